@@ -0,0 +1,95 @@
+package actionstest
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+)
+
+// Command is a workflow command captured from the code under test
+type Command struct {
+	Name       string
+	Properties map[string]string
+	Message    string
+}
+
+// Stdout returns everything written to the command output, including lines that are not commands
+func (rt *Runtime) Stdout() string {
+	return rt.commands.String()
+}
+
+// Commands returns the workflow commands written so far, with properties and messages unescaped.
+// Commands issued while processing was stopped with stop-commands are not returned, as on the runner.
+func (rt *Runtime) Commands() []Command {
+	var commands []Command
+	stopToken := ""
+	for _, line := range strings.Split(rt.commands.String(), "\n") {
+		cmd, ok := parseCommand(line)
+		if !ok {
+			continue
+		}
+		if stopToken != "" {
+			if cmd.Name == stopToken {
+				stopToken = ""
+			}
+			continue
+		}
+		if cmd.Name == "stop-commands" {
+			stopToken = cmd.Message
+			continue
+		}
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// Annotations returns the error, warning and notice commands
+func (rt *Runtime) Annotations() []Command {
+	var annotations []Command
+	for _, cmd := range rt.Commands() {
+		switch cmd.Name {
+		case actions.AnnotationError, actions.AnnotationWarning, actions.AnnotationNotice:
+			annotations = append(annotations, cmd)
+		}
+	}
+	return annotations
+}
+
+// Masks returns the values registered with add-mask
+func (rt *Runtime) Masks() []string {
+	var masks []string
+	for _, cmd := range rt.Commands() {
+		if cmd.Name == "add-mask" {
+			masks = append(masks, cmd.Message)
+		}
+	}
+	return masks
+}
+
+// parseCommand parses a "::name key=value,...::message" line
+func parseCommand(line string) (Command, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), "::")
+	if !ok {
+		return Command{}, false
+	}
+	head, message, ok := strings.Cut(rest, "::")
+	if !ok {
+		return Command{}, false
+	}
+	name, props, _ := strings.Cut(head, " ")
+	cmd := Command{Name: name, Message: unescape(message)}
+	if props != "" {
+		cmd.Properties = map[string]string{}
+		for _, prop := range strings.Split(props, ",") {
+			key, value, _ := strings.Cut(prop, "=")
+			cmd.Properties[key] = unescape(value)
+		}
+	}
+	return cmd, true
+}
+
+var unescaper = strings.NewReplacer("%0D", "\r", "%0A", "\n", "%3A", ":", "%2C", ",", "%25", "%")
+
+func unescape(s string) string {
+	return unescaper.Replace(s)
+}
@@ -0,0 +1,329 @@
+// Package actionstest emulates the GitHub Actions runtime so that code using pkg/actions can be tested
+// outside a runner. New sets the GITHUB_* environment, writes the event payload and creates the file
+// command files in a temporary directory; the Runtime then reads back what the code under test produced.
+package actionstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+)
+
+// Default values of the emulated runtime
+const (
+	DefaultRepository = "octo-org/octo-repo"
+	DefaultServerURL  = "https://github.com"
+	DefaultSHA        = "0123456789abcdef0123456789abcdef01234567"
+	DefaultActor      = "octocat"
+	DefaultRunID      = 1
+)
+
+// Options configures the emulated runtime. Zero values are replaced with defaults.
+type Options struct {
+	EventName  string // defaults to "push"
+	Payload    any    // event payload; []byte is written as is, other values are marshaled to JSON
+	Fixture    string // path of an event payload file, used when Payload is nil
+	Repository string // "owner/repo"
+	ServerURL  string
+	Ref        string // defaults to refs/heads/main, or refs/pull/<n>/merge for pull request events
+	SHA        string
+	Workflow   string // workflow file path, defaults to .github/workflows/ci.yml
+	Job        string // defaults to "test"
+	Actor      string
+	RunID      int64
+	Env        map[string]string // extra environment variables, applied last
+}
+
+// Runtime is an emulated GitHub Actions runtime for one test
+type Runtime struct {
+	Dir      string // temporary directory holding the event payload and file command files
+	t        testing.TB
+	commands *bytes.Buffer
+}
+
+// New sets up the emulated runtime for the duration of the test. It uses t.Setenv, so tests using it
+// cannot run in parallel. Workflow commands written through pkg/actions are captured instead of
+// being printed.
+func New(t testing.TB, opts *Options) *Runtime {
+	t.Helper()
+	if opts == nil {
+		opts = &Options{}
+	}
+	o := *opts
+	if o.EventName == "" {
+		o.EventName = actions.EventPush
+	}
+	if o.Repository == "" {
+		o.Repository = DefaultRepository
+	}
+	if o.ServerURL == "" {
+		o.ServerURL = DefaultServerURL
+	}
+	if o.SHA == "" {
+		o.SHA = DefaultSHA
+	}
+	if o.Workflow == "" {
+		o.Workflow = ".github/workflows/ci.yml"
+	}
+	if o.Job == "" {
+		o.Job = "test"
+	}
+	if o.Actor == "" {
+		o.Actor = DefaultActor
+	}
+	if o.RunID == 0 {
+		o.RunID = DefaultRunID
+	}
+	if o.Ref == "" {
+		o.Ref = "refs/heads/main"
+		if isPullRequestEvent(o.EventName) {
+			o.Ref = "refs/pull/1/merge"
+		}
+	}
+
+	rt := &Runtime{Dir: t.TempDir(), t: t, commands: &bytes.Buffer{}}
+	payload := rt.payload(&o)
+	eventPath := rt.writeFile("event.json", payload)
+
+	owner, _, _ := strings.Cut(o.Repository, "/")
+	env := map[string]string{
+		"CI":                      "true",
+		"GITHUB_ACTIONS":          "true",
+		"GITHUB_EVENT_NAME":       o.EventName,
+		"GITHUB_EVENT_PATH":       eventPath,
+		"GITHUB_OUTPUT":           rt.writeFile("output", nil),
+		"GITHUB_ENV":              rt.writeFile("env", nil),
+		"GITHUB_PATH":             rt.writeFile("path", nil),
+		"GITHUB_STATE":            rt.writeFile("state", nil),
+		"GITHUB_STEP_SUMMARY":     rt.writeFile("step_summary", nil),
+		"GITHUB_REPOSITORY":       o.Repository,
+		"GITHUB_REPOSITORY_OWNER": owner,
+		"GITHUB_SERVER_URL":       o.ServerURL,
+		"GITHUB_API_URL":          apiURL(o.ServerURL),
+		"GITHUB_SHA":              o.SHA,
+		"GITHUB_REF":              o.Ref,
+		"GITHUB_REF_NAME":         refName(o.Ref),
+		"GITHUB_HEAD_REF":         "",
+		"GITHUB_BASE_REF":         "",
+		"GITHUB_WORKFLOW":         strings.TrimSuffix(filepath.Base(o.Workflow), filepath.Ext(o.Workflow)),
+		"GITHUB_WORKFLOW_REF":     o.Repository + "/" + o.Workflow + "@" + o.Ref,
+		"GITHUB_JOB":              o.Job,
+		"GITHUB_ACTION":           "__run",
+		"GITHUB_ACTOR":            o.Actor,
+		"GITHUB_RUN_ID":           strconv.FormatInt(o.RunID, 10),
+		"GITHUB_RUN_NUMBER":       "1",
+		"GITHUB_RUN_ATTEMPT":      "1",
+		"GITHUB_WORKSPACE":        rt.mkdir("workspace"),
+		"RUNNER_TEMP":             rt.mkdir("runner_temp"),
+		"RUNNER_OS":               "Linux",
+		"RUNNER_DEBUG":            "",
+	}
+	if isPullRequestEvent(o.EventName) {
+		env["GITHUB_HEAD_REF"] = "feature"
+		env["GITHUB_BASE_REF"] = "main"
+	}
+	for k, v := range o.Env {
+		env[k] = v
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	prev := actions.SetCommandOutput(rt.commands)
+	t.Cleanup(func() { actions.SetCommandOutput(prev) })
+	return rt
+}
+
+func isPullRequestEvent(eventName string) bool {
+	return strings.HasPrefix(eventName, actions.EventPullRequest)
+}
+
+func apiURL(serverURL string) string {
+	if serverURL == DefaultServerURL {
+		return "https://api.github.com"
+	}
+	return strings.TrimSuffix(serverURL, "/") + "/api/v3"
+}
+
+func refName(ref string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/pull/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+// payload returns the event payload from the options, or a minimal payload for the event
+func (rt *Runtime) payload(o *Options) []byte {
+	rt.t.Helper()
+	switch p := o.Payload.(type) {
+	case []byte:
+		return p
+	case nil:
+		if o.Fixture != "" {
+			data, err := os.ReadFile(o.Fixture)
+			if err != nil {
+				rt.t.Fatalf("failed to read event fixture: %v", err)
+			}
+			return data
+		}
+		return rt.marshal(defaultPayload(o))
+	default:
+		return rt.marshal(p)
+	}
+}
+
+// defaultPayload returns a minimal payload with the fields the effective helpers in pkg/actions read
+func defaultPayload(o *Options) map[string]any {
+	owner, name, _ := strings.Cut(o.Repository, "/")
+	payload := map[string]any{
+		"repository": map[string]any{
+			"name":      name,
+			"full_name": o.Repository,
+			"owner":     map[string]any{"login": owner},
+		},
+		"sender": map[string]any{"login": o.Actor},
+	}
+	switch {
+	case isPullRequestEvent(o.EventName):
+		payload["number"] = 1
+		payload["pull_request"] = map[string]any{
+			"number": 1,
+			"head":   map[string]any{"ref": "feature", "sha": o.SHA},
+			"base":   map[string]any{"ref": "main"},
+		}
+	case o.EventName == actions.EventPush:
+		payload["ref"] = o.Ref
+		payload["after"] = o.SHA
+	case o.EventName == actions.EventWorkflowDispatch:
+		payload["ref"] = o.Ref
+		payload["inputs"] = map[string]any{}
+	case o.EventName == actions.EventMergeGroup:
+		payload["merge_group"] = map[string]any{"head_sha": o.SHA, "base_ref": "refs/heads/main"}
+	}
+	return payload
+}
+
+func (rt *Runtime) marshal(v any) []byte {
+	rt.t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		rt.t.Fatalf("failed to marshal event payload: %v", err)
+	}
+	return data
+}
+
+func (rt *Runtime) writeFile(name string, data []byte) string {
+	rt.t.Helper()
+	path := filepath.Join(rt.Dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		rt.t.Fatalf("failed to create %s: %v", name, err)
+	}
+	return path
+}
+
+func (rt *Runtime) mkdir(name string) string {
+	rt.t.Helper()
+	path := filepath.Join(rt.Dir, name)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		rt.t.Fatalf("failed to create %s: %v", name, err)
+	}
+	return path
+}
+
+func (rt *Runtime) readFile(envName string) string {
+	rt.t.Helper()
+	data, err := os.ReadFile(filepath.Join(rt.Dir, fileNames[envName]))
+	if err != nil {
+		rt.t.Fatalf("failed to read %s: %v", envName, err)
+	}
+	return string(data)
+}
+
+var fileNames = map[string]string{
+	"GITHUB_OUTPUT":       "output",
+	"GITHUB_ENV":          "env",
+	"GITHUB_PATH":         "path",
+	"GITHUB_STATE":        "state",
+	"GITHUB_STEP_SUMMARY": "step_summary",
+}
+
+// Outputs returns the step outputs written to GITHUB_OUTPUT
+func (rt *Runtime) Outputs() map[string]string {
+	rt.t.Helper()
+	return rt.keyValues("GITHUB_OUTPUT")
+}
+
+// Output returns a single step output, or "" if it was not set
+func (rt *Runtime) Output(name string) string {
+	rt.t.Helper()
+	return rt.Outputs()[name]
+}
+
+// Env returns the environment variables written to GITHUB_ENV
+func (rt *Runtime) Env() map[string]string {
+	rt.t.Helper()
+	return rt.keyValues("GITHUB_ENV")
+}
+
+// State returns the state written to GITHUB_STATE
+func (rt *Runtime) State() map[string]string {
+	rt.t.Helper()
+	return rt.keyValues("GITHUB_STATE")
+}
+
+// Paths returns the directories written to GITHUB_PATH in order
+func (rt *Runtime) Paths() []string {
+	rt.t.Helper()
+	var paths []string
+	for _, line := range strings.Split(rt.readFile("GITHUB_PATH"), "\n") {
+		if line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths
+}
+
+// Summary returns the markdown written to GITHUB_STEP_SUMMARY
+func (rt *Runtime) Summary() string {
+	rt.t.Helper()
+	return rt.readFile("GITHUB_STEP_SUMMARY")
+}
+
+// keyValues parses a file command file in the name=value and name<<delimiter formats.
+// Later values of the same name win, as they do on the runner.
+func (rt *Runtime) keyValues(envName string) map[string]string {
+	rt.t.Helper()
+	values := map[string]string{}
+	lines := strings.Split(rt.readFile(envName), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		if name, delimiter, ok := strings.Cut(line, "<<"); ok && !strings.Contains(name, "=") {
+			var body []string
+			for i++; i < len(lines) && lines[i] != delimiter; i++ {
+				body = append(body, lines[i])
+			}
+			if i == len(lines) {
+				rt.t.Fatalf("%s: missing delimiter %q for %s", envName, delimiter, name)
+			}
+			values[name] = strings.Join(body, "\n")
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			rt.t.Fatalf("%s: invalid line %q", envName, line)
+		}
+		values[name] = value
+	}
+	return values
+}
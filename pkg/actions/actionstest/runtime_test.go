@@ -0,0 +1,115 @@
+package actionstest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+)
+
+func TestRuntime_FileCommands(t *testing.T) {
+	rt := New(t, nil)
+
+	if err := actions.Output("result", "ok"); err != nil {
+		t.Fatal(err)
+	}
+	if err := actions.Output("report", "line1\nline2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := actions.SetEnv("MODE", "release"); err != nil {
+		t.Fatal(err)
+	}
+	if err := actions.SaveState("pid", "42"); err != nil {
+		t.Fatal(err)
+	}
+	if err := actions.AddPath("/opt/bin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := actions.NewSummary().AddHeading("Done", 1).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rt.Output("result"); got != "ok" {
+		t.Errorf("output result = %q", got)
+	}
+	if got := rt.Output("report"); got != "line1\nline2" {
+		t.Errorf("output report = %q", got)
+	}
+	if got := rt.Env()["MODE"]; got != "release" {
+		t.Errorf("env MODE = %q", got)
+	}
+	if got := rt.State()["pid"]; got != "42" {
+		t.Errorf("state pid = %q", got)
+	}
+	if got := rt.Paths(); len(got) != 1 || got[0] != "/opt/bin" {
+		t.Errorf("paths = %q", got)
+	}
+	if got := rt.Summary(); got != "# Done\n\n" {
+		t.Errorf("summary = %q", got)
+	}
+}
+
+func TestRuntime_Commands(t *testing.T) {
+	rt := New(t, nil)
+
+	_ = actions.Warning("deprecated, use v2", &actions.AnnotationProperties{File: "a.go", Line: 3, Title: "a: b"})
+	_ = actions.AddMask("s3cret")
+	_ = actions.WithoutCommands(func() error {
+		return actions.Error("ignored", nil)
+	})
+	_ = actions.Notice("done", nil)
+
+	annotations := rt.Annotations()
+	if len(annotations) != 2 {
+		t.Fatalf("annotations = %+v", annotations)
+	}
+	warning := annotations[0]
+	if warning.Name != "warning" || warning.Message != "deprecated, use v2" || warning.Properties["title"] != "a: b" || warning.Properties["line"] != "3" {
+		t.Errorf("warning = %+v", warning)
+	}
+	if masks := rt.Masks(); len(masks) != 1 || masks[0] != "s3cret" {
+		t.Errorf("masks = %q", masks)
+	}
+}
+
+func TestRuntime_Context(t *testing.T) {
+	New(t, &Options{EventName: actions.EventPullRequest, Repository: "octo/app", RunID: 99})
+
+	if got := actions.GetRepositoryFullName(); got != "octo/app" {
+		t.Errorf("repository = %q", got)
+	}
+	if got := actions.GetWorkflowFilePath(); got != ".github/workflows/ci.yml" {
+		t.Errorf("workflow file path = %q", got)
+	}
+	if got := actions.GetRunURL(); got != "https://github.com/octo/app/actions/runs/99" {
+		t.Errorf("run url = %q", got)
+	}
+	if !actions.IsRunsOn() || !actions.IsPullRequestEvent() {
+		t.Error("expected a pull request event in GitHub Actions")
+	}
+	event, err := actions.GetEventAs[*github.PullRequestEvent]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actions.EffectivePullRequest(event).GetNumber() != 1 || actions.EffectiveBaseRef(event) != "main" {
+		t.Errorf("unexpected pull request event: %+v", event)
+	}
+}
+
+func TestRuntime_Fixture(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "merge_group.json")
+	if err := os.WriteFile(fixture, []byte(`{"merge_group":{"head_sha":"fixture-sha"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	New(t, &Options{EventName: actions.EventMergeGroup, Fixture: fixture})
+
+	event, err := actions.GetEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := actions.EffectiveHeadSHA(event); got != "fixture-sha" {
+		t.Errorf("head sha = %q", got)
+	}
+}
@@ -0,0 +1,215 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// commandOutput is where workflow commands are written; the runner reads them from stdout
+var commandOutput io.Writer = os.Stdout
+
+// SetCommandOutput sets the writer workflow commands are written to and returns the previous one
+func SetCommandOutput(w io.Writer) io.Writer {
+	prev := commandOutput
+	commandOutput = w
+	return prev
+}
+
+// Annotation levels
+const (
+	AnnotationError   = "error"
+	AnnotationWarning = "warning"
+	AnnotationNotice  = "notice"
+)
+
+// AnnotationProperties locates an annotation in a file. Zero values are omitted.
+type AnnotationProperties struct {
+	Title     string
+	File      string
+	Line      int
+	EndLine   int
+	Column    int
+	EndColumn int
+}
+
+// Map returns the workflow command properties of the annotation
+func (p *AnnotationProperties) Map() map[string]string {
+	props := map[string]string{}
+	if p == nil {
+		return props
+	}
+	set := func(key, value string) {
+		if value != "" {
+			props[key] = value
+		}
+	}
+	setInt := func(key string, value int) {
+		if value > 0 {
+			props[key] = fmt.Sprint(value)
+		}
+	}
+	set("title", p.Title)
+	set("file", p.File)
+	setInt("line", p.Line)
+	setInt("endLine", p.EndLine)
+	setInt("col", p.Column)
+	setInt("endColumn", p.EndColumn)
+	return props
+}
+
+// IssueCommand writes a workflow command, escaping the properties and the message so that
+// multiline values and separators in them cannot terminate the command early
+func IssueCommand(command string, props map[string]string, message string) error {
+	_, err := fmt.Fprintln(commandOutput, FormatCommand(command, props, message))
+	return err
+}
+
+// FormatCommand formats a workflow command, e.g. "::warning file=a.go,line=1::message"
+func FormatCommand(command string, props map[string]string, message string) string {
+	var b strings.Builder
+	b.WriteString("::")
+	b.WriteString(command)
+	if len(props) > 0 {
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i == 0 {
+				b.WriteString(" ")
+			} else {
+				b.WriteString(",")
+			}
+			b.WriteString(k)
+			b.WriteString("=")
+			b.WriteString(escapeProperty(props[k]))
+		}
+	}
+	b.WriteString("::")
+	b.WriteString(escapeData(message))
+	return b.String()
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// Annotate writes an error, warning or notice annotation
+func Annotate(level string, message string, props *AnnotationProperties) error {
+	return IssueCommand(level, props.Map(), message)
+}
+
+// Error writes an error annotation. props may be nil.
+func Error(message string, props *AnnotationProperties) error {
+	return Annotate(AnnotationError, message, props)
+}
+
+// Warning writes a warning annotation. props may be nil.
+func Warning(message string, props *AnnotationProperties) error {
+	return Annotate(AnnotationWarning, message, props)
+}
+
+// Notice writes a notice annotation. props may be nil.
+func Notice(message string, props *AnnotationProperties) error {
+	return Annotate(AnnotationNotice, message, props)
+}
+
+// Debug writes a debug message, shown when step debug logging is enabled
+func Debug(message string) error {
+	return IssueCommand("debug", nil, message)
+}
+
+// IsDebug returns true if step debug logging is enabled
+func IsDebug() bool {
+	return os.Getenv("RUNNER_DEBUG") == "1"
+}
+
+// AddMask masks value in the log. Each line of a multiline value is masked separately as well,
+// since the runner matches masks line by line.
+func AddMask(value string) error {
+	if value == "" {
+		return nil
+	}
+	if err := IssueCommand("add-mask", nil, value); err != nil {
+		return err
+	}
+	if !strings.ContainsAny(value, "\r\n") {
+		return nil
+	}
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if err := IssueCommand("add-mask", nil, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartGroup starts a collapsible group in the log
+func StartGroup(name string) error {
+	return IssueCommand("group", nil, name)
+}
+
+// EndGroup ends the group started by StartGroup
+func EndGroup() error {
+	return IssueCommand("endgroup", nil, "")
+}
+
+// Group runs fn inside a collapsible log group
+func Group(name string, fn func() error) error {
+	if err := StartGroup(name); err != nil {
+		return err
+	}
+	fnErr := fn()
+	if err := EndGroup(); err != nil && fnErr == nil {
+		return err
+	}
+	return fnErr
+}
+
+// SetCommandEcho enables or disables echoing of workflow commands in the log
+func SetCommandEcho(enabled bool) error {
+	if enabled {
+		return IssueCommand("echo", nil, "on")
+	}
+	return IssueCommand("echo", nil, "off")
+}
+
+// StopCommands stops processing of workflow commands until ResumeCommands is called with the returned token.
+// Use it before printing untrusted text that could contain workflow commands.
+func StopCommands() (string, error) {
+	token := "ghatoken_" + randomDelimiter()
+	if err := IssueCommand("stop-commands", nil, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ResumeCommands resumes processing of workflow commands stopped by StopCommands
+func ResumeCommands(token string) error {
+	_, err := fmt.Fprintf(commandOutput, "::%s::\n", token)
+	return err
+}
+
+// WithoutCommands runs fn with workflow command processing stopped
+func WithoutCommands(fn func() error) error {
+	token, err := StopCommands()
+	if err != nil {
+		return err
+	}
+	fnErr := fn()
+	if err := ResumeCommands(token); err != nil && fnErr == nil {
+		return err
+	}
+	return fnErr
+}
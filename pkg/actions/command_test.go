@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func captureCommands(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := SetCommandOutput(&buf)
+	t.Cleanup(func() { SetCommandOutput(prev) })
+	return &buf
+}
+
+func TestFormatCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		props   map[string]string
+		message string
+		want    string
+	}{
+		{"plain", "debug", nil, "hello", "::debug::hello"},
+		{"multiline message", "error", nil, "100%\nfailed", "::error::100%25%0Afailed"},
+		{"properties", "warning", map[string]string{"line": "3", "file": "a:b,c.go"}, "msg", "::warning file=a%3Ab%2Cc.go,line=3::msg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCommand(tt.command, tt.props, tt.message); got != tt.want {
+				t.Errorf("FormatCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	buf := captureCommands(t)
+	if err := Error("boom", &AnnotationProperties{Title: "Build", File: "main.go", Line: 10, Column: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Notice("done", nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "::error col=2,file=main.go,line=10,title=Build::boom\n::notice::done\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestAddMask(t *testing.T) {
+	buf := captureCommands(t)
+	if err := AddMask("secret\nvalue"); err != nil {
+		t.Fatal(err)
+	}
+	want := "::add-mask::secret%0Avalue\n::add-mask::secret\n::add-mask::value\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	buf := captureCommands(t)
+	errFn := errors.New("failed")
+	err := Group("Install", func() error {
+		_, _ = buf.WriteString("installing\n")
+		return errFn
+	})
+	if !errors.Is(err, errFn) {
+		t.Errorf("Group() error = %v, want %v", err, errFn)
+	}
+	if want := "::group::Install\ninstalling\n::endgroup::\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWithoutCommands(t *testing.T) {
+	buf := captureCommands(t)
+	err := WithoutCommands(func() error {
+		_, _ = buf.WriteString("::error::untrusted\n")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	token := strings.TrimPrefix(lines[0], "::stop-commands::")
+	if token == lines[0] || lines[2] != "::"+token+"::" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
@@ -56,8 +56,8 @@ type EventPayload struct {
 	CheckRun          *github.CheckRun           `json:"check_run,omitempty"`
 	CheckSuite        *github.CheckSuite         `json:"check_suite,omitempty"`
 	Package           *github.Package            `json:"package,omitempty"`
-	Discussion        any                        `json:"discussion,omitempty"`
-	DiscussionComment any                        `json:"discussion_comment,omitempty"`
+	Discussion        *github.Discussion         `json:"discussion,omitempty"`
+	DiscussionComment *github.CommentDiscussion  `json:"discussion_comment,omitempty"`
 }
 
 // GetEventPayload reads and parses the complete GitHub Actions event payload
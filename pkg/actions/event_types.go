@@ -0,0 +1,198 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v90/github"
+)
+
+// Event names that are not covered by the constants in event.go
+const (
+	EventBranchProtectionRule    = "branch_protection_rule"
+	EventGollum                  = "gollum"
+	EventMergeGroup              = "merge_group"
+	EventPullRequestReviewThread = "pull_request_review_thread"
+	EventRegistryPackage         = "registry_package"
+	EventStatus                  = "status"
+	EventWorkflowCall            = "workflow_call"
+	EventWorkflowJob             = "workflow_job"
+)
+
+// ScheduleEvent is the payload of a schedule event, which has no webhook counterpart
+type ScheduleEvent struct {
+	Schedule   string             `json:"schedule,omitempty"` // the cron expression that triggered the run
+	Repository *github.Repository `json:"repository,omitempty"`
+	Sender     *github.User       `json:"sender,omitempty"`
+}
+
+// ParseEvent parses an event payload into the go-github event type for eventName,
+// e.g. *github.PullRequestEvent for "pull_request" and *github.MergeGroupEvent for "merge_group".
+// A "schedule" payload is parsed into *ScheduleEvent, and events without a typed counterpart
+// (such as classic project events) into the loose *EventPayload.
+func ParseEvent(eventName string, data []byte) (any, error) {
+	switch eventName {
+	case "":
+		return nil, fmt.Errorf("event name is empty")
+	case EventSchedule:
+		var event ScheduleEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode %s event payload: %w", eventName, err)
+		}
+		return &event, nil
+	}
+	if isKnownWebHookEvent(eventName) {
+		event, err := github.ParseWebHook(eventName, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s event payload: %w", eventName, err)
+		}
+		return event, nil
+	}
+	var payload EventPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event payload: %w", eventName, err)
+	}
+	return &payload, nil
+}
+
+// isKnownWebHookEvent returns true if go-github has a typed payload for the event name
+func isKnownWebHookEvent(eventName string) bool {
+	_, err := github.ParseWebHook(eventName, []byte("{}"))
+	return err == nil
+}
+
+// GetEvent reads the payload at GITHUB_EVENT_PATH and parses it for GITHUB_EVENT_NAME with ParseEvent
+func GetEvent() (any, error) {
+	path := GetEventJsonPath()
+	if path == "" {
+		return nil, fmt.Errorf("GITHUB_EVENT_PATH is not set")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return ParseEvent(GetEventName(), data)
+}
+
+// GetEventAs returns the current event as T, e.g. GetEventAs[*github.WorkflowRunEvent]().
+// It returns an error when the triggering event has a different type.
+func GetEventAs[T any]() (T, error) {
+	var zero T
+	event, err := GetEvent()
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := event.(T)
+	if !ok {
+		return zero, fmt.Errorf("event %q is %T, not %T", GetEventName(), event, zero)
+	}
+	return typed, nil
+}
+
+// EffectivePullRequest returns the pull request an event is about. Besides pull request events this covers
+// comments on pull requests and the first associated pull request of workflow_run, check_run and check_suite
+// events; those may only carry the number, head and base. It returns nil when there is no pull request.
+func EffectivePullRequest(event any) *github.PullRequest {
+	switch e := event.(type) {
+	case *github.PullRequestEvent:
+		return e.GetPullRequest()
+	case *github.PullRequestTargetEvent:
+		return e.GetPullRequest()
+	case *github.PullRequestReviewEvent:
+		return e.GetPullRequest()
+	case *github.PullRequestReviewCommentEvent:
+		return e.GetPullRequest()
+	case *github.PullRequestReviewThreadEvent:
+		return e.GetPullRequest()
+	case *github.IssueCommentEvent:
+		if !e.GetIssue().IsPullRequest() {
+			return nil
+		}
+		return &github.PullRequest{
+			Number:  e.GetIssue().Number,
+			Title:   e.GetIssue().Title,
+			HTMLURL: e.GetIssue().GetPullRequestLinks().HTMLURL,
+			URL:     e.GetIssue().GetPullRequestLinks().URL,
+		}
+	case *github.WorkflowRunEvent:
+		return firstPullRequest(e.GetWorkflowRun().PullRequests)
+	case *github.CheckRunEvent:
+		return firstPullRequest(e.GetCheckRun().PullRequests)
+	case *github.CheckSuiteEvent:
+		return firstPullRequest(e.GetCheckSuite().PullRequests)
+	case *EventPayload:
+		return e.PullRequest
+	}
+	return nil
+}
+
+func firstPullRequest(prs []*github.PullRequest) *github.PullRequest {
+	if len(prs) == 0 {
+		return nil
+	}
+	return prs[0]
+}
+
+// EffectiveHeadSHA returns the commit an event is about: the pull request head, the pushed commit,
+// the merge group, workflow run, check or deployment commit. It falls back to GITHUB_SHA.
+func EffectiveHeadSHA(event any) string {
+	sha := ""
+	switch e := event.(type) {
+	case *github.PushEvent:
+		sha = e.GetAfter()
+	case *github.MergeGroupEvent:
+		sha = e.GetMergeGroup().GetHeadSHA()
+	case *github.WorkflowRunEvent:
+		sha = e.GetWorkflowRun().GetHeadSHA()
+	case *github.WorkflowJobEvent:
+		sha = e.GetWorkflowJob().GetHeadSHA()
+	case *github.CheckRunEvent:
+		sha = e.GetCheckRun().GetHeadSHA()
+	case *github.CheckSuiteEvent:
+		sha = e.GetCheckSuite().GetHeadSHA()
+	case *github.DeploymentEvent:
+		sha = e.GetDeployment().GetSHA()
+	case *github.DeploymentStatusEvent:
+		sha = e.GetDeployment().GetSHA()
+	case *github.StatusEvent:
+		sha = e.GetSHA()
+	case *EventPayload:
+		sha = e.After
+	}
+	if sha == "" {
+		sha = EffectivePullRequest(event).GetHead().GetSHA()
+	}
+	if sha == "" {
+		sha = GetSHA()
+	}
+	return sha
+}
+
+// EffectiveBaseRef returns the branch an event's changes target, without the refs/heads/ prefix:
+// the pull request base or the merge group base. It falls back to GITHUB_BASE_REF and returns ""
+// for events that have no base, e.g. push.
+func EffectiveBaseRef(event any) string {
+	ref := ""
+	if e, ok := event.(*github.MergeGroupEvent); ok {
+		ref = e.GetMergeGroup().GetBaseRef()
+	}
+	if ref == "" {
+		ref = EffectivePullRequest(event).GetBase().GetRef()
+	}
+	if ref == "" {
+		ref = os.Getenv("GITHUB_BASE_REF")
+	}
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
+
+// IsMergeGroupEvent returns true if the current event is a merge_group event
+func IsMergeGroupEvent() bool {
+	return GetEventName() == EventMergeGroup
+}
+
+// IsWorkflowRunEvent returns true if the current event is a workflow_run event
+func IsWorkflowRunEvent() bool {
+	return GetEventName() == EventWorkflowRun
+}
@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventName string
+		payload   string
+		wantType  string
+	}{
+		{"merge group", EventMergeGroup, `{"merge_group":{"head_sha":"abc"}}`, "*github.MergeGroupEvent"},
+		{"pull request target", EventPullRequestTarget, `{"number":1}`, "*github.PullRequestTargetEvent"},
+		{"discussion comment", EventDiscussionComment, `{"comment":{"body":"hi"}}`, "*github.DiscussionCommentEvent"},
+		{"schedule", EventSchedule, `{"schedule":"0 0 * * *"}`, "*actions.ScheduleEvent"},
+		{"untyped", EventProjectCard, `{"action":"moved"}`, "*actions.EventPayload"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent(tt.eventName, []byte(tt.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprintf("%T", event); got != tt.wantType {
+				t.Errorf("ParseEvent() type = %s, want %s", got, tt.wantType)
+			}
+		})
+	}
+
+	if _, err := ParseEvent(EventPush, []byte(`{"ref":1}`)); err == nil {
+		t.Error("expected error for a malformed payload")
+	}
+	if _, err := ParseEvent("", []byte(`{}`)); err == nil {
+		t.Error("expected error for an empty event name")
+	}
+}
+
+func TestGetEventAs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(path, []byte(`{"workflow_run":{"head_sha":"abc","pull_requests":[{"number":7,"base":{"ref":"main"}}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_EVENT_PATH", path)
+	t.Setenv("GITHUB_EVENT_NAME", EventWorkflowRun)
+
+	event, err := GetEventAs[*github.WorkflowRunEvent]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.GetWorkflowRun().GetHeadSHA() != "abc" {
+		t.Errorf("head sha = %q", event.GetWorkflowRun().GetHeadSHA())
+	}
+	if _, err := GetEventAs[*github.PushEvent](); err == nil {
+		t.Error("expected error for a mismatched event type")
+	}
+}
+
+func TestEffectiveHelpers(t *testing.T) {
+	t.Setenv("GITHUB_SHA", "env-sha")
+	t.Setenv("GITHUB_BASE_REF", "")
+
+	pr := &github.PullRequest{
+		Number: github.Ptr(3),
+		Head:   &github.PullRequestBranch{SHA: github.Ptr("pr-head")},
+		Base:   &github.PullRequestBranch{Ref: github.Ptr("develop")},
+	}
+	tests := []struct {
+		name     string
+		event    any
+		wantPR   int
+		wantSHA  string
+		wantBase string
+	}{
+		{"pull request", &github.PullRequestEvent{PullRequest: pr}, 3, "pr-head", "develop"},
+		{"push", &github.PushEvent{After: github.Ptr("pushed")}, 0, "pushed", ""},
+		{"merge group", &github.MergeGroupEvent{MergeGroup: &github.MergeGroup{HeadSHA: github.Ptr("mg"), BaseRef: github.Ptr("refs/heads/main")}}, 0, "mg", "main"},
+		{"workflow run", &github.WorkflowRunEvent{WorkflowRun: &github.WorkflowRun{HeadSHA: github.Ptr("run"), PullRequests: []*github.PullRequest{pr}}}, 3, "run", "develop"},
+		{"issue comment on pr", &github.IssueCommentEvent{Issue: &github.Issue{Number: github.Ptr(9), PullRequestLinks: &github.PullRequestLinks{}}}, 9, "env-sha", ""},
+		{"schedule", &ScheduleEvent{}, 0, "env-sha", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EffectivePullRequest(tt.event).GetNumber(); got != tt.wantPR {
+				t.Errorf("EffectivePullRequest() number = %d, want %d", got, tt.wantPR)
+			}
+			if got := EffectiveHeadSHA(tt.event); got != tt.wantSHA {
+				t.Errorf("EffectiveHeadSHA() = %q, want %q", got, tt.wantSHA)
+			}
+			if got := EffectiveBaseRef(tt.event); got != tt.wantBase {
+				t.Errorf("EffectiveBaseRef() = %q, want %q", got, tt.wantBase)
+			}
+		})
+	}
+}
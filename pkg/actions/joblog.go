@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+// NewJobLog creates a JobLog for a single job whose log is fetched as one text, as returned by the
+// "download job logs" API. fetch is called once, when the first step log is read, and the content is
+// split into step logs by SplitJobLogContent.
+func NewJobLog(jobName string, steps []*github.TaskStep, fetch func() ([]byte, error)) *JobLog {
+	var (
+		once    sync.Once
+		split   map[int][]byte
+		loadErr error
+	)
+	load := func() {
+		content, err := fetch()
+		if err != nil {
+			loadErr = err
+			return
+		}
+		split = SplitJobLogContent(steps, content)
+	}
+
+	jobLog := &JobLog{
+		JobName:  jobName,
+		StepLogs: make(map[int]*StepLog, len(steps)),
+	}
+	for _, step := range steps {
+		number := int(step.GetNumber())
+		jobLog.StepLogs[number] = &StepLog{
+			StepNumber: number,
+			StepName:   step.GetName(),
+			FilePath:   jobName + "/" + step.GetName(),
+			load: func() ([]byte, error) {
+				once.Do(load)
+				if loadErr != nil {
+					return nil, loadErr
+				}
+				return split[number], nil
+			},
+		}
+	}
+	return jobLog
+}
+
+// SplitJobLogContent splits the log of a whole job into step logs keyed by step number.
+// The job log has no step boundaries, so each timestamped line is assigned to the last step that started
+// at or before it; lines without a timestamp follow the preceding line. The step start times have second
+// precision, so lines logged within the second a step starts may be attributed to that step.
+func SplitJobLogContent(steps []*github.TaskStep, content []byte) map[int][]byte {
+	type boundary struct {
+		number  int
+		started time.Time
+	}
+	var boundaries []boundary
+	for _, step := range steps {
+		if step.StartedAt == nil {
+			continue
+		}
+		boundaries = append(boundaries, boundary{number: int(step.GetNumber()), started: step.GetStartedAt().Time})
+	}
+
+	result := map[int][]byte{}
+	if len(boundaries) == 0 {
+		return result
+	}
+	current := boundaries[0].number
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		text := bytes.TrimPrefix(line, []byte("\xef\xbb\xbf"))
+		if ts, _, ok := bytes.Cut(text, []byte(" ")); ok {
+			if t, err := time.Parse(time.RFC3339Nano, string(ts)); err == nil {
+				t = t.Truncate(time.Second)
+				for _, b := range boundaries {
+					if !b.started.After(t) {
+						current = b.number
+					}
+				}
+			}
+		}
+		result[current] = append(result[current], text...)
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func testTaskStep(number int64, name string, started string) *github.TaskStep {
+	step := &github.TaskStep{Number: github.Ptr(number), Name: github.Ptr(name)}
+	if started != "" {
+		t, _ := time.Parse(time.RFC3339, started)
+		step.StartedAt = &github.Timestamp{Time: t}
+	}
+	return step
+}
+
+var testJobSteps = []*github.TaskStep{
+	testTaskStep(1, "Set up job", "2024-01-02T03:04:05Z"),
+	testTaskStep(2, "Test", "2024-01-02T03:04:07Z"),
+	testTaskStep(3, "Skipped", ""),
+}
+
+const testJobContent = "\xef\xbb\xbf2024-01-02T03:04:05.1000000Z Current runner version\n" +
+	"2024-01-02T03:04:06.9000000Z Complete job name\n" +
+	"2024-01-02T03:04:07.2000000Z ##[group]Run go test\n" +
+	"continued line\n" +
+	"2024-01-02T03:04:08.0000000Z ##[error]Process completed with exit code 1.\n"
+
+func TestSplitJobLogContent(t *testing.T) {
+	split := SplitJobLogContent(testJobSteps, []byte(testJobContent))
+	want := map[int]string{
+		1: "2024-01-02T03:04:05.1000000Z Current runner version\n2024-01-02T03:04:06.9000000Z Complete job name\n",
+		2: "2024-01-02T03:04:07.2000000Z ##[group]Run go test\ncontinued line\n2024-01-02T03:04:08.0000000Z ##[error]Process completed with exit code 1.\n",
+	}
+	if len(split) != len(want) {
+		t.Fatalf("len(split) = %d, want %d", len(split), len(want))
+	}
+	for number, content := range want {
+		if string(split[number]) != content {
+			t.Errorf("step %d = %q, want %q", number, split[number], content)
+		}
+	}
+}
+
+func TestNewJobLog(t *testing.T) {
+	calls := 0
+	jobLog := NewJobLog("test", testJobSteps, func() ([]byte, error) {
+		calls++
+		return []byte(testJobContent), nil
+	})
+	if calls != 0 {
+		t.Fatal("fetch must not be called before a step log is read")
+	}
+	archive := NewWorkflowRunLogArchiveFromJobs(jobLog)
+	analysis, err := archive.Analyze(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+	if len(analysis.Jobs) != 1 || analysis.Jobs[0].FailedStep != 2 || len(analysis.Jobs[0].Steps) != 3 {
+		t.Errorf("analysis = %+v", analysis.Jobs[0])
+	}
+	step, err := archive.GetStepLogByName("test", "Skipped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, err := step.ReadContent(); err != nil || len(content) != 0 {
+		t.Errorf("skipped step content = %q, %v", content, err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
+
+func TestNewJobLog_FetchError(t *testing.T) {
+	fetchErr := errors.New("boom")
+	jobLog := NewJobLog("test", testJobSteps, func() ([]byte, error) { return nil, fetchErr })
+	step, _ := jobLog.GetStepLog(1)
+	if _, err := step.ReadContent(); !errors.Is(err, fetchErr) {
+		t.Errorf("err = %v, want %v", err, fetchErr)
+	}
+}
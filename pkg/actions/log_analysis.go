@@ -0,0 +1,351 @@
+package actions
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Log line kinds parsed from "##[...]" markers
+const (
+	LogLineKindGroup    = "group"
+	LogLineKindEndGroup = "endgroup"
+	LogLineKindError    = "error"
+	LogLineKindWarning  = "warning"
+	LogLineKindNotice   = "notice"
+	LogLineKindDebug    = "debug"
+	LogLineKindCommand  = "command"
+)
+
+// Failure categories
+const (
+	FailureCategoryTestFailure = "test-failure"
+	FailureCategoryOOM         = "oom"
+	FailureCategoryTimeout     = "timeout"
+	FailureCategoryNetwork     = "network"
+	FailureCategoryCancelled   = "cancelled"
+	FailureCategoryInfra       = "infra"
+	FailureCategoryUnknown     = "unknown"
+)
+
+//go:embed log_classification_rules.yaml
+var defaultLogClassificationRules []byte
+
+// LogLine is a single line of a step log
+type LogLine struct {
+	Number    int        `json:"number"` // 1-based
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Kind      string     `json:"kind,omitempty"` // one of the LogLineKind constants, or "" for plain output
+	Text      string     `json:"text"`           // the line without timestamp and marker
+}
+
+var exitCodePattern = regexp.MustCompile(`Process completed with exit code (\d+)`)
+
+// ParseLogLines splits a step log into lines, parsing the leading timestamp and "##[kind]" marker of each line
+func ParseLogLines(content []byte) []LogLine {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	text := strings.TrimRight(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	raw := strings.Split(text, "\n")
+	lines := make([]LogLine, len(raw))
+	for i, s := range raw {
+		line := LogLine{Number: i + 1}
+		if ts, rest, ok := strings.Cut(s, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				line.Timestamp = &t
+				s = rest
+			}
+		}
+		if marker, rest, ok := strings.Cut(s, "]"); ok && strings.HasPrefix(marker, "##[") {
+			line.Kind = strings.TrimPrefix(marker, "##[")
+			s = rest
+		}
+		line.Text = s
+		lines[i] = line
+	}
+	return lines
+}
+
+// LogClassificationRule classifies a failure when any of its patterns matches
+type LogClassificationRule struct {
+	ID          string   `json:"id" yaml:"id"`
+	Category    string   `json:"category" yaml:"category"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Patterns    []string `json:"patterns" yaml:"patterns"`
+
+	patterns []*regexp.Regexp
+}
+
+// LogClassificationRuleSet is an ordered list of classification rules; the first matching rule wins
+type LogClassificationRuleSet struct {
+	Rules []*LogClassificationRule `json:"rules" yaml:"rules"`
+}
+
+// ParseLogClassificationRules parses and validates a YAML or JSON rule set
+func ParseLogClassificationRules(data []byte) (*LogClassificationRuleSet, error) {
+	var rs LogClassificationRuleSet
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse log classification rules: %w", err)
+	}
+	for i, rule := range rs.Rules {
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("invalid log classification rule #%d %q: %w", i+1, rule.ID, err)
+		}
+	}
+	return &rs, nil
+}
+
+// LoadLogClassificationRules reads a rule set from a YAML or JSON file
+func LoadLogClassificationRules(path string) (*LogClassificationRuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log classification rules %q: %w", path, err)
+	}
+	return ParseLogClassificationRules(data)
+}
+
+// DefaultLogClassificationRules returns the built-in rule set
+func DefaultLogClassificationRules() *LogClassificationRuleSet {
+	rs, err := ParseLogClassificationRules(defaultLogClassificationRules)
+	if err != nil {
+		panic(err)
+	}
+	return rs
+}
+
+// Prepend adds rules in front of the rule set so that they take precedence, e.g. project specific rules
+// in front of the defaults
+func (rs *LogClassificationRuleSet) Prepend(rules ...*LogClassificationRule) error {
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return fmt.Errorf("invalid log classification rule %q: %w", rule.ID, err)
+		}
+	}
+	rs.Rules = append(append([]*LogClassificationRule{}, rules...), rs.Rules...)
+	return nil
+}
+
+func (r *LogClassificationRule) compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.Category == "" {
+		return fmt.Errorf("category is required")
+	}
+	if len(r.Patterns) == 0 {
+		return fmt.Errorf("patterns are required")
+	}
+	r.patterns = make([]*regexp.Regexp, len(r.Patterns))
+	for i, p := range r.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return err
+		}
+		r.patterns[i] = re
+	}
+	return nil
+}
+
+func (r *LogClassificationRule) match(text string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureClassification is the result of classifying a failed step
+type FailureClassification struct {
+	Category    string   `json:"category"`
+	RuleID      string   `json:"rule_id,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Line        *LogLine `json:"line,omitempty"` // the line the rule matched
+}
+
+// Classify returns the classification of the first rule matching any of the lines.
+// Error lines are tried before the other lines. It returns an unknown classification when no rule matches.
+func (rs *LogClassificationRuleSet) Classify(lines []LogLine) *FailureClassification {
+	ordered := make([]LogLine, 0, len(lines))
+	for _, line := range lines {
+		if line.Kind == LogLineKindError {
+			ordered = append(ordered, line)
+		}
+	}
+	for _, line := range lines {
+		if line.Kind != LogLineKindError {
+			ordered = append(ordered, line)
+		}
+	}
+	for _, rule := range rs.Rules {
+		for i := range ordered {
+			if rule.match(ordered[i].Text) {
+				return &FailureClassification{Category: rule.Category, RuleID: rule.ID, Description: rule.Description, Line: &ordered[i]}
+			}
+		}
+	}
+	return &FailureClassification{Category: FailureCategoryUnknown}
+}
+
+// LogAnalyzerOptions configures log analysis
+type LogAnalyzerOptions struct {
+	Rules        *LogClassificationRuleSet // defaults to DefaultLogClassificationRules
+	ExcerptLines int                       // lines of context before the first error; defaults to 20
+}
+
+func (o *LogAnalyzerOptions) withDefaults() *LogAnalyzerOptions {
+	opts := LogAnalyzerOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Rules == nil {
+		opts.Rules = DefaultLogClassificationRules()
+	}
+	if opts.ExcerptLines <= 0 {
+		opts.ExcerptLines = 20
+	}
+	return &opts
+}
+
+// StepAnalysis is the analysis of a single step log
+type StepAnalysis struct {
+	StepNumber     int                    `json:"step_number"`
+	StepName       string                 `json:"step_name"`
+	StartedAt      *time.Time             `json:"started_at,omitempty"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	Failed         bool                   `json:"failed"`
+	ExitCode       *int                   `json:"exit_code,omitempty"`
+	Errors         []LogLine              `json:"errors,omitempty"`
+	Warnings       []LogLine              `json:"warnings,omitempty"`
+	Groups         []string               `json:"groups,omitempty"`
+	Excerpt        []LogLine              `json:"excerpt,omitempty"` // lines leading up to and including the first error
+	Classification *FailureClassification `json:"classification,omitempty"`
+}
+
+// Duration returns the time between the first and last timestamped line
+func (s *StepAnalysis) Duration() time.Duration {
+	if s.StartedAt == nil || s.CompletedAt == nil {
+		return 0
+	}
+	return s.CompletedAt.Sub(*s.StartedAt)
+}
+
+// JobAnalysis is the analysis of all step logs of a job
+type JobAnalysis struct {
+	JobName string          `json:"job_name"`
+	Failed  bool            `json:"failed"`
+	Steps   []*StepAnalysis `json:"steps"`
+	// FailedStep is the number of the first failed step, or 0 when no step failed
+	FailedStep int `json:"failed_step,omitempty"`
+	// Classification is the classification of the first failed step
+	Classification *FailureClassification `json:"classification,omitempty"`
+}
+
+// LogAnalysis is the analysis of a workflow run log archive
+type LogAnalysis struct {
+	Jobs []*JobAnalysis `json:"jobs"`
+}
+
+// FailedJobs returns the jobs with a failed step
+func (a *LogAnalysis) FailedJobs() []*JobAnalysis {
+	var jobs []*JobAnalysis
+	for _, job := range a.Jobs {
+		if job.Failed {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// AnalyzeStepLog analyzes the content of a single step log. A step failed when it logged an error
+// or completed with a non-zero exit code; failed steps get an excerpt and a classification.
+func AnalyzeStepLog(stepNumber int, stepName string, content []byte, opts *LogAnalyzerOptions) *StepAnalysis {
+	opts = opts.withDefaults()
+	lines := ParseLogLines(content)
+	step := &StepAnalysis{StepNumber: stepNumber, StepName: stepName}
+	firstError := -1
+	for i, line := range lines {
+		if line.Timestamp != nil {
+			if step.StartedAt == nil {
+				step.StartedAt = line.Timestamp
+			}
+			step.CompletedAt = line.Timestamp
+		}
+		switch line.Kind {
+		case LogLineKindGroup:
+			step.Groups = append(step.Groups, line.Text)
+		case LogLineKindWarning:
+			step.Warnings = append(step.Warnings, line)
+		case LogLineKindError:
+			step.Errors = append(step.Errors, line)
+			if firstError < 0 {
+				firstError = i
+			}
+			if m := exitCodePattern.FindStringSubmatch(line.Text); m != nil {
+				if code, err := strconv.Atoi(m[1]); err == nil {
+					step.ExitCode = &code
+				}
+			}
+		}
+	}
+	step.Failed = len(step.Errors) > 0 || (step.ExitCode != nil && *step.ExitCode != 0)
+	if !step.Failed {
+		return step
+	}
+	start := max(firstError-opts.ExcerptLines, 0)
+	for _, line := range lines[start : firstError+1] {
+		if line.Kind == LogLineKindGroup || line.Kind == LogLineKindEndGroup {
+			continue
+		}
+		step.Excerpt = append(step.Excerpt, line)
+	}
+	candidates := append(append([]LogLine{}, step.Excerpt...), step.Errors...)
+	step.Classification = opts.Rules.Classify(candidates)
+	return step
+}
+
+// AnalyzeJobLog analyzes every step log of a job in step order
+func AnalyzeJobLog(job *JobLog, opts *LogAnalyzerOptions) (*JobAnalysis, error) {
+	opts = opts.withDefaults()
+	analysis := &JobAnalysis{JobName: job.JobName}
+	for _, stepLog := range job.ListSteps() {
+		content, err := stepLog.ReadContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log of step %d in job %q: %w", stepLog.StepNumber, job.JobName, err)
+		}
+		step := AnalyzeStepLog(stepLog.StepNumber, stepLog.StepName, content, opts)
+		analysis.Steps = append(analysis.Steps, step)
+		if step.Failed && !analysis.Failed {
+			analysis.Failed = true
+			analysis.FailedStep = step.StepNumber
+			analysis.Classification = step.Classification
+		}
+	}
+	return analysis, nil
+}
+
+// Analyze analyzes the logs of every job in the archive, sorted by job name
+func (w *WorkflowRunLogArchive) Analyze(opts *LogAnalyzerOptions) (*LogAnalysis, error) {
+	opts = opts.withDefaults()
+	jobs := w.ListJobs()
+	slices.Sort(jobs)
+	analysis := &LogAnalysis{}
+	for _, name := range jobs {
+		job, err := AnalyzeJobLog(w.JobLogs[name], opts)
+		if err != nil {
+			return nil, err
+		}
+		analysis.Jobs = append(analysis.Jobs, job)
+	}
+	return analysis, nil
+}
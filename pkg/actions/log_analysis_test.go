@@ -0,0 +1,213 @@
+package actions
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+)
+
+func newTestLogArchive(t *testing.T, files map[string]string) *WorkflowRunLogArchive {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := NewWorkflowRunLogArchive(context.Background(), zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return archive
+}
+
+func TestParseLogLines(t *testing.T) {
+	content := "\xef\xbb\xbf2024-01-02T03:04:05.1234567Z ##[group]Run go test\r\n" +
+		"2024-01-02T03:04:05.2000000Z go test ./...\r\n" +
+		"2024-01-02T03:04:05.3000000Z ##[endgroup]\r\n" +
+		"no timestamp\r\n"
+	lines := ParseLogLines([]byte(content))
+	if len(lines) != 4 {
+		t.Fatalf("len(lines) = %d, want 4", len(lines))
+	}
+	if lines[0].Kind != LogLineKindGroup || lines[0].Text != "Run go test" || lines[0].Timestamp == nil {
+		t.Errorf("lines[0] = %+v", lines[0])
+	}
+	if lines[1].Kind != "" || lines[1].Text != "go test ./..." {
+		t.Errorf("lines[1] = %+v", lines[1])
+	}
+	if lines[2].Kind != LogLineKindEndGroup {
+		t.Errorf("lines[2].Kind = %q", lines[2].Kind)
+	}
+	if lines[3].Timestamp != nil || lines[3].Text != "no timestamp" || lines[3].Number != 4 {
+		t.Errorf("lines[3] = %+v", lines[3])
+	}
+	if ParseLogLines(nil) != nil {
+		t.Error("ParseLogLines(nil) should return nil")
+	}
+}
+
+func TestAnalyzeStepLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		failed   bool
+		exitCode int
+		category string
+	}{
+		{
+			name:    "success",
+			content: "2024-01-02T03:04:05.000Z ok\n2024-01-02T03:04:09.000Z ##[warning]deprecated\n",
+		},
+		{
+			name: "test failure",
+			content: "2024-01-02T03:04:05.000Z === RUN TestFoo\n" +
+				"2024-01-02T03:04:06.000Z --- FAIL: TestFoo (0.00s)\n" +
+				"2024-01-02T03:04:07.000Z ##[error]Process completed with exit code 1.\n",
+			failed: true, exitCode: 1, category: FailureCategoryTestFailure,
+		},
+		{
+			name:    "oom",
+			content: "2024-01-02T03:04:05.000Z ##[error]Process completed with exit code 137.\n",
+			failed:  true, exitCode: 137, category: FailureCategoryOOM,
+		},
+		{
+			name:    "timeout",
+			content: "2024-01-02T03:04:05.000Z ##[error]The job running on runner abc has exceeded the maximum execution time of 10 minutes.\n",
+			failed:  true, exitCode: -1, category: FailureCategoryTimeout,
+		},
+		{
+			name:    "cancelled",
+			content: "2024-01-02T03:04:05.000Z ##[error]The operation was canceled.\n",
+			failed:  true, exitCode: -1, category: FailureCategoryCancelled,
+		},
+		{
+			name: "network",
+			content: "2024-01-02T03:04:05.000Z fatal: unable to access 'https://example.com/': Could not resolve host: example.com\n" +
+				"2024-01-02T03:04:06.000Z ##[error]Process completed with exit code 128.\n",
+			failed: true, exitCode: 128, category: FailureCategoryNetwork,
+		},
+		{
+			name:    "infra",
+			content: "2024-01-02T03:04:05.000Z ##[error]The runner has received a shutdown signal.\n",
+			failed:  true, exitCode: -1, category: FailureCategoryInfra,
+		},
+		{
+			name:    "unknown",
+			content: "2024-01-02T03:04:05.000Z ##[error]Process completed with exit code 2.\n",
+			failed:  true, exitCode: 2, category: FailureCategoryUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := AnalyzeStepLog(1, "step", []byte(tt.content), nil)
+			if step.Failed != tt.failed {
+				t.Fatalf("Failed = %v, want %v", step.Failed, tt.failed)
+			}
+			if !tt.failed {
+				if step.Classification != nil || len(step.Excerpt) != 0 {
+					t.Errorf("successful step has classification %+v and excerpt %+v", step.Classification, step.Excerpt)
+				}
+				if len(step.Warnings) != 1 || step.Duration().Seconds() != 4 {
+					t.Errorf("warnings = %+v, duration = %v", step.Warnings, step.Duration())
+				}
+				return
+			}
+			if tt.exitCode < 0 {
+				if step.ExitCode != nil {
+					t.Errorf("ExitCode = %d, want nil", *step.ExitCode)
+				}
+			} else if step.ExitCode == nil || *step.ExitCode != tt.exitCode {
+				t.Errorf("ExitCode = %v, want %d", step.ExitCode, tt.exitCode)
+			}
+			if step.Classification.Category != tt.category {
+				t.Errorf("Category = %q, want %q", step.Classification.Category, tt.category)
+			}
+		})
+	}
+}
+
+func TestAnalyzeStepLog_Excerpt(t *testing.T) {
+	content := "2024-01-02T03:04:05.000Z line 1\n" +
+		"2024-01-02T03:04:05.000Z ##[group]Run make\n" +
+		"2024-01-02T03:04:05.000Z line 3\n" +
+		"2024-01-02T03:04:05.000Z line 4\n" +
+		"2024-01-02T03:04:05.000Z ##[error]boom\n" +
+		"2024-01-02T03:04:05.000Z after\n"
+	step := AnalyzeStepLog(1, "make", []byte(content), &LogAnalyzerOptions{ExcerptLines: 3})
+	var texts []string
+	for _, line := range step.Excerpt {
+		texts = append(texts, line.Text)
+	}
+	want := []string{"line 3", "line 4", "boom"}
+	if len(texts) != len(want) {
+		t.Fatalf("excerpt = %q, want %q", texts, want)
+	}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Errorf("excerpt[%d] = %q, want %q", i, texts[i], want[i])
+		}
+	}
+	if len(step.Groups) != 1 || step.Groups[0] != "Run make" {
+		t.Errorf("Groups = %q", step.Groups)
+	}
+}
+
+func TestLogClassificationRules(t *testing.T) {
+	if len(DefaultLogClassificationRules().Rules) == 0 {
+		t.Fatal("default rules are empty")
+	}
+	if _, err := ParseLogClassificationRules([]byte("rules:\n  - category: oom\n    patterns: [x]\n")); err == nil {
+		t.Error("expected an error for a rule without id")
+	}
+	if _, err := ParseLogClassificationRules([]byte("rules:\n  - id: bad\n    category: oom\n    patterns: ['(']\n")); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+
+	rules := DefaultLogClassificationRules()
+	custom := &LogClassificationRule{ID: "flaky-dep", Category: FailureCategoryInfra, Patterns: []string{`registry\.example\.com`}}
+	if err := rules.Prepend(custom); err != nil {
+		t.Fatal(err)
+	}
+	got := rules.Classify([]LogLine{{Text: "--- FAIL: TestFoo"}, {Kind: LogLineKindError, Text: "pull from registry.example.com failed"}})
+	if got.RuleID != "flaky-dep" || got.Line.Text != "pull from registry.example.com failed" {
+		t.Errorf("Classify = %+v", got)
+	}
+}
+
+func TestWorkflowRunLogArchive_Analyze(t *testing.T) {
+	archive := newTestLogArchive(t, map[string]string{
+		"build/1_Set up job.txt": "2024-01-02T03:04:05.000Z Starting\n",
+		"build/2_Build.txt":      "2024-01-02T03:04:06.000Z done\n",
+		"test/1_Set up job.txt":  "2024-01-02T03:04:05.000Z Starting\n",
+		"test/2_Test.txt":        "2024-01-02T03:04:06.000Z --- FAIL: TestBar\n2024-01-02T03:04:07.000Z ##[error]Process completed with exit code 1.\n",
+		"test/3_Post.txt":        "2024-01-02T03:04:08.000Z ##[error]cleanup failed\n",
+	})
+	analysis, err := archive.Analyze(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analysis.Jobs) != 2 || analysis.Jobs[0].JobName != "build" || analysis.Jobs[1].JobName != "test" {
+		t.Fatalf("jobs = %+v", analysis.Jobs)
+	}
+	if analysis.Jobs[0].Failed || len(analysis.Jobs[0].Steps) != 2 {
+		t.Errorf("build = %+v", analysis.Jobs[0])
+	}
+	failed := analysis.FailedJobs()
+	if len(failed) != 1 || failed[0].FailedStep != 2 || failed[0].Classification.Category != FailureCategoryTestFailure {
+		t.Errorf("failed jobs = %+v", failed)
+	}
+}
@@ -0,0 +1,232 @@
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Log diff statuses
+const (
+	LogDiffAdded     = "added"     // only in the head run
+	LogDiffRemoved   = "removed"   // only in the base run
+	LogDiffChanged   = "changed"   // in both runs with different normalized logs
+	LogDiffUnchanged = "unchanged" // in both runs with the same normalized logs
+)
+
+// LogNormalizer replaces the matches of Pattern with Replacement before logs are compared
+type LogNormalizer struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultLogNormalizers returns normalizers that strip values which differ on every run:
+// timestamps, durations, UUIDs, commit SHAs and run, job and check suite IDs
+func DefaultLogNormalizers() []LogNormalizer {
+	return []LogNormalizer{
+		{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<timestamp>"},
+		{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+		{regexp.MustCompile(`\b[0-9a-f]{40}\b`), "<sha>"},
+		{regexp.MustCompile(`(/(?:runs|job|jobs|attempts|check-suites|check-runs|artifacts)/)\d+`), "${1}<id>"},
+		{regexp.MustCompile(`\b(\d+h)?(\d+m)?\d+(\.\d+)?(ms|µs|ns|s)\b`), "<duration>"},
+		{regexp.MustCompile(`\b\d+h\d+m\b|\b\d+m\b`), "<duration>"},
+		{regexp.MustCompile(`(?i)\b(\d+(\.\d+)?) ?(seconds?|secs?|minutes?|mins?)\b`), "<duration>"},
+	}
+}
+
+// NormalizeLog parses a step log and returns its lines without timestamp prefixes and with
+// the normalizers applied. Nil normalizers means DefaultLogNormalizers.
+func NormalizeLog(content []byte, normalizers []LogNormalizer) []string {
+	if normalizers == nil {
+		normalizers = DefaultLogNormalizers()
+	}
+	lines := ParseLogLines(content)
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		text := line.Text
+		if line.Kind != "" {
+			text = "##[" + line.Kind + "]" + text
+		}
+		for _, n := range normalizers {
+			text = n.Pattern.ReplaceAllString(text, n.Replacement)
+		}
+		normalized = append(normalized, text)
+	}
+	return normalized
+}
+
+// LogDiffOptions configures DiffLogArchives
+type LogDiffOptions struct {
+	Normalizers  []LogNormalizer // defaults to DefaultLogNormalizers
+	ContextLines int             // lines of context in the unified diffs; defaults to 3
+}
+
+// StepLogDiff is the difference between the logs of a step in two runs
+type StepLogDiff struct {
+	StepName   string `json:"step_name"`
+	BaseNumber int    `json:"base_number,omitempty"` // step number in the base run, 0 if added
+	HeadNumber int    `json:"head_number,omitempty"` // step number in the head run, 0 if removed
+	Status     string `json:"status"`
+	Diff       string `json:"diff,omitempty"` // unified diff of the normalized logs
+}
+
+// JobLogDiff is the difference between the logs of a job in two runs
+type JobLogDiff struct {
+	JobName string         `json:"job_name"`
+	Status  string         `json:"status"`
+	Steps   []*StepLogDiff `json:"steps,omitempty"`
+}
+
+// LogDiff is the difference between the logs of two runs, with jobs sorted by name
+type LogDiff struct {
+	Jobs []*JobLogDiff `json:"jobs"`
+}
+
+// Changed returns the jobs that were added, removed or changed
+func (d *LogDiff) Changed() []*JobLogDiff {
+	var jobs []*JobLogDiff
+	for _, job := range d.Jobs {
+		if job.Status != LogDiffUnchanged {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// DiffLogArchives compares the logs of a base run and a head run. Jobs are aligned by name, and steps
+// by name and, for repeated names such as two checkout steps, by their order within the job.
+func DiffLogArchives(base, head *WorkflowRunLogArchive, opts *LogDiffOptions) (*LogDiff, error) {
+	if opts == nil {
+		opts = &LogDiffOptions{}
+	}
+	names := append(base.ListJobs(), head.ListJobs()...)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	diff := &LogDiff{}
+	for _, name := range names {
+		baseJob := base.JobLogs[name]
+		headJob := head.JobLogs[name]
+		job, err := diffJobLogs(name, baseJob, headJob, opts)
+		if err != nil {
+			return nil, err
+		}
+		diff.Jobs = append(diff.Jobs, job)
+	}
+	return diff, nil
+}
+
+// stepKey identifies a step by name and occurrence of that name within the job
+type stepKey struct {
+	name       string
+	occurrence int
+}
+
+func stepKeys(job *JobLog) ([]stepKey, map[stepKey]*StepLog) {
+	if job == nil {
+		return nil, nil
+	}
+	var keys []stepKey
+	steps := map[stepKey]*StepLog{}
+	seen := map[string]int{}
+	for _, step := range job.ListSteps() {
+		key := stepKey{name: step.StepName, occurrence: seen[step.StepName]}
+		seen[step.StepName]++
+		keys = append(keys, key)
+		steps[key] = step
+	}
+	return keys, steps
+}
+
+func diffJobLogs(name string, baseJob, headJob *JobLog, opts *LogDiffOptions) (*JobLogDiff, error) {
+	job := &JobLogDiff{JobName: name, Status: LogDiffUnchanged}
+	switch {
+	case baseJob == nil:
+		job.Status = LogDiffAdded
+	case headJob == nil:
+		job.Status = LogDiffRemoved
+	}
+
+	baseKeys, baseSteps := stepKeys(baseJob)
+	headKeys, headSteps := stepKeys(headJob)
+	// Head order first, then the steps only in the base run
+	keys := headKeys
+	for _, key := range baseKeys {
+		if _, ok := headSteps[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		step, err := diffStepLogs(key.name, baseSteps[key], headSteps[key], opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff logs of job %q: %w", name, err)
+		}
+		if step.Status != LogDiffUnchanged && job.Status == LogDiffUnchanged {
+			job.Status = LogDiffChanged
+		}
+		job.Steps = append(job.Steps, step)
+	}
+	return job, nil
+}
+
+func diffStepLogs(name string, baseStep, headStep *StepLog, opts *LogDiffOptions) (*StepLogDiff, error) {
+	step := &StepLogDiff{StepName: name}
+	var baseLines, headLines []string
+	if baseStep != nil {
+		step.BaseNumber = baseStep.StepNumber
+		content, err := baseStep.ReadContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read base log of step %q: %w", name, err)
+		}
+		baseLines = NormalizeLog(content, opts.Normalizers)
+	}
+	if headStep != nil {
+		step.HeadNumber = headStep.StepNumber
+		content, err := headStep.ReadContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read head log of step %q: %w", name, err)
+		}
+		headLines = NormalizeLog(content, opts.Normalizers)
+	}
+
+	switch {
+	case baseStep == nil:
+		step.Status = LogDiffAdded
+	case headStep == nil:
+		step.Status = LogDiffRemoved
+	case slices.Equal(baseLines, headLines):
+		step.Status = LogDiffUnchanged
+		return step, nil
+	default:
+		step.Status = LogDiffChanged
+	}
+
+	contextLines := opts.ContextLines
+	if contextLines <= 0 {
+		contextLines = 3
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        withNewlines(baseLines),
+		B:        withNewlines(headLines),
+		FromFile: "base/" + name,
+		ToFile:   "head/" + name,
+		Context:  contextLines,
+	})
+	if err != nil {
+		return nil, err
+	}
+	step.Diff = diff
+	return step, nil
+}
+
+func withNewlines(lines []string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = strings.TrimSuffix(line, "\r") + "\n"
+	}
+	return result
+}
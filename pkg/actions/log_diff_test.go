@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLog(t *testing.T) {
+	content := "2024-01-02T03:04:05.1234567Z ##[group]Run tests\n" +
+		"2024-01-02T03:04:06.0000000Z ok  \texample.com/pkg\t0.012s\n" +
+		"2024-01-02T03:04:07.0000000Z HEAD is now at 0123456789abcdef0123456789abcdef01234567\n" +
+		"2024-01-02T03:04:08.0000000Z see https://github.com/o/r/actions/runs/123/job/456\n" +
+		"2024-01-02T03:04:09.0000000Z temp dir /tmp/3f2504e0-4f89-11d3-9a0c-0305e82c3301\n" +
+		"2024-01-02T03:04:10.0000000Z finished at 2024-01-02 03:04:10 after 12 seconds\n"
+	want := []string{
+		"##[group]Run tests",
+		"ok  \texample.com/pkg\t<duration>",
+		"HEAD is now at <sha>",
+		"see https://github.com/o/r/actions/runs/<id>/job/<id>",
+		"temp dir /tmp/<uuid>",
+		"finished at <timestamp> after <duration>",
+	}
+	got := NormalizeLog([]byte(content), nil)
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeLog() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDiffLogArchives(t *testing.T) {
+	base := newTestLogArchive(t, map[string]string{
+		"lint/1_Run lint.txt":      "2024-01-01T00:00:00.000Z ok in 1.2s\n",
+		"test/1_Checkout.txt":      "2024-01-01T00:00:00.000Z checkout a\n",
+		"test/2_Checkout.txt":      "2024-01-01T00:00:00.000Z checkout b\n",
+		"test/3_Test.txt":          "2024-01-01T00:00:00.000Z --- PASS: TestA (0.01s)\n2024-01-01T00:00:01.000Z ok\n",
+		"test/4_Cache.txt":         "2024-01-01T00:00:00.000Z cache hit\n",
+		"removed/1_Set up job.txt": "2024-01-01T00:00:00.000Z setup\n",
+	})
+	head := newTestLogArchive(t, map[string]string{
+		"lint/1_Run lint.txt":    "2024-02-01T00:00:00.000Z ok in 3.4s\n",
+		"test/1_Checkout.txt":    "2024-02-01T00:00:00.000Z checkout a\n",
+		"test/2_Checkout.txt":    "2024-02-01T00:00:00.000Z checkout b\n",
+		"test/3_Test.txt":        "2024-02-01T00:00:00.000Z --- FAIL: TestA (0.02s)\n2024-02-01T00:00:01.000Z ok\n",
+		"added/1_Set up job.txt": "2024-02-01T00:00:00.000Z setup\n",
+	})
+	diff, err := DiffLogArchives(base, head, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := map[string]*JobLogDiff{}
+	for _, job := range diff.Jobs {
+		jobs[job.JobName] = job
+	}
+	for name, status := range map[string]string{"lint": LogDiffUnchanged, "test": LogDiffChanged, "added": LogDiffAdded, "removed": LogDiffRemoved} {
+		if jobs[name] == nil || jobs[name].Status != status {
+			t.Errorf("job %q = %+v, want status %q", name, jobs[name], status)
+		}
+	}
+	if len(diff.Changed()) != 3 {
+		t.Errorf("len(Changed()) = %d, want 3", len(diff.Changed()))
+	}
+
+	steps := jobs["test"].Steps
+	wantSteps := []struct {
+		name   string
+		status string
+	}{
+		{"Checkout", LogDiffUnchanged},
+		{"Checkout", LogDiffUnchanged},
+		{"Test", LogDiffChanged},
+		{"Cache", LogDiffRemoved},
+	}
+	if len(steps) != len(wantSteps) {
+		t.Fatalf("steps = %+v", steps)
+	}
+	for i, want := range wantSteps {
+		if steps[i].StepName != want.name || steps[i].Status != want.status {
+			t.Errorf("steps[%d] = %s/%s, want %s/%s", i, steps[i].StepName, steps[i].Status, want.name, want.status)
+		}
+	}
+	if !strings.Contains(steps[2].Diff, "--- base/Test") || !strings.Contains(steps[2].Diff, "+--- FAIL: TestA (<duration>)") {
+		t.Errorf("diff = %q", steps[2].Diff)
+	}
+	if strings.Contains(steps[2].Diff, "2024-") {
+		t.Errorf("diff contains timestamps: %q", steps[2].Diff)
+	}
+}
@@ -18,10 +18,14 @@ type StepLog struct {
 	StepName   string
 	FilePath   string
 	zipFile    *zip.File
+	load       func() ([]byte, error) // used instead of zipFile for logs downloaded per job
 }
 
-// ReadContent reads and returns the log content from the zip file
+// ReadContent reads and returns the log content from the zip file, or from the job log it was split from
 func (s *StepLog) ReadContent() ([]byte, error) {
+	if s.load != nil {
+		return s.load()
+	}
 	if s.zipFile == nil {
 		return nil, fmt.Errorf("zip file reference is nil")
 	}
@@ -68,6 +72,7 @@ func (j *JobLog) GetStepLogByName(stepName string) (*StepLog, error) {
 // WorkflowRunLogArchive represents the entire workflow run log archive structure
 type WorkflowRunLogArchive struct {
 	JobLogs map[string]*JobLog // key: job name
+	closer  io.Closer
 }
 
 // NewWorkflowRunLogArchive creates a new WorkflowRunLogArchive instance by fetching and parsing logs
@@ -85,6 +90,38 @@ func NewWorkflowRunLogArchive(ctx context.Context, zipReader *zip.Reader) (*Work
 	return archive, nil
 }
 
+// NewWorkflowRunLogArchiveWithCloser creates a WorkflowRunLogArchive whose Close also closes closer,
+// e.g. a temporary file backing zipReader
+func NewWorkflowRunLogArchiveWithCloser(ctx context.Context, zipReader *zip.Reader, closer io.Closer) (*WorkflowRunLogArchive, error) {
+	archive, err := NewWorkflowRunLogArchive(ctx, zipReader)
+	if err != nil {
+		return nil, err
+	}
+	archive.closer = closer
+	return archive, nil
+}
+
+// NewWorkflowRunLogArchiveFromJobs creates a WorkflowRunLogArchive from job logs, e.g. ones created with NewJobLog
+func NewWorkflowRunLogArchiveFromJobs(jobLogs ...*JobLog) *WorkflowRunLogArchive {
+	archive := &WorkflowRunLogArchive{
+		JobLogs: make(map[string]*JobLog, len(jobLogs)),
+	}
+	for _, jobLog := range jobLogs {
+		archive.JobLogs[jobLog.JobName] = jobLog
+	}
+	return archive
+}
+
+// Close releases the resources backing the archive. Step logs cannot be read after Close.
+func (w *WorkflowRunLogArchive) Close() error {
+	if w.closer == nil {
+		return nil
+	}
+	closer := w.closer
+	w.closer = nil
+	return closer.Close()
+}
+
 // parseZipArchive parses the downloaded zip file and builds the log structure
 func (w *WorkflowRunLogArchive) parseZipArchive(zipReader *zip.Reader) error {
 	// Iterate through files in the zip archive
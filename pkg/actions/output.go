@@ -11,29 +11,86 @@ func GetErrorPrefix() string {
 	return "::error::"
 }
 
-func Output(name, value string) (err error) {
-	outputPath := os.Getenv("GITHUB_OUTPUT")
-	if outputPath == "" {
+// Output sets a step output by appending to the GITHUB_OUTPUT file
+func Output(name, value string) error {
+	return appendKeyValue("GITHUB_OUTPUT", name, value)
+}
+
+// SetEnv sets an environment variable for the following steps by appending to the GITHUB_ENV file.
+// The variable is also set in the current process.
+func SetEnv(name, value string) error {
+	if err := appendKeyValue("GITHUB_ENV", name, value); err != nil {
+		return err
+	}
+	return os.Setenv(name, value)
+}
+
+// SaveState saves a value for the pre/post steps of the action by appending to the GITHUB_STATE file
+func SaveState(name, value string) error {
+	return appendKeyValue("GITHUB_STATE", name, value)
+}
+
+// GetState returns a value saved by SaveState in an earlier phase of the action.
+// The runner passes saved state to later phases as STATE_<name> environment variables.
+func GetState(name string) string {
+	return os.Getenv("STATE_" + name)
+}
+
+// AddPath prepends a directory to PATH for the following steps by appending to the GITHUB_PATH file.
+// PATH of the current process is updated as well.
+func AddPath(dir string) error {
+	if strings.ContainsAny(dir, "\r\n") {
+		return fmt.Errorf("path %q must not contain a newline", dir)
+	}
+	if err := appendFileCommand("GITHUB_PATH", dir+"\n"); err != nil {
+		return err
+	}
+	return os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// appendKeyValue appends name=value to the file command file named by envName.
+// Multiline values use the heredoc format with a random delimiter that does not occur in the value.
+func appendKeyValue(envName, name, value string) error {
+	if name == "" || strings.ContainsAny(name, "=\r\n") {
+		return fmt.Errorf("invalid %s name %q", envName, name)
+	}
+	if !strings.ContainsAny(value, "\r\n") {
+		return appendFileCommand(envName, fmt.Sprintf("%s=%s\n", name, value))
+	}
+	delimiter := safeDelimiter(value)
+	return appendFileCommand(envName, fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter))
+}
+
+// appendFileCommand appends content to the file named by envName.
+// Nothing is written when the variable is not set, e.g. when running outside GitHub Actions.
+func appendFileCommand(envName, content string) (err error) {
+	path := os.Getenv(envName)
+	if path == "" {
 		return nil
 	}
-	outputFile, err := os.OpenFile(outputPath, os.O_APPEND|os.O_WRONLY, 0600)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
-		return fmt.Errorf("failed to open GITHUB_OUTPUT file: %w", err)
+		return fmt.Errorf("failed to open %s file: %w", envName, err)
 	}
 	defer func() {
-		err = outputFile.Close()
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
 	}()
-	// If value contains newline, use multiline format
-	if strings.Contains(value, "\n") {
-		delimiter := randomDelimiter()
-		_, err = fmt.Fprintf(outputFile, "%s<<%s\n%s\n%s\n", name, delimiter, value, delimiter)
-		return err
-	} else {
-		_, err = fmt.Fprintf(outputFile, "%s=%s\n", name, value)
-	}
+	_, err = f.WriteString(content)
 	return err
 }
 
+// safeDelimiter returns a random delimiter that does not occur in value
+func safeDelimiter(value string) string {
+	for {
+		delimiter := "ghadelimiter_" + randomDelimiter()
+		if !strings.Contains(value, delimiter) {
+			return delimiter
+		}
+	}
+}
+
 func randomDelimiter() string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, 8)
@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newFileCommandFile(t *testing.T, envName string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), strings.ToLower(envName))
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(envName, path)
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestOutput(t *testing.T) {
+	path := newFileCommandFile(t, "GITHUB_OUTPUT")
+	if err := Output("single", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Output("multi", "line1\nline2"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(readFile(t, path), "\n")
+	if lines[0] != "single=value" {
+		t.Errorf("single line = %q", lines[0])
+	}
+	name, delimiter, ok := strings.Cut(lines[1], "<<")
+	if !ok || name != "multi" || !strings.HasPrefix(delimiter, "ghadelimiter_") {
+		t.Fatalf("multiline header = %q", lines[1])
+	}
+	if lines[2] != "line1" || lines[3] != "line2" || lines[4] != delimiter {
+		t.Errorf("multiline body = %q", lines[2:])
+	}
+
+	if err := Output("bad\nname", "v"); err == nil {
+		t.Error("expected error for a name with a newline")
+	}
+}
+
+func TestOutput_NotInActions(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "")
+	if err := Output("name", "value"); err != nil {
+		t.Errorf("Output() without GITHUB_OUTPUT returned %v", err)
+	}
+}
+
+func TestSafeDelimiter(t *testing.T) {
+	value := "ghadelimiter_"
+	for range 10 {
+		if d := safeDelimiter(value); strings.Contains(value, d) {
+			t.Fatalf("delimiter %q occurs in the value", d)
+		}
+	}
+}
+
+func TestSetEnvAndState(t *testing.T) {
+	envPath := newFileCommandFile(t, "GITHUB_ENV")
+	statePath := newFileCommandFile(t, "GITHUB_STATE")
+	t.Setenv("GHX_TEST_VAR", "")
+
+	if err := SetEnv("GHX_TEST_VAR", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, envPath); got != "GHX_TEST_VAR=1\n" {
+		t.Errorf("GITHUB_ENV = %q", got)
+	}
+	if os.Getenv("GHX_TEST_VAR") != "1" {
+		t.Error("SetEnv() did not set the variable in the current process")
+	}
+
+	if err := SaveState("pid", "42"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, statePath); got != "pid=42\n" {
+		t.Errorf("GITHUB_STATE = %q", got)
+	}
+	t.Setenv("STATE_pid", "42")
+	if got := GetState("pid"); got != "42" {
+		t.Errorf("GetState() = %q", got)
+	}
+}
+
+func TestAddPath(t *testing.T) {
+	path := newFileCommandFile(t, "GITHUB_PATH")
+	t.Setenv("PATH", "/usr/bin")
+
+	if err := AddPath("/opt/tool/bin"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "/opt/tool/bin\n" {
+		t.Errorf("GITHUB_PATH = %q", got)
+	}
+	if got := os.Getenv("PATH"); got != "/opt/tool/bin"+string(os.PathListSeparator)+"/usr/bin" {
+		t.Errorf("PATH = %q", got)
+	}
+	if err := AddPath("a\nb"); err == nil {
+		t.Error("expected error for a path with a newline")
+	}
+}
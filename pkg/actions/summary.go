@@ -0,0 +1,171 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StepSummaryEnv is the environment variable naming the step summary file
+const StepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// Summary builds markdown for the job summary. It implements io.Writer so that renderers can
+// write tables and other output into it; call Flush to append the content to GITHUB_STEP_SUMMARY.
+type Summary struct {
+	buf strings.Builder
+}
+
+// NewSummary returns an empty summary
+func NewSummary() *Summary {
+	return &Summary{}
+}
+
+// Write appends raw markdown
+func (s *Summary) Write(p []byte) (int, error) {
+	return s.buf.Write(p)
+}
+
+// String returns the markdown built so far
+func (s *Summary) String() string {
+	return s.buf.String()
+}
+
+// AddRaw appends raw markdown, followed by a newline when eol is true
+func (s *Summary) AddRaw(text string, eol bool) *Summary {
+	s.buf.WriteString(text)
+	if eol {
+		s.buf.WriteString("\n")
+	}
+	return s
+}
+
+// AddHeading appends a heading of the given level (1-6)
+func (s *Summary) AddHeading(text string, level int) *Summary {
+	level = min(max(level, 1), 6)
+	return s.AddRaw(strings.Repeat("#", level)+" "+text+"\n", true)
+}
+
+// AddParagraph appends a paragraph
+func (s *Summary) AddParagraph(text string) *Summary {
+	return s.AddRaw(text+"\n", true)
+}
+
+// AddCodeBlock appends a fenced code block. The fence is made longer than any backtick run in code.
+func (s *Summary) AddCodeBlock(code string, lang string) *Summary {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return s.AddRaw(fmt.Sprintf("%s%s\n%s\n%s\n", fence, lang, strings.TrimSuffix(code, "\n"), fence), true)
+}
+
+// AddList appends a bulleted or numbered list
+func (s *Summary) AddList(items []string, ordered bool) *Summary {
+	for i, item := range items {
+		if ordered {
+			s.buf.WriteString(fmt.Sprintf("%d. %s\n", i+1, item))
+		} else {
+			s.buf.WriteString("- " + item + "\n")
+		}
+	}
+	return s.AddRaw("", true)
+}
+
+// AddDetails appends a collapsible section
+func (s *Summary) AddDetails(label string, content string) *Summary {
+	return s.AddRaw(fmt.Sprintf("<details><summary>%s</summary>\n\n%s\n\n</details>\n", label, content), true)
+}
+
+// AddLink appends a link
+func (s *Summary) AddLink(text string, href string) *Summary {
+	return s.AddRaw(fmt.Sprintf("[%s](%s)\n", text, href), true)
+}
+
+// AddSeparator appends a horizontal rule
+func (s *Summary) AddSeparator() *Summary {
+	return s.AddRaw("---\n", true)
+}
+
+// AddTable appends a markdown table
+func (s *Summary) AddTable(table *MarkdownTable) *Summary {
+	return s.AddRaw(table.String(), true)
+}
+
+// Flush appends the content to GITHUB_STEP_SUMMARY and clears the buffer.
+// Nothing is written when the variable is not set.
+func (s *Summary) Flush() error {
+	if err := appendFileCommand(StepSummaryEnv, s.buf.String()); err != nil {
+		return err
+	}
+	s.buf.Reset()
+	return nil
+}
+
+// Overwrite replaces the content of GITHUB_STEP_SUMMARY with the summary and clears the buffer
+func (s *Summary) Overwrite() error {
+	if err := ClearSummary(); err != nil {
+		return err
+	}
+	return s.Flush()
+}
+
+// ClearSummary removes everything written to GITHUB_STEP_SUMMARY by the current step
+func ClearSummary() error {
+	path := os.Getenv(StepSummaryEnv)
+	if path == "" {
+		return nil
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return fmt.Errorf("failed to clear %s file: %w", StepSummaryEnv, err)
+	}
+	return nil
+}
+
+// MarkdownTable builds a GitHub flavored markdown table. Append has the same signature as
+// render.TableWriter.Append, so code filling a TableWriter can fill a MarkdownTable as well.
+type MarkdownTable struct {
+	header []string
+	rows   [][]string
+}
+
+// NewMarkdownTable returns an empty table with the given column headers
+func NewMarkdownTable(header []string) *MarkdownTable {
+	return &MarkdownTable{header: header}
+}
+
+// Append adds a row. Missing cells are left empty and extra cells are dropped.
+func (t *MarkdownTable) Append(row []string) {
+	t.rows = append(t.rows, row)
+}
+
+// String returns the table as markdown. Pipes and newlines in cells are escaped.
+func (t *MarkdownTable) String() string {
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i := range t.header {
+			cell := ""
+			if i < len(cells) {
+				cell = escapeMarkdownCell(cells[i])
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(t.header)
+	b.WriteString("|")
+	for range t.header {
+		b.WriteString(" --- |")
+	}
+	b.WriteString("\n")
+	for _, row := range t.rows {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
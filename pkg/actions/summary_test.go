@@ -0,0 +1,34 @@
+package actions
+
+import "testing"
+
+func TestSummary(t *testing.T) {
+	path := newFileCommandFile(t, StepSummaryEnv)
+
+	table := NewMarkdownTable([]string{"NAME", "VALUE"})
+	table.Append([]string{"a|b", "1\n2"})
+	table.Append([]string{"c"})
+	s := NewSummary().
+		AddHeading("Result", 2).
+		AddTable(table).
+		AddCodeBlock("x := \"```\"", "go")
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := "## Result\n\n" +
+		"| NAME | VALUE |\n| --- | --- |\n| a\\|b | 1<br>2 |\n| c |  |\n\n" +
+		"````go\nx := \"```\"\n````\n\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	if s.String() != "" {
+		t.Error("Flush() did not clear the buffer")
+	}
+
+	if err := NewSummary().AddParagraph("replaced").Overwrite(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "replaced\n\n" {
+		t.Errorf("summary after Overwrite() = %q", got)
+	}
+}
@@ -0,0 +1,380 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// Upgrade status of an action reference
+const (
+	ActionUpgradeStatusUpToDate = "up-to-date"
+	ActionUpgradeStatusPatch    = "patch"
+	ActionUpgradeStatusMinor    = "minor"
+	ActionUpgradeStatusMajor    = "major"
+	ActionUpgradeStatusUnknown  = "unknown"
+)
+
+// ActionUsage is a place where an action reference is used
+type ActionUsage struct {
+	Repository string                `json:"repository"`
+	Source     string                `json:"source"`
+	JobID      string                `json:"job_id,omitempty"`
+	repo       repository.Repository // repository that owns Source
+	traversed  bool                  // true if Source was reached by traversing another repository
+}
+
+// ActionUpgrade is the upgrade status of a single action reference compared against the tags
+// of the action repository
+type ActionUpgrade struct {
+	Action         parser.ActionReference `json:"action"`
+	CurrentVersion string                 `json:"current_version,omitempty"` // the ref, or the tag a SHA-pinned ref points to
+	LatestVersion  string                 `json:"latest_version,omitempty"`
+	LatestSHA      string                 `json:"latest_sha,omitempty"`
+	SuggestedRef   string                 `json:"suggested_ref,omitempty"` // ref to upgrade to; a commit SHA for SHA-pinned refs
+	Status         string                 `json:"status"`
+	Usages         []ActionUsage          `json:"usages"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+// IsOutdated returns true if a newer version is available
+func (u *ActionUpgrade) IsOutdated() bool {
+	switch u.Status {
+	case ActionUpgradeStatusPatch, ActionUpgradeStatusMinor, ActionUpgradeStatusMajor:
+		return true
+	}
+	return false
+}
+
+// actionTagVersion is a tag of an action repository parsed as a version
+type actionTagVersion struct {
+	Version parser.ActionVersion
+	SHA     string
+}
+
+// CheckOutdatedActions compares every remote action reference in deps against the tags of its
+// repository. Floating tags (e.g. "v4") are up to date while they cover the latest release,
+// and SHA-pinned refs are mapped back to the most specific tag pointing to the same commit.
+// fallback is used when the primary client cannot list the tags (e.g. GHES -> github.com).
+func CheckOutdatedActions(ctx context.Context, g *GitHubClient, deps []parser.WorkflowDependency, fallback *GitHubClient) []*ActionUpgrade {
+	usages := make(map[string][]ActionUsage)
+	for _, dep := range deps {
+		repo := dep.Repository
+		source := dep.Source
+		traversed := false
+		// Sources of traversed dependencies are prefixed with "owner/repo:"
+		if prefix, path, ok := strings.Cut(source, ":"); ok && strings.Contains(prefix, "/") {
+			if owner, name, ok := strings.Cut(prefix, "/"); ok {
+				repo = repository.Repository{Host: dep.Repository.Host, Owner: owner, Name: name}
+			}
+			source = path
+			traversed = true
+		}
+		for _, action := range dep.Actions {
+			usages[action.VersionedName()] = append(usages[action.VersionedName()], ActionUsage{
+				Repository: parser.GetRepositoryFullName(repo),
+				Source:     source,
+				JobID:      action.JobID,
+				repo:       repo,
+				traversed:  traversed,
+			})
+		}
+	}
+
+	tagCache := make(map[string][]actionTagVersion)
+	var upgrades []*ActionUpgrade
+	for _, action := range FlattenWorkflowDependencies(deps) {
+		if !action.IsRemote() || action.Ref == "" {
+			continue
+		}
+		upgrade := &ActionUpgrade{
+			Action: action,
+			Status: ActionUpgradeStatusUnknown,
+			Usages: usages[action.VersionedName()],
+		}
+		upgrades = append(upgrades, upgrade)
+
+		repo := repository.Repository{Owner: action.Owner, Name: action.Repo}
+		key := parser.GetRepositoryFullName(repo)
+		tags, ok := tagCache[key]
+		if !ok {
+			var err error
+			tags, err = listActionTagVersions(ctx, g, fallback, repo)
+			if err != nil {
+				logger.Debug("Failed to list action tags", "repository", key, "error", err)
+				upgrade.Error = err.Error()
+			}
+			tagCache[key] = tags
+		}
+		evaluateActionUpgrade(upgrade, tags)
+	}
+	return upgrades
+}
+
+// listActionTagVersions lists the tags of an action repository that parse as versions
+func listActionTagVersions(ctx context.Context, g *GitHubClient, fallback *GitHubClient, repo repository.Repository) ([]actionTagVersion, error) {
+	tags, err := ListTags(ctx, g, repo)
+	if err != nil && fallback != nil {
+		tags, err = ListTags(ctx, fallback, repo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags for %s: %w", parser.GetRepositoryFullName(repo), err)
+	}
+	var versions []actionTagVersion
+	for _, tag := range tags {
+		if v, ok := parser.ParseActionVersion(tag.GetName()); ok {
+			versions = append(versions, actionTagVersion{Version: v, SHA: tag.GetCommit().GetSHA()})
+		}
+	}
+	return versions, nil
+}
+
+// evaluateActionUpgrade fills the current/latest versions, status and suggested ref of upgrade from tags
+func evaluateActionUpgrade(upgrade *ActionUpgrade, tags []actionTagVersion) {
+	if len(tags) == 0 {
+		return
+	}
+	var latest *actionTagVersion
+	for i := range tags {
+		if latest == nil || compareActionTagVersions(tags[i], *latest) > 0 {
+			latest = &tags[i]
+		}
+	}
+	upgrade.LatestVersion = latest.Version.Raw
+	upgrade.LatestSHA = latest.SHA
+
+	var current parser.ActionVersion
+	if upgrade.Action.IsPinned() {
+		var found *actionTagVersion
+		for i := range tags {
+			if tags[i].SHA == upgrade.Action.Ref && (found == nil || parser.CompareActionVersions(tags[i].Version, found.Version) > 0) {
+				found = &tags[i]
+			}
+		}
+		if found == nil {
+			upgrade.Error = "no tag points to the pinned commit"
+			return
+		}
+		// A SHA pin is an exact version even if the matching tag is a floating one
+		current = found.Version
+		current.Precision = 3
+		upgrade.CurrentVersion = found.Version.Raw
+	} else {
+		v, ok := parser.ParseActionVersion(upgrade.Action.Ref)
+		if !ok {
+			upgrade.Error = "ref is not a version"
+			return
+		}
+		current = v
+		upgrade.CurrentVersion = v.Raw
+	}
+
+	kind := parser.UpgradeKind(current, latest.Version)
+	if kind == "" {
+		upgrade.Status = ActionUpgradeStatusUpToDate
+		return
+	}
+	upgrade.Status = kind
+	if upgrade.Action.IsPinned() {
+		upgrade.SuggestedRef = latest.SHA
+		return
+	}
+	upgrade.SuggestedRef = latest.Version.Raw
+	suggested := latest.Version.Format(current.Precision)
+	if slices.ContainsFunc(tags, func(t actionTagVersion) bool { return t.Version.Raw == suggested }) {
+		upgrade.SuggestedRef = suggested
+	}
+}
+
+// compareActionTagVersions orders tags so that stable releases sort after prereleases
+// of any version, and otherwise by version
+func compareActionTagVersions(a, b actionTagVersion) int {
+	if a.Version.IsPrerelease() != b.Version.IsPrerelease() {
+		if a.Version.IsPrerelease() {
+			return -1
+		}
+		return 1
+	}
+	return parser.CompareActionVersions(a.Version, b.Version)
+}
+
+// FilterOutdatedActions returns the upgrades that have a newer version available
+func FilterOutdatedActions(upgrades []*ActionUpgrade) []*ActionUpgrade {
+	var outdated []*ActionUpgrade
+	for _, upgrade := range upgrades {
+		if upgrade.IsOutdated() {
+			outdated = append(outdated, upgrade)
+		}
+	}
+	return outdated
+}
+
+// ActionUpgradePullRequestOptions holds options for opening action upgrade pull requests
+type ActionUpgradePullRequestOptions struct {
+	Branch       string   // head branch to create or reuse; defaults to "update-actions"
+	Title        string   // pull request title; a default title is used when empty
+	Statuses     []string // upgrade statuses to apply; all outdated statuses when empty
+	Draft        bool
+	CommitAuthor *CommitAuthor // optional
+}
+
+// CreateActionUpgradePullRequests opens one pull request per repository that bumps the outdated
+// action references found in that repository's own files. An existing head branch, e.g. from a previous
+// run, is reset to the default branch and rewritten, and its open pull request is returned instead of a
+// new one. Repositories where nothing changes are skipped and the branch is removed.
+func CreateActionUpgradePullRequests(ctx context.Context, g *GitHubClient, upgrades []*ActionUpgrade, opts *ActionUpgradePullRequestOptions) ([]*github.PullRequest, error) {
+	if opts == nil {
+		opts = &ActionUpgradePullRequestOptions{}
+	}
+	branch := opts.Branch
+	if branch == "" {
+		branch = "update-actions"
+	}
+	title := opts.Title
+	if title == "" {
+		title = "Update GitHub Actions"
+	}
+
+	type repoChanges struct {
+		repo     repository.Repository
+		files    map[string]map[string]parser.ActionUseReplacement
+		upgrades []*ActionUpgrade
+	}
+	var order []string
+	changes := make(map[string]*repoChanges)
+	for _, upgrade := range upgrades {
+		if !upgrade.IsOutdated() || upgrade.SuggestedRef == "" {
+			continue
+		}
+		if len(opts.Statuses) > 0 && !slices.Contains(opts.Statuses, upgrade.Status) {
+			continue
+		}
+		replacement := parser.ActionUseReplacement{Ref: upgrade.SuggestedRef}
+		if upgrade.Action.IsPinned() {
+			replacement.Comment = upgrade.LatestVersion
+		}
+		for _, usage := range upgrade.Usages {
+			// Only the scanned repositories are updated, never traversed third-party actions
+			if usage.traversed {
+				continue
+			}
+			key := parser.GetRepositoryFullName(usage.repo)
+			rc, ok := changes[key]
+			if !ok {
+				rc = &repoChanges{repo: usage.repo, files: make(map[string]map[string]parser.ActionUseReplacement)}
+				changes[key] = rc
+				order = append(order, key)
+			}
+			if rc.files[usage.Source] == nil {
+				rc.files[usage.Source] = make(map[string]parser.ActionUseReplacement)
+			}
+			rc.files[usage.Source][upgrade.Action.Raw] = replacement
+			if !slices.Contains(rc.upgrades, upgrade) {
+				rc.upgrades = append(rc.upgrades, upgrade)
+			}
+		}
+	}
+
+	var pulls []*github.PullRequest
+	for _, key := range order {
+		rc := changes[key]
+		pr, err := createActionUpgradePullRequest(ctx, g, rc.repo, branch, title, rc.files, rc.upgrades, opts)
+		if err != nil {
+			return pulls, fmt.Errorf("failed to open action upgrade pull request for %s: %w", key, err)
+		}
+		if pr != nil {
+			pulls = append(pulls, pr)
+		}
+	}
+	return pulls, nil
+}
+
+// createActionUpgradePullRequest creates the head branch, commits the rewritten files and opens the pull request
+func createActionUpgradePullRequest(ctx context.Context, g *GitHubClient, repo repository.Repository, branch, title string, files map[string]map[string]parser.ActionUseReplacement, upgrades []*ActionUpgrade, opts *ActionUpgradePullRequestOptions) (*github.PullRequest, error) {
+	r, err := GetRepository(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	base := r.GetDefaultBranch()
+	baseBranch, err := GetBranch(ctx, g, repo, base)
+	if err != nil {
+		return nil, err
+	}
+	baseSHA := baseBranch.GetCommit().GetSHA()
+	if _, err := GetBranch(ctx, g, repo, branch); err == nil {
+		if _, err := ResetBranch(ctx, g, repo, branch, baseSHA); err != nil {
+			return nil, err
+		}
+	} else if !IsHTTPNotFound(err) {
+		return nil, err
+	} else if _, err := CreateBranch(ctx, g, repo, branch, baseSHA); err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	changed := 0
+	for _, path := range paths {
+		fileContent, err := GetRepositoryFileContent(ctx, g, repo, path, &branch)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode file content for %s: %w", path, err)
+		}
+		updated, count := parser.ReplaceActionUses([]byte(content), files[path])
+		if count == 0 {
+			continue
+		}
+		_, err = UpdateRepositoryFile(ctx, g, repo, path, &RepositoryContentFileOptions{
+			Message: fmt.Sprintf("Update actions in %s", path),
+			Content: updated,
+			Branch:  &branch,
+			SHA:     fileContent.SHA,
+			Author:  opts.CommitAuthor,
+		})
+		if err != nil {
+			return nil, err
+		}
+		changed++
+	}
+	if changed == 0 {
+		logger.Info("No action references to update", "repository", parser.GetRepositoryFullName(repo))
+		return nil, DeleteBranch(ctx, g, repo, branch)
+	}
+
+	head := repo.Owner + ":" + branch
+	existing, err := ListPullRequests(ctx, g, repo, &ListPullRequestsOptionHead{Head: head}, &ListPullRequestsOptionBase{Base: base}, ListPullRequestsOptionStateOpen())
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		logger.Info("Updated existing action upgrade pull request", "repository", parser.GetRepositoryFullName(repo), "number", existing[0].GetNumber())
+		return existing[0], nil
+	}
+
+	var body strings.Builder
+	body.WriteString("| Action | Current | Latest | Update |\n| --- | --- | --- | --- |\n")
+	for _, upgrade := range upgrades {
+		fmt.Fprintf(&body, "| %s | %s | %s | %s |\n", upgrade.Action.Name(), upgrade.CurrentVersion, upgrade.LatestVersion, upgrade.Status)
+	}
+	bodyText := body.String()
+	return CreatePullRequest(ctx, g, repo, NewPullRequest{
+		Title: title,
+		Head:  branch,
+		Base:  base,
+		Body:  &bodyText,
+		Draft: &opts.Draft,
+	})
+}
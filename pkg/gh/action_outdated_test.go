@@ -0,0 +1,52 @@
+package gh
+
+import (
+	"testing"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+func newTestActionTags(tags map[string]string) []actionTagVersion {
+	var versions []actionTagVersion
+	for name, sha := range tags {
+		v, _ := parser.ParseActionVersion(name)
+		versions = append(versions, actionTagVersion{Version: v, SHA: sha})
+	}
+	return versions
+}
+
+func TestEvaluateActionUpgrade(t *testing.T) {
+	tags := newTestActionTags(map[string]string{
+		"v4":          "aaaa",
+		"v4.2.2":      "aaaa",
+		"v4.2.1":      "bbbb",
+		"v5":          "cccc",
+		"v5.1.0":      "cccc",
+		"v6.0.0-beta": "dddd",
+	})
+	sha := "bbbb000000000000000000000000000000000000"
+	tags = append(tags, newTestActionTags(map[string]string{"v4.1.0": sha})...)
+
+	tests := []struct {
+		uses          string
+		wantStatus    string
+		wantCurrent   string
+		wantSuggested string
+	}{
+		{uses: "actions/checkout@v5", wantStatus: ActionUpgradeStatusUpToDate, wantCurrent: "v5"},
+		{uses: "actions/checkout@v4", wantStatus: ActionUpgradeStatusMajor, wantCurrent: "v4", wantSuggested: "v5"},
+		{uses: "actions/checkout@v4.2.1", wantStatus: ActionUpgradeStatusMajor, wantCurrent: "v4.2.1", wantSuggested: "v5.1.0"},
+		{uses: "actions/checkout@" + sha, wantStatus: ActionUpgradeStatusMajor, wantCurrent: "v4.1.0", wantSuggested: "cccc"},
+		{uses: "actions/checkout@main", wantStatus: ActionUpgradeStatusUnknown},
+	}
+	for _, tt := range tests {
+		upgrade := &ActionUpgrade{Action: parser.ParseActionReference(tt.uses), Status: ActionUpgradeStatusUnknown}
+		evaluateActionUpgrade(upgrade, tags)
+		if upgrade.Status != tt.wantStatus || upgrade.CurrentVersion != tt.wantCurrent || upgrade.SuggestedRef != tt.wantSuggested {
+			t.Errorf("%s: got status=%q current=%q suggested=%q, want %q %q %q", tt.uses, upgrade.Status, upgrade.CurrentVersion, upgrade.SuggestedRef, tt.wantStatus, tt.wantCurrent, tt.wantSuggested)
+		}
+		if upgrade.Status != ActionUpgradeStatusUnknown && upgrade.LatestVersion != "v5.1.0" {
+			t.Errorf("%s: latest = %q, want v5.1.0", tt.uses, upgrade.LatestVersion)
+		}
+	}
+}
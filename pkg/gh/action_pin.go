@@ -0,0 +1,268 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/ioutil"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// ActionPinResult is the pinning status of a single "uses:" reference
+type ActionPinResult struct {
+	Repository string `json:"repository,omitempty"`
+	Source     string `json:"source"`
+	Line       int    `json:"line"`
+	Uses       string `json:"uses"`
+	Pinned     bool   `json:"pinned"`            // true if the reference is already pinned to a commit SHA
+	SHA        string `json:"sha,omitempty"`     // resolved commit SHA for an unpinned reference
+	Comment    string `json:"comment,omitempty"` // version written as a trailing comment
+	Error      string `json:"error,omitempty"`
+}
+
+// ActionPinFile is a workflow or action file to be checked or pinned.
+// Files loaded from a repository are written back through the contents API;
+// files loaded from a local directory (LocalPath is set) are written to disk.
+type ActionPinFile struct {
+	WorkflowFile
+	Results []*ActionPinResult `json:"results"`
+	pinned  []byte
+}
+
+// newActionPinFiles wraps workflow files for pinning
+func newActionPinFiles(files []*WorkflowFile) []*ActionPinFile {
+	pinFiles := make([]*ActionPinFile, 0, len(files))
+	for _, file := range files {
+		pinFiles = append(pinFiles, &ActionPinFile{WorkflowFile: *file})
+	}
+	return pinFiles
+}
+
+// Changed returns true if pinning modifies the file content
+func (f *ActionPinFile) Changed() bool {
+	return f.pinned != nil && string(f.pinned) != string(f.Content)
+}
+
+// PinnedContent returns the rewritten file content, or the original content if nothing was pinned
+func (f *ActionPinFile) PinnedContent() []byte {
+	if f.pinned == nil {
+		return f.Content
+	}
+	return f.pinned
+}
+
+// ActionPinResolver resolves action refs to commit SHAs and caches the results across files
+type ActionPinResolver struct {
+	Fallback *GitHubClient // optional client used when the primary host cannot resolve the action (e.g. GHES -> github.com)
+	pins     map[string]parser.ActionPin
+	errs     map[string]error
+	tags     map[string][]*github.RepositoryTag
+}
+
+// NewActionPinResolver creates a new ActionPinResolver
+func NewActionPinResolver(fallback *GitHubClient) *ActionPinResolver {
+	return &ActionPinResolver{
+		Fallback: fallback,
+		pins:     make(map[string]parser.ActionPin),
+		errs:     make(map[string]error),
+		tags:     make(map[string][]*github.RepositoryTag),
+	}
+}
+
+// ResolveActionPin resolves the ref of a remote action reference to its full commit SHA.
+// The comment is the most specific tag pointing to the same commit that extends the ref
+// (e.g. "v4" -> "v4.2.2"), or the ref itself when no such tag exists.
+func ResolveActionPin(ctx context.Context, g *GitHubClient, resolver *ActionPinResolver, action parser.ActionReference) (parser.ActionPin, error) {
+	if !action.IsRemote() {
+		return parser.ActionPin{}, fmt.Errorf("%s is not a remote action reference", action.Raw)
+	}
+	if action.Ref == "" {
+		return parser.ActionPin{}, fmt.Errorf("%s has no ref", action.Raw)
+	}
+	repo := repository.Repository{Owner: action.Owner, Name: action.Repo}
+	key := parser.GetRepositoryFullName(repo) + "@" + action.Ref
+	if pin, ok := resolver.pins[key]; ok {
+		return pin, nil
+	}
+	if err, ok := resolver.errs[key]; ok {
+		return parser.ActionPin{}, err
+	}
+
+	client := g
+	sha, err := GetCommitSHA1(ctx, client, repo, action.Ref)
+	if err != nil && resolver.Fallback != nil {
+		client = resolver.Fallback
+		sha, err = GetCommitSHA1(ctx, client, repo, action.Ref)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to resolve %s: %w", action.VersionedName(), err)
+		resolver.errs[key] = err
+		return parser.ActionPin{}, err
+	}
+
+	tags, ok := resolver.tags[parser.GetRepositoryFullName(repo)]
+	if !ok {
+		tags, err = ListTags(ctx, client, repo)
+		if err != nil {
+			logger.Debug("Failed to list tags for pin comment", "repository", parser.GetRepositoryFullName(repo), "error", err)
+		}
+		resolver.tags[parser.GetRepositoryFullName(repo)] = tags
+	}
+	pin := parser.ActionPin{SHA: sha, Comment: mostSpecificTag(tags, sha, action.Ref)}
+	resolver.pins[key] = pin
+	return pin, nil
+}
+
+// mostSpecificTag returns the longest tag pointing to sha that equals ref or extends it with ".",
+// e.g. "v4.2.2" for ref "v4". ref is returned when no such tag exists.
+func mostSpecificTag(tags []*github.RepositoryTag, sha string, ref string) string {
+	best := ref
+	for _, tag := range tags {
+		if tag.GetCommit().GetSHA() != sha {
+			continue
+		}
+		name := tag.GetName()
+		if name != ref && !strings.HasPrefix(name, ref+".") {
+			continue
+		}
+		if len(name) > len(best) || (len(name) == len(best) && name > best) {
+			best = name
+		}
+	}
+	return best
+}
+
+// GetRepositoryActionPinFiles fetches the workflow files under .github/workflows and the root
+// action.yml/action.yaml of a repository.
+func GetRepositoryActionPinFiles(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string) ([]*ActionPinFile, error) {
+	files, err := GetRepositoryWorkflowFiles(ctx, g, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return newActionPinFiles(files), nil
+}
+
+// LoadLocalActionPinFiles reads the workflow files under .github/workflows and every
+// action.yml/action.yaml below dir. Paths are reported relative to dir.
+func LoadLocalActionPinFiles(dir string) ([]*ActionPinFile, error) {
+	files, err := LoadLocalWorkflowFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newActionPinFiles(files), nil
+}
+
+// PlanActionPins resolves every unpinned remote "uses:" reference in files and computes the
+// pinned content. Local actions, docker images and expressions are skipped.
+// Resolution failures are recorded on the result instead of aborting the plan.
+func PlanActionPins(ctx context.Context, g *GitHubClient, files []*ActionPinFile, resolver *ActionPinResolver) {
+	for _, file := range files {
+		file.Results = nil
+		pins := make(map[string]parser.ActionPin)
+		for _, use := range parser.FindActionUses(file.Content) {
+			action := use.Reference
+			if !action.IsRemote() {
+				continue
+			}
+			result := &ActionPinResult{
+				Source: file.Path,
+				Line:   use.Line,
+				Uses:   action.Raw,
+				Pinned: action.IsPinned(),
+			}
+			if file.LocalPath == "" {
+				result.Repository = parser.GetRepositoryFullName(file.Repository)
+			}
+			file.Results = append(file.Results, result)
+			if result.Pinned {
+				result.SHA = action.Ref
+				result.Comment = use.Comment
+				continue
+			}
+			pin, err := ResolveActionPin(ctx, g, resolver, action)
+			if err != nil {
+				result.Error = err.Error()
+				continue
+			}
+			result.SHA = pin.SHA
+			result.Comment = pin.Comment
+			pins[action.Raw] = pin
+		}
+		file.pinned, _ = parser.PinActionUses(file.Content, pins)
+	}
+}
+
+// UnpinnedActionPinResults returns the results of references that are not pinned to a commit SHA
+func UnpinnedActionPinResults(files []*ActionPinFile) []*ActionPinResult {
+	var results []*ActionPinResult
+	for _, file := range files {
+		for _, result := range file.Results {
+			if !result.Pinned {
+				results = append(results, result)
+			}
+		}
+	}
+	return results
+}
+
+// ActionPinApplyOptions holds options for writing pinned files back to a repository
+type ActionPinApplyOptions struct {
+	Message   string        // commit message; a default message is used when empty
+	Branch    *string       // optional: defaults to the repository's default branch
+	Author    *CommitAuthor // optional
+	Committer *CommitAuthor // optional
+}
+
+// ApplyActionPins writes the pinned content of every changed file. Local files are replaced
+// atomically; repository files are committed through UpdateRepositoryFile (or CreateRepositoryFile
+// when the file has no blob SHA). It returns the files that were written.
+func ApplyActionPins(ctx context.Context, g *GitHubClient, files []*ActionPinFile, opts *ActionPinApplyOptions) ([]*ActionPinFile, error) {
+	if opts == nil {
+		opts = &ActionPinApplyOptions{}
+	}
+	var written []*ActionPinFile
+	for _, file := range files {
+		if !file.Changed() {
+			continue
+		}
+		if file.LocalPath != "" {
+			if err := ioutil.WriteFileAtomic(file.LocalPath, file.pinned, 0o644); err != nil {
+				return written, fmt.Errorf("failed to write %s: %w", file.LocalPath, err)
+			}
+			written = append(written, file)
+			continue
+		}
+
+		message := opts.Message
+		if message == "" {
+			message = fmt.Sprintf("Pin actions to commit SHAs in %s", file.Path)
+		}
+		fileOpts := &RepositoryContentFileOptions{
+			Message:   message,
+			Content:   file.pinned,
+			Branch:    opts.Branch,
+			Author:    opts.Author,
+			Committer: opts.Committer,
+		}
+		var err error
+		var resp *github.RepositoryContentResponse
+		if file.BlobSHA == "" {
+			resp, err = CreateRepositoryFile(ctx, g, file.Repository, file.Path, fileOpts)
+		} else {
+			fileOpts.SHA = &file.BlobSHA
+			resp, err = UpdateRepositoryFile(ctx, g, file.Repository, file.Path, fileOpts)
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to commit %s to %s: %w", file.Path, parser.GetRepositoryFullName(file.Repository), err)
+		}
+		if resp != nil && resp.Content != nil {
+			file.BlobSHA = resp.Content.GetSHA()
+		}
+		written = append(written, file)
+	}
+	return written, nil
+}
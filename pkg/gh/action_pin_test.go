@@ -0,0 +1,74 @@
+package gh
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+const actionPinTestSHA = "11bd71901bbe5b1630ceea73d27597364c9af683"
+
+func TestMostSpecificTag(t *testing.T) {
+	tags := []*github.RepositoryTag{
+		{Name: github.Ptr("v4"), Commit: &github.Commit{SHA: github.Ptr(actionPinTestSHA)}},
+		{Name: github.Ptr("v4.2"), Commit: &github.Commit{SHA: github.Ptr(actionPinTestSHA)}},
+		{Name: github.Ptr("v4.2.2"), Commit: &github.Commit{SHA: github.Ptr(actionPinTestSHA)}},
+		{Name: github.Ptr("v4.2.1"), Commit: &github.Commit{SHA: github.Ptr("other")}},
+		{Name: github.Ptr("v40.0.0"), Commit: &github.Commit{SHA: github.Ptr(actionPinTestSHA)}},
+	}
+	if got := mostSpecificTag(tags, actionPinTestSHA, "v4"); got != "v4.2.2" {
+		t.Errorf("mostSpecificTag(v4) = %q, want v4.2.2", got)
+	}
+	if got := mostSpecificTag(tags, actionPinTestSHA, "main"); got != "main" {
+		t.Errorf("mostSpecificTag(main) = %q, want main", got)
+	}
+}
+
+func TestPlanAndApplyLocalActionPins(t *testing.T) {
+	dir := t.TempDir()
+	workflowPath := filepath.Join(dir, ".github", "workflows", "ci.yml")
+	if err := os.MkdirAll(filepath.Dir(workflowPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	workflow := "jobs:\n  build:\n    steps:\n      # keep this comment\n      - uses: actions/checkout@v4\n      - uses: actions/cache@" + actionPinTestSHA + " # v4.0.0\n"
+	if err := os.WriteFile(workflowPath, []byte(workflow), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := LoadLocalActionPinFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Path != ".github/workflows/ci.yml" {
+		t.Fatalf("files = %+v", files)
+	}
+
+	resolver := NewActionPinResolver(nil)
+	resolver.pins["actions/checkout@v4"] = parser.ActionPin{SHA: actionPinTestSHA, Comment: "v4.2.2"}
+	PlanActionPins(context.Background(), nil, files, resolver)
+
+	unpinned := UnpinnedActionPinResults(files)
+	if len(unpinned) != 1 || unpinned[0].Uses != "actions/checkout@v4" || unpinned[0].Line != 5 || unpinned[0].SHA != actionPinTestSHA {
+		t.Fatalf("unpinned = %+v", unpinned)
+	}
+	if !files[0].Changed() {
+		t.Fatal("expected file to change")
+	}
+
+	written, err := ApplyActionPins(context.Background(), nil, files, nil)
+	if err != nil || len(written) != 1 {
+		t.Fatalf("ApplyActionPins() = %d files, %v", len(written), err)
+	}
+	got, err := os.ReadFile(workflowPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "jobs:\n  build:\n    steps:\n      # keep this comment\n      - uses: actions/checkout@" + actionPinTestSHA + " # v4.2.2\n      - uses: actions/cache@" + actionPinTestSHA + " # v4.0.0\n"
+	if string(got) != want {
+		t.Errorf("pinned workflow =\n%s\nwant\n%s", got, want)
+	}
+}
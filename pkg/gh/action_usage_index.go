@@ -0,0 +1,179 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/ioutil"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// ActionUsageEntry is a single use of an action or reusable workflow by a scanned repository.
+// For transitive use, Source and JobID point at the workflow of the scanned repository the chain
+// starts from, and Via lists the actions and reusable workflows in between.
+type ActionUsageEntry struct {
+	Action     string   `json:"action"` // e.g. "actions/checkout" or "owner/repo/.github/workflows/build.yml"
+	Ref        string   `json:"ref,omitempty"`
+	Repository string   `json:"repository"`
+	Source     string   `json:"source"`
+	JobID      string   `json:"job_id,omitempty"`
+	Via        []string `json:"via,omitempty"`
+}
+
+// IsTransitive returns true if the action is used through another action or reusable workflow
+func (e *ActionUsageEntry) IsTransitive() bool {
+	return len(e.Via) > 0
+}
+
+// ActionUsageIndex is a reverse index from actions to the repositories that use them
+type ActionUsageIndex struct {
+	Owner        string              `json:"owner"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Repositories []string            `json:"repositories"`
+	Errors       map[string]string   `json:"errors,omitempty"` // repository -> error for repositories that could not be scanned
+	Entries      []*ActionUsageEntry `json:"entries"`
+}
+
+// ActionUsageIndexOptions controls which repositories are scanned when building an index
+type ActionUsageIndexOptions struct {
+	IncludeArchived bool
+	IncludeForks    bool
+	Fallback        *GitHubClient // used when an action cannot be fetched from the primary host (e.g. GHES -> github.com)
+}
+
+// NewActionUsageIndex returns an empty index for the owner
+func NewActionUsageIndex(owner string) *ActionUsageIndex {
+	return &ActionUsageIndex{
+		Owner:     owner,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// isOwnWorkflowSource returns true if source is a workflow or the root action of the scanned repository,
+// i.e. a starting point of usage chains rather than a file reached by traversal
+func isOwnWorkflowSource(source string) bool {
+	if prefix, _, ok := strings.Cut(source, ":"); ok && strings.Contains(prefix, "/") {
+		return false
+	}
+	return strings.HasPrefix(source, workflowsDir+"/") || isActionMetadataFile(source)
+}
+
+// Add indexes the recursively traversed dependencies of a scanned repository
+func (idx *ActionUsageIndex) Add(repo repository.Repository, deps []parser.WorkflowDependency) {
+	repoName := parser.GetRepositoryFullName(repo)
+	idx.Repositories = append(idx.Repositories, repoName)
+
+	depBySource := make(map[string]*parser.WorkflowDependency)
+	for i := range deps {
+		depBySource[deps[i].Source] = &deps[i]
+	}
+	hasSource := func(key string) bool {
+		_, ok := depBySource[key]
+		return ok
+	}
+
+	var walk func(root *parser.WorkflowDependency, dep *parser.WorkflowDependency, rootJobID string, via []string, visiting map[string]bool)
+	walk = func(root *parser.WorkflowDependency, dep *parser.WorkflowDependency, rootJobID string, via []string, visiting map[string]bool) {
+		for _, action := range dep.Actions {
+			jobID := rootJobID
+			if jobID == "" {
+				jobID = action.JobID
+			}
+			if !action.IsLocal {
+				idx.Entries = append(idx.Entries, &ActionUsageEntry{
+					Action:     action.Name(),
+					Ref:        action.Ref,
+					Repository: repoName,
+					Source:     root.Source,
+					JobID:      jobID,
+					Via:        slices.Clone(via),
+				})
+			}
+			key := parser.ResolveActionDepSource(action, hasSource)
+			if key == "" || visiting[key] {
+				continue
+			}
+			visiting[key] = true
+			walk(root, depBySource[key], jobID, append(via, action.VersionedName()), visiting)
+			delete(visiting, key)
+		}
+	}
+	for i := range deps {
+		if isOwnWorkflowSource(deps[i].Source) {
+			walk(&deps[i], &deps[i], "", nil, map[string]bool{deps[i].Source: true})
+		}
+	}
+}
+
+// Find returns the entries using the queried action. The query is "owner/repo[/path][@ref]";
+// without a path all actions of the repository match, and without a ref all refs match.
+func (idx *ActionUsageIndex) Find(query string) []*ActionUsageEntry {
+	q := parser.ParseActionReference(query)
+	name := strings.ToLower(q.Name())
+	var entries []*ActionUsageEntry
+	for _, e := range idx.Entries {
+		action := strings.ToLower(e.Action)
+		if action != name && (q.Path != "" || !strings.HasPrefix(action, name+"/")) {
+			continue
+		}
+		if q.Ref != "" && e.Ref != q.Ref {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// BuildActionUsageIndex scans the workflows of every repository in the organization, following
+// composite actions and reusable workflows, and indexes every action they use.
+// Repositories that cannot be scanned are recorded in Errors and skipped.
+func BuildActionUsageIndex(ctx context.Context, g *GitHubClient, org repository.Repository, opts *ActionUsageIndexOptions) (*ActionUsageIndex, error) {
+	if opts == nil {
+		opts = &ActionUsageIndexOptions{}
+	}
+	repos, err := ListOrganizationRepositories(ctx, g, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s: %w", org.Owner, err)
+	}
+	idx := NewActionUsageIndex(org.Owner)
+	for _, r := range repos {
+		if (r.GetArchived() && !opts.IncludeArchived) || (r.GetFork() && !opts.IncludeForks) {
+			continue
+		}
+		target := repository.Repository{Host: org.Host, Owner: r.GetOwner().GetLogin(), Name: r.GetName()}
+		deps, err := GetRepositoryWorkflowDependencies(ctx, g, target, nil, true, opts.Fallback)
+		if err != nil {
+			logger.Warn("Failed to scan repository workflows", "repository", r.GetFullName(), "error", err)
+			if idx.Errors == nil {
+				idx.Errors = make(map[string]string)
+			}
+			idx.Errors[r.GetFullName()] = err.Error()
+			continue
+		}
+		idx.Add(target, deps)
+	}
+	return idx, nil
+}
+
+// SaveActionUsageIndex writes the index to a JSON file
+func SaveActionUsageIndex(path string, idx *ActionUsageIndex) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal action usage index: %w", err)
+	}
+	if err := ioutil.WriteFileAtomic(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write action usage index %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadActionUsageIndex reads an index written by SaveActionUsageIndex
+func LoadActionUsageIndex(path string) (*ActionUsageIndex, error) {
+	return ioutil.DecodeJSONFile[*ActionUsageIndex](path)
+}
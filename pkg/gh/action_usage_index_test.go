@@ -0,0 +1,70 @@
+package gh
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+func TestActionUsageIndex(t *testing.T) {
+	repo := repository.Repository{Host: "github.com", Owner: "octo", Name: "app"}
+	deps := []parser.WorkflowDependency{
+		{
+			Source: ".github/workflows/ci.yml",
+			Actions: []parser.ActionReference{
+				{Raw: "actions/checkout@v4", Owner: "actions", Repo: "checkout", Ref: "v4", JobID: "build"},
+				{Raw: "./setup", IsLocal: true, JobID: "build"},
+				{Raw: "octo/shared/.github/workflows/deploy.yml@v1", Owner: "octo", Repo: "shared", Path: ".github/workflows/deploy.yml", Ref: "v1", JobID: "deploy"},
+			},
+		},
+		{
+			Source:  "setup/action.yml",
+			Actions: []parser.ActionReference{{Raw: "tj/changed-files@v45", Owner: "tj", Repo: "changed-files", Ref: "v45"}},
+		},
+		{
+			Source:  "octo/shared:.github/workflows/deploy.yml",
+			Actions: []parser.ActionReference{{Raw: "tj/changed-files/sub@v44", Owner: "tj", Repo: "changed-files", Path: "sub", Ref: "v44", JobID: "run"}},
+		},
+	}
+	idx := NewActionUsageIndex("octo")
+	idx.Add(repo, deps)
+
+	entries := idx.Find("tj/changed-files")
+	if len(entries) != 2 {
+		t.Fatalf("Find() = %d entries, want 2", len(entries))
+	}
+	local, remote := entries[0], entries[1]
+	if local.Repository != "octo/app" || local.Source != ".github/workflows/ci.yml" || local.JobID != "build" || local.Ref != "v45" {
+		t.Errorf("unexpected entry via local action: %+v", local)
+	}
+	if len(local.Via) != 1 || local.Via[0] != "./setup" {
+		t.Errorf("local.Via = %v", local.Via)
+	}
+	if remote.JobID != "deploy" || remote.Action != "tj/changed-files/sub" || len(remote.Via) != 1 || remote.Via[0] != "octo/shared/.github/workflows/deploy.yml@v1" {
+		t.Errorf("unexpected entry via reusable workflow: %+v", remote)
+	}
+
+	if got := idx.Find("tj/changed-files@v45"); len(got) != 1 {
+		t.Errorf("Find with ref = %d entries, want 1", len(got))
+	}
+	if got := idx.Find("tj/changed-files/sub"); len(got) != 1 {
+		t.Errorf("Find with path = %d entries, want 1", len(got))
+	}
+	if got := idx.Find("actions/checkout"); len(got) != 1 || got[0].IsTransitive() {
+		t.Errorf("Find(actions/checkout) = %+v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := SaveActionUsageIndex(path, idx); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadActionUsageIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Owner != "octo" || len(loaded.Entries) != len(idx.Entries) || len(loaded.Find("tj/changed-files")) != 2 {
+		t.Errorf("loaded index does not match: %+v", loaded)
+	}
+}
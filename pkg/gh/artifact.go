@@ -0,0 +1,18 @@
+package gh
+
+import (
+	"context"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+)
+
+// ListArtifacts retrieves all artifacts of a repository. A nil name lists artifacts of every name.
+func ListArtifacts(ctx context.Context, g *GitHubClient, repo repository.Repository, name *string) ([]*github.Artifact, error) {
+	return g.ListArtifacts(ctx, repo.Owner, repo.Name, name)
+}
+
+// DeleteArtifact deletes an artifact.
+func DeleteArtifact(ctx context.Context, g *GitHubClient, repo repository.Repository, artifactID int64) error {
+	return g.DeleteArtifact(ctx, repo.Owner, repo.Name, artifactID)
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+
+	"github.com/google/go-github/v90/github"
+)
+
+// ListArtifacts retrieves all artifacts of a repository, optionally filtered by name.
+func (g *GitHubClient) ListArtifacts(ctx context.Context, owner string, repo string, name *string) ([]*github.Artifact, error) {
+	opt := github.ListArtifactsOptions{Name: name, ListOptions: github.ListOptions{PerPage: defaultPerPage}}
+
+	var allArtifacts []*github.Artifact
+	for {
+		artifacts, resp, err := g.client.Actions.ListArtifacts(ctx, owner, repo, &opt)
+		if err != nil {
+			return nil, err
+		}
+		allArtifacts = append(allArtifacts, artifacts.Artifacts...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allArtifacts, nil
+}
+
+// DeleteArtifact deletes an artifact.
+func (g *GitHubClient) DeleteArtifact(ctx context.Context, owner string, repo string, artifactID int64) error {
+	_, err := g.client.Actions.DeleteArtifact(ctx, owner, repo, artifactID)
+	return err
+}
@@ -260,6 +260,15 @@ func (g *GitHubClient) CreateRef(ctx context.Context, owner, repo string, ref st
 	return r, nil
 }
 
+// UpdateRef moves a Git reference (e.g., branch) to sha. With force, the update need not be a fast-forward.
+func (g *GitHubClient) UpdateRef(ctx context.Context, owner, repo, ref string, sha string, force bool) (*github.Reference, error) {
+	r, _, err := g.client.Git.UpdateRef(ctx, owner, repo, ref, github.UpdateRef{SHA: sha, Force: &force})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // DeleteRef deletes a Git reference (e.g., branch).
 func (g *GitHubClient) DeleteRef(ctx context.Context, owner, repo, ref string) error {
 	_, err := g.client.Git.DeleteRef(ctx, owner, repo, ref)
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRef_Force(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	tr := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		gotMethod = r.Method
+		gotPath = r.URL.EscapedPath()
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"ref":"refs/heads/update-actions","object":{"sha":"abc"}}`)),
+			Request:    r,
+		}, nil
+	})
+	g := newTestClient(t, "https://api.github.com/", tr)
+
+	ref, err := g.UpdateRef(t.Context(), "owner", "repo", "heads/update-actions", "abc", true)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/update-actions", ref.GetRef())
+	assert.Equal(t, http.MethodPatch, gotMethod)
+	assert.Equal(t, "/repos/owner/repo/git/refs/heads/update-actions", gotPath)
+	assert.JSONEq(t, `{"sha":"abc","force":true}`, gotBody)
+}
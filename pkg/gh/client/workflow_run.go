@@ -107,6 +107,24 @@ func (g *GitHubClient) ListWorkflowRunsByID(ctx context.Context, owner string, r
 	return allRuns, nil
 }
 
+// GetLatestWorkflowRunByID returns the most recently created run of a workflow that matches options,
+// requesting a single page of one run. Returns nil without an error when no run matches.
+func (g *GitHubClient) GetLatestWorkflowRunByID(ctx context.Context, owner string, repo string, workflowID int64, options *github.ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+	opt := github.ListWorkflowRunsOptions{}
+	if options != nil {
+		opt = *options
+	}
+	opt.ListOptions = github.ListOptions{PerPage: 1}
+	runs, _, err := g.client.Actions.ListWorkflowRunsByID(ctx, owner, repo, workflowID, &opt)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs.WorkflowRuns) == 0 {
+		return nil, nil
+	}
+	return runs.WorkflowRuns[0], nil
+}
+
 // ListWorkflowRunsByFileName retrieves all workflow runs for a specific workflow by file name.
 func (g *GitHubClient) ListWorkflowRunsByFileName(ctx context.Context, owner string, repo string, workflowFileName string, options *github.ListWorkflowRunsOptions) ([]*github.WorkflowRun, error) {
 	opt := github.ListWorkflowRunsOptions{ListOptions: github.ListOptions{PerPage: defaultPerPage}}
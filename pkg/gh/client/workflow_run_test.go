@@ -0,0 +1,56 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLatestWorkflowRunByID(t *testing.T) {
+	var gotPath, gotQuery string
+	requests := 0
+	tr := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		gotPath = r.URL.EscapedPath()
+		gotQuery = r.URL.RawQuery
+		header := make(http.Header)
+		header.Set("Link", `<https://api.github.com/repos/owner/repo/actions/workflows/7/runs?page=2&per_page=1>; rel="next"`)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{"total_count":2,"workflow_runs":[{"id":3},{"id":2}]}`)),
+			Request:    r,
+		}, nil
+	})
+	g := newTestClient(t, "https://api.github.com/", tr)
+
+	run, err := g.GetLatestWorkflowRunByID(t.Context(), "owner", "repo", 7, &github.ListWorkflowRunsOptions{Status: "success", ListOptions: github.ListOptions{PerPage: 100}})
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, int64(3), run.GetID())
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, "/repos/owner/repo/actions/workflows/7/runs", gotPath)
+	assert.Contains(t, gotQuery, "per_page=1")
+	assert.Contains(t, gotQuery, "status=success")
+}
+
+func TestGetLatestWorkflowRunByID_None(t *testing.T) {
+	tr := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"total_count":0,"workflow_runs":[]}`)),
+			Request:    r,
+		}, nil
+	})
+	g := newTestClient(t, "https://api.github.com/", tr)
+
+	run, err := g.GetLatestWorkflowRunByID(t.Context(), "owner", "repo", 7, nil)
+	require.NoError(t, err)
+	assert.Nil(t, run)
+}
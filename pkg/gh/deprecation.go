@@ -0,0 +1,389 @@
+package gh
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Deprecation rule kinds
+const (
+	DeprecationKindRunnerLabel     = "runner-label"     // jobs.<job_id>.runs-on labels, including labels listed in the matrix
+	DeprecationKindWorkflowCommand = "workflow-command" // "::command" workflow commands in run steps
+	DeprecationKindActionVersion   = "action-version"   // uses of an action with a version below a threshold
+	DeprecationKindActionRuntime   = "action-runtime"   // runs.using of action metadata files
+)
+
+// DeprecationKindList is the list of valid deprecation rule kinds.
+var DeprecationKindList = []string{
+	DeprecationKindRunnerLabel,
+	DeprecationKindWorkflowCommand,
+	DeprecationKindActionVersion,
+	DeprecationKindActionRuntime,
+}
+
+//go:embed deprecation_rules.yaml
+var defaultDeprecationRules []byte
+
+// DeprecationRule describes a deprecated runner label, workflow command, action version or runtime.
+// Only the matcher fields of the rule's kind are used.
+type DeprecationRule struct {
+	ID          string   `json:"id" yaml:"id"`
+	Kind        string   `json:"kind" yaml:"kind"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Replacement string   `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	Date        string   `json:"date,omitempty" yaml:"date,omitempty"`         // deprecation or removal date
+	Labels      []string `json:"labels,omitempty" yaml:"labels,omitempty"`     // runner-label
+	Commands    []string `json:"commands,omitempty" yaml:"commands,omitempty"` // workflow-command
+	Action      string   `json:"action,omitempty" yaml:"action,omitempty"`     // action-version, e.g. "actions/cache"
+	Below       string   `json:"below,omitempty" yaml:"below,omitempty"`       // action-version, e.g. "v4"; floating refs covering it (e.g. "v4" for "v4.1") do not match
+	Using       []string `json:"using,omitempty" yaml:"using,omitempty"`       // action-runtime
+
+	below    parser.ActionVersion
+	commands *regexp.Regexp
+}
+
+// DeprecationRuleSet is a data file of deprecation rules
+type DeprecationRuleSet struct {
+	Rules []*DeprecationRule `json:"rules" yaml:"rules"`
+}
+
+// DeprecationFinding is a single use of something a deprecation rule covers
+type DeprecationFinding struct {
+	RuleID      string `json:"rule_id"`
+	Kind        string `json:"kind"`
+	Repository  string `json:"repository,omitempty"`
+	Path        string `json:"path"`
+	Line        int    `json:"line"`
+	JobID       string `json:"job_id,omitempty"`
+	Match       string `json:"match"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// DeprecationRuleSummary aggregates the findings of a single rule
+type DeprecationRuleSummary struct {
+	RuleID       string   `json:"rule_id"`
+	Kind         string   `json:"kind"`
+	Description  string   `json:"description,omitempty"`
+	Replacement  string   `json:"replacement,omitempty"`
+	Date         string   `json:"date,omitempty"`
+	Count        int      `json:"count"`
+	Repositories []string `json:"repositories"`
+	Files        []string `json:"files"`
+}
+
+// DeprecationReport is the migration report over one or more repositories
+type DeprecationReport struct {
+	Summaries []*DeprecationRuleSummary `json:"summaries"`
+	Findings  []*DeprecationFinding     `json:"findings"`
+	Errors    map[string]string         `json:"errors,omitempty"` // repository -> error for repositories that could not be scanned
+}
+
+// ParseDeprecationRules parses and validates a YAML or JSON rule set
+func ParseDeprecationRules(data []byte) (*DeprecationRuleSet, error) {
+	var rs DeprecationRuleSet
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse deprecation rules: %w", err)
+	}
+	for i, rule := range rs.Rules {
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("invalid deprecation rule #%d %q: %w", i+1, rule.ID, err)
+		}
+	}
+	return &rs, nil
+}
+
+// LoadDeprecationRules reads a rule set from a YAML or JSON file
+func LoadDeprecationRules(path string) (*DeprecationRuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deprecation rules %q: %w", path, err)
+	}
+	return ParseDeprecationRules(data)
+}
+
+// DefaultDeprecationRules returns the built-in rule set
+func DefaultDeprecationRules() *DeprecationRuleSet {
+	rs, err := ParseDeprecationRules(defaultDeprecationRules)
+	if err != nil {
+		panic(err)
+	}
+	return rs
+}
+
+func (r *DeprecationRule) compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	switch r.Kind {
+	case DeprecationKindRunnerLabel:
+		if len(r.Labels) == 0 {
+			return fmt.Errorf("labels are required")
+		}
+	case DeprecationKindWorkflowCommand:
+		if len(r.Commands) == 0 {
+			return fmt.Errorf("commands are required")
+		}
+		quoted := make([]string, len(r.Commands))
+		for i, c := range r.Commands {
+			quoted[i] = regexp.QuoteMeta(c)
+		}
+		r.commands = regexp.MustCompile(`::(` + strings.Join(quoted, "|") + `)( [^:]*)?::`)
+	case DeprecationKindActionVersion:
+		below, ok := parser.ParseActionVersion(r.Below)
+		if r.Action == "" || !ok {
+			return fmt.Errorf("action and a version-like below are required")
+		}
+		r.below = below
+	case DeprecationKindActionRuntime:
+		if len(r.Using) == 0 {
+			return fmt.Errorf("using is required")
+		}
+	default:
+		return fmt.Errorf("invalid kind %q: must be one of %s", r.Kind, strings.Join(DeprecationKindList, ", "))
+	}
+	return nil
+}
+
+// deprecationChecker collects findings for a single file
+type deprecationChecker struct {
+	file     *WorkflowFile
+	lines    []string
+	findings []*DeprecationFinding
+}
+
+func (c *deprecationChecker) report(rule *DeprecationRule, pos parser.Position, jobID, match string) {
+	finding := &DeprecationFinding{
+		RuleID:      rule.ID,
+		Kind:        rule.Kind,
+		Path:        c.file.Path,
+		Line:        pos.Line,
+		JobID:       jobID,
+		Match:       match,
+		Replacement: rule.Replacement,
+	}
+	if c.file.Repository.Name != "" {
+		finding.Repository = parser.GetRepositoryFullName(c.file.Repository)
+	}
+	c.findings = append(c.findings, finding)
+}
+
+// CheckWorkflowFileDeprecations matches the rules against a single workflow or action metadata file
+func CheckWorkflowFileDeprecations(file *WorkflowFile, rules *DeprecationRuleSet) ([]*DeprecationFinding, error) {
+	c := &deprecationChecker{file: file, lines: strings.Split(string(file.Content), "\n")}
+	if file.IsActionMetadata() {
+		action, err := parser.ParseActionMetadata(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Path, err)
+		}
+		if action.Runs == nil {
+			return nil, nil
+		}
+		for _, rule := range rules.Rules {
+			if rule.Kind == DeprecationKindActionRuntime && slices.Contains(rule.Using, action.Runs.Using) {
+				c.report(rule, c.lineOf(action.Runs.Pos, "using:"), "", action.Runs.Using)
+			}
+		}
+		c.checkSteps(rules, "", action.Runs.Steps)
+		return c.findings, nil
+	}
+
+	wf, err := parser.ParseWorkflow(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file.Path, err)
+	}
+	for _, job := range wf.Jobs {
+		if job.RunsOn != nil {
+			labels := runnerLabelCandidates(job)
+			for _, rule := range rules.Rules {
+				if rule.Kind != DeprecationKindRunnerLabel {
+					continue
+				}
+				for _, label := range labels {
+					if slices.ContainsFunc(rule.Labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+						c.report(rule, c.lineOf(job.RunsOn.Pos, label), job.ID, label)
+					}
+				}
+			}
+		}
+		if job.Uses != "" {
+			c.checkUses(rules, job.ID, job.Uses, job.UsesPos)
+		}
+		c.checkSteps(rules, job.ID, job.Steps)
+	}
+	return c.findings, nil
+}
+
+// runnerLabelCandidates returns the runs-on labels of a job. Labels given as "${{ matrix.<key> }}"
+// are expanded to the string values of that matrix key, including include entries.
+func runnerLabelCandidates(job *parser.WorkflowJob) []string {
+	var labels []string
+	for _, label := range job.RunsOn.Labels {
+		key, ok := matrixExpressionKey(label)
+		if !ok || job.Strategy == nil || job.Strategy.Matrix == nil {
+			labels = append(labels, label)
+			continue
+		}
+		var values []any
+		values = append(values, job.Strategy.Matrix.Values[key]...)
+		for _, include := range job.Strategy.Matrix.Include {
+			values = append(values, include[key])
+		}
+		for _, v := range values {
+			if s, ok := v.(string); ok && !slices.Contains(labels, s) {
+				labels = append(labels, s)
+			}
+		}
+	}
+	return labels
+}
+
+var matrixExpressionPattern = regexp.MustCompile(`^\$\{\{\s*matrix\.([A-Za-z0-9_-]+)\s*\}\}$`)
+
+// matrixExpressionKey returns the key of a "${{ matrix.<key> }}" expression
+func matrixExpressionKey(s string) (string, bool) {
+	m := matrixExpressionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func (c *deprecationChecker) checkSteps(rules *DeprecationRuleSet, jobID string, steps []*parser.WorkflowStep) {
+	for _, step := range steps {
+		if step.Uses != "" {
+			c.checkUses(rules, jobID, step.Uses, step.UsesPos)
+		}
+		if step.Run == "" {
+			continue
+		}
+		for _, rule := range rules.Rules {
+			if rule.Kind != DeprecationKindWorkflowCommand {
+				continue
+			}
+			for _, m := range rule.commands.FindAllStringSubmatch(step.Run, -1) {
+				c.report(rule, c.lineOf(step.RunPos, m[0]), jobID, "::"+m[1])
+			}
+		}
+	}
+}
+
+func (c *deprecationChecker) checkUses(rules *DeprecationRuleSet, jobID, uses string, pos parser.Position) {
+	action := parser.ParseActionReference(uses)
+	if !action.IsRemote() {
+		return
+	}
+	version, ok := parser.ParseActionVersion(action.Ref)
+	if !ok {
+		return
+	}
+	for _, rule := range rules.Rules {
+		if rule.Kind == DeprecationKindActionVersion && strings.EqualFold(rule.Action, action.Name()) && parser.UpgradeKind(version, rule.below) != "" {
+			c.report(rule, pos, jobID, action.VersionedName())
+		}
+	}
+}
+
+func (c *deprecationChecker) lineOf(start parser.Position, needle string) parser.Position {
+	return lineContaining(c.lines, start, needle)
+}
+
+// lineContaining returns the position of the first line at or after start that contains needle,
+// or start itself when it is not found (e.g. for folded scalars)
+func lineContaining(lines []string, start parser.Position, needle string) parser.Position {
+	for i := max(start.Line-1, 0); i < len(lines); i++ {
+		if col := strings.Index(lines[i], needle); col >= 0 {
+			return parser.Position{Line: i + 1, Column: col + 1}
+		}
+	}
+	return start
+}
+
+// CheckWorkflowFilesDeprecations matches the rules against every file. Files that cannot be parsed are skipped with a warning.
+func CheckWorkflowFilesDeprecations(files []*WorkflowFile, rules *DeprecationRuleSet) []*DeprecationFinding {
+	var findings []*DeprecationFinding
+	for _, file := range files {
+		fileFindings, err := CheckWorkflowFileDeprecations(file, rules)
+		if err != nil {
+			logger.Warn("Failed to check workflow file", "path", file.Path, "error", err)
+			continue
+		}
+		findings = append(findings, fileFindings...)
+	}
+	return findings
+}
+
+// NewDeprecationReport aggregates findings per rule, in rule order. Rules without findings are omitted.
+func NewDeprecationReport(findings []*DeprecationFinding, rules *DeprecationRuleSet) *DeprecationReport {
+	report := &DeprecationReport{Findings: findings}
+	for _, rule := range rules.Rules {
+		summary := &DeprecationRuleSummary{
+			RuleID:      rule.ID,
+			Kind:        rule.Kind,
+			Description: rule.Description,
+			Replacement: rule.Replacement,
+			Date:        rule.Date,
+		}
+		for _, f := range findings {
+			if f.RuleID != rule.ID {
+				continue
+			}
+			summary.Count++
+			if f.Repository != "" && !slices.Contains(summary.Repositories, f.Repository) {
+				summary.Repositories = append(summary.Repositories, f.Repository)
+			}
+			file := f.Path
+			if f.Repository != "" {
+				file = f.Repository + ":" + f.Path
+			}
+			if !slices.Contains(summary.Files, file) {
+				summary.Files = append(summary.Files, file)
+			}
+		}
+		if summary.Count > 0 {
+			sort.Strings(summary.Repositories)
+			sort.Strings(summary.Files)
+			report.Summaries = append(report.Summaries, summary)
+		}
+	}
+	return report
+}
+
+// GetOrganizationDeprecationReport checks the workflows of every non-archived repository of the organization.
+// Repositories that cannot be read are recorded in Errors and skipped.
+func GetOrganizationDeprecationReport(ctx context.Context, g *GitHubClient, org repository.Repository, rules *DeprecationRuleSet) (*DeprecationReport, error) {
+	repos, err := ListOrganizationRepositories(ctx, g, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of %s: %w", org.Owner, err)
+	}
+	var findings []*DeprecationFinding
+	errs := make(map[string]string)
+	for _, r := range repos {
+		if r.GetArchived() {
+			continue
+		}
+		target := repository.Repository{Host: org.Host, Owner: r.GetOwner().GetLogin(), Name: r.GetName()}
+		files, err := GetRepositoryWorkflowFiles(ctx, g, target, nil)
+		if err != nil {
+			logger.Warn("Failed to get workflow files", "repository", r.GetFullName(), "error", err)
+			errs[r.GetFullName()] = err.Error()
+			continue
+		}
+		findings = append(findings, CheckWorkflowFilesDeprecations(files, rules)...)
+	}
+	report := NewDeprecationReport(findings, rules)
+	if len(errs) > 0 {
+		report.Errors = errs
+	}
+	return report, nil
+}
@@ -0,0 +1,88 @@
+package gh
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+func TestDefaultDeprecationRules(t *testing.T) {
+	rules := DefaultDeprecationRules()
+	if len(rules.Rules) == 0 {
+		t.Fatal("no default rules")
+	}
+	if _, err := ParseDeprecationRules([]byte("rules:\n  - id: x\n    kind: unknown\n")); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+	if _, err := ParseDeprecationRules([]byte("rules:\n  - id: x\n    kind: action-version\n    action: a/b\n    below: main\n")); err == nil {
+		t.Error("expected an error for a non-version below")
+	}
+}
+
+func TestCheckWorkflowFileDeprecations(t *testing.T) {
+	rules := DefaultDeprecationRules()
+	repo := repository.Repository{Owner: "octo", Name: "app"}
+	files := []*WorkflowFile{
+		{Repository: repo, Path: ".github/workflows/ci.yml", Content: []byte(`on: push
+jobs:
+  build:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, windows-2019]
+        include:
+          - os: macos-12
+    runs-on: ${{ matrix.os }}
+    steps:
+      - uses: actions/checkout@v3
+      - uses: actions/cache@v4
+      - uses: actions/upload-artifact@v3.1.2
+      - id: out
+        run: |
+          echo "::set-output name=x::1"
+          echo "::save-state name=y::2"
+  legacy:
+    runs-on: ubuntu-20.04
+    steps:
+      - run: echo ok
+`)},
+		{Repository: repo, Path: "action.yml", Content: []byte("runs:\n  using: node16\n  main: index.js\n")},
+	}
+	findings := CheckWorkflowFilesDeprecations(files, rules)
+	got := map[string]int{}
+	lines := map[string]int{}
+	for _, f := range findings {
+		got[f.RuleID]++
+		lines[f.RuleID] = f.Line
+	}
+	want := map[string]int{
+		"runner-windows-2019":       1,
+		"runner-macos-12":           1,
+		"runner-ubuntu-20.04":       1,
+		"action-checkout-node16":    1,
+		"action-upload-artifact-v3": 1,
+		"command-set-output":        1,
+		"command-save-state":        1,
+		"runtime-node16":            1,
+	}
+	if len(got) != len(want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+	for id, n := range want {
+		if got[id] != n {
+			t.Errorf("%s: %d findings, want %d", id, got[id], n)
+		}
+	}
+	if lines["command-save-state"] != 17 || lines["runner-ubuntu-20.04"] != 19 || lines["runtime-node16"] != 2 {
+		t.Errorf("unexpected lines: %v", lines)
+	}
+
+	report := NewDeprecationReport(findings, rules)
+	if len(report.Summaries) != len(want) {
+		t.Fatalf("summaries = %d, want %d", len(report.Summaries), len(want))
+	}
+	for _, s := range report.Summaries {
+		if s.Count != 1 || len(s.Repositories) != 1 || s.Repositories[0] != "octo/app" || len(s.Files) != 1 {
+			t.Errorf("unexpected summary: %+v", s)
+		}
+	}
+}
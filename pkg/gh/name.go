@@ -105,6 +105,12 @@ func GetObjectName(item any) string {
 		return v.GetSlug()
 	case unity.UnityPackage:
 		return v.Name
+	case WorkflowEventNode:
+		return v.GetName()
+	case WorkflowJobNode:
+		return v.GetName()
+	case WorkflowNode:
+		return v.GetName()
 	case *github.User:
 		if v == nil {
 			return ""
@@ -262,6 +268,10 @@ func GetObjectHTMLURL(item any, defaultHost string) string {
 			return ""
 		}
 		return "https://" + host + "/" + s[1]
+	case WorkflowJobNode:
+		return workflowHTMLURL(v.Repository, v.Path, host)
+	case WorkflowNode:
+		return workflowHTMLURL(v.Repository, v.Path, host)
 	default:
 		return ""
 	}
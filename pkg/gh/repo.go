@@ -108,6 +108,11 @@ func CreateBranch(ctx context.Context, g *GitHubClient, repo repository.Reposito
 	return g.CreateRef(ctx, repo.Owner, repo.Name, "refs/heads/"+branchName, sha)
 }
 
+// ResetBranch force-moves an existing branch to the given SHA (wrapper).
+func ResetBranch(ctx context.Context, g *GitHubClient, repo repository.Repository, branchName string, sha string) (*github.Reference, error) {
+	return g.UpdateRef(ctx, repo.Owner, repo.Name, "heads/"+branchName, sha, true)
+}
+
 // DeleteBranch deletes a branch (wrapper).
 func DeleteBranch(ctx context.Context, g *GitHubClient, repo repository.Repository, branchName string) error {
 	return g.DeleteRef(ctx, repo.Owner, repo.Name, "heads/"+branchName)
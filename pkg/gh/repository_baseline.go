@@ -0,0 +1,501 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// RepositoryBaselineProfile describes the expected settings of a repository.
+// Every field is optional; nil fields are not audited or enforced.
+type RepositoryBaselineProfile struct {
+	Repository *RepositoryBaselineSettings `json:"repository,omitempty" yaml:"repository,omitempty"`
+	Security   *RepositorySecurityBaseline `json:"security,omitempty" yaml:"security,omitempty"`
+	DeployKeys *DeployKeyBaseline          `json:"deploy_keys,omitempty" yaml:"deploy_keys,omitempty"`
+}
+
+// RepositoryBaselineSettings holds merge options and feature toggles applied through EditRepository.
+type RepositoryBaselineSettings struct {
+	AllowMergeCommit         *bool   `json:"allow_merge_commit,omitempty" yaml:"allow_merge_commit,omitempty"`
+	AllowSquashMerge         *bool   `json:"allow_squash_merge,omitempty" yaml:"allow_squash_merge,omitempty"`
+	AllowRebaseMerge         *bool   `json:"allow_rebase_merge,omitempty" yaml:"allow_rebase_merge,omitempty"`
+	AllowAutoMerge           *bool   `json:"allow_auto_merge,omitempty" yaml:"allow_auto_merge,omitempty"`
+	AllowUpdateBranch        *bool   `json:"allow_update_branch,omitempty" yaml:"allow_update_branch,omitempty"`
+	DeleteBranchOnMerge      *bool   `json:"delete_branch_on_merge,omitempty" yaml:"delete_branch_on_merge,omitempty"`
+	WebCommitSignoffRequired *bool   `json:"web_commit_signoff_required,omitempty" yaml:"web_commit_signoff_required,omitempty"`
+	SquashMergeCommitTitle   *string `json:"squash_merge_commit_title,omitempty" yaml:"squash_merge_commit_title,omitempty"`
+	SquashMergeCommitMessage *string `json:"squash_merge_commit_message,omitempty" yaml:"squash_merge_commit_message,omitempty"`
+	MergeCommitTitle         *string `json:"merge_commit_title,omitempty" yaml:"merge_commit_title,omitempty"`
+	MergeCommitMessage       *string `json:"merge_commit_message,omitempty" yaml:"merge_commit_message,omitempty"`
+	HasIssues                *bool   `json:"has_issues,omitempty" yaml:"has_issues,omitempty"`
+	HasWiki                  *bool   `json:"has_wiki,omitempty" yaml:"has_wiki,omitempty"`
+	HasProjects              *bool   `json:"has_projects,omitempty" yaml:"has_projects,omitempty"`
+	HasDiscussions           *bool   `json:"has_discussions,omitempty" yaml:"has_discussions,omitempty"`
+}
+
+// RepositorySecurityBaseline holds security feature toggles.
+type RepositorySecurityBaseline struct {
+	AdvancedSecurity              *bool `json:"advanced_security,omitempty" yaml:"advanced_security,omitempty"`
+	SecretScanning                *bool `json:"secret_scanning,omitempty" yaml:"secret_scanning,omitempty"`
+	SecretScanningPushProtection  *bool `json:"secret_scanning_push_protection,omitempty" yaml:"secret_scanning_push_protection,omitempty"`
+	VulnerabilityAlerts           *bool `json:"vulnerability_alerts,omitempty" yaml:"vulnerability_alerts,omitempty"`
+	AutomatedSecurityFixes        *bool `json:"automated_security_fixes,omitempty" yaml:"automated_security_fixes,omitempty"`
+	PrivateVulnerabilityReporting *bool `json:"private_vulnerability_reporting,omitempty" yaml:"private_vulnerability_reporting,omitempty"`
+	// DependencyGraph can only be toggled for the whole organization, so it is only fixed, once per owner,
+	// when DependencyGraphOrganizationWide is set.
+	DependencyGraph *bool `json:"dependency_graph,omitempty" yaml:"dependency_graph,omitempty"`
+	// DependencyGraphOrganizationWide opts in to fixing DependencyGraph for every repository of the owner.
+	DependencyGraphOrganizationWide bool `json:"dependency_graph_organization_wide,omitempty" yaml:"dependency_graph_organization_wide,omitempty"`
+	// CodeScanningDefaultSetup is "configured" or "not-configured".
+	CodeScanningDefaultSetup *string `json:"code_scanning_default_setup,omitempty" yaml:"code_scanning_default_setup,omitempty"`
+	// CodeScanningQuerySuite is "default" or "extended"; only checked when default setup is configured.
+	CodeScanningQuerySuite *string `json:"code_scanning_query_suite,omitempty" yaml:"code_scanning_query_suite,omitempty"`
+	// CodeQualitySetup is "configured" or "not-configured".
+	CodeQualitySetup *string `json:"code_quality_setup,omitempty" yaml:"code_quality_setup,omitempty"`
+}
+
+// DeployKeyBaseline describes the deploy key policy of a repository.
+type DeployKeyBaseline struct {
+	// Allowed set to false requires the repository to have no deploy keys.
+	Allowed *bool `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+	// ReadOnly set to true requires every deploy key to be read-only.
+	ReadOnly *bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+// RepositoryBaselineResult is the compliance result of a single setting of a repository.
+type RepositoryBaselineResult struct {
+	Repository string `json:"repository"`
+	Setting    string `json:"setting"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+	Compliant  bool   `json:"compliant"`
+	Fixed      bool   `json:"fixed,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	repo repository.Repository
+	fix  func(ctx context.Context, g *GitHubClient, repo repository.Repository) error
+}
+
+// LoadRepositoryBaselineProfile reads a baseline profile from a YAML or JSON file.
+func LoadRepositoryBaselineProfile(path string) (*RepositoryBaselineProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline profile %q: %w", path, err)
+	}
+	var profile RepositoryBaselineProfile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline profile %q: %w", path, err)
+	}
+	return &profile, nil
+}
+
+// repositoryBaselineCheck audits a single setting and knows how to fix it.
+type repositoryBaselineCheck struct {
+	setting  string
+	expected string
+	actual   func(ctx context.Context, g *GitHubClient, repo repository.Repository, r *github.Repository) (string, error)
+	fix      func(ctx context.Context, g *GitHubClient, repo repository.Repository) error
+}
+
+func formatEnabled(b bool) string {
+	if b {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func repositorySettingBoolCheck(setting string, expected *bool, get func(r *github.Repository) bool, set func(u *github.Repository, v *bool)) *repositoryBaselineCheck {
+	return &repositoryBaselineCheck{
+		setting:  setting,
+		expected: strconv.FormatBool(*expected),
+		actual: func(_ context.Context, _ *GitHubClient, _ repository.Repository, r *github.Repository) (string, error) {
+			return strconv.FormatBool(get(r)), nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			update := &github.Repository{}
+			set(update, expected)
+			_, err := EditRepository(ctx, g, repo, update)
+			return err
+		},
+	}
+}
+
+func repositorySettingStringCheck(setting string, expected *string, get func(r *github.Repository) string, set func(u *github.Repository, v *string)) *repositoryBaselineCheck {
+	return &repositoryBaselineCheck{
+		setting:  setting,
+		expected: *expected,
+		actual: func(_ context.Context, _ *GitHubClient, _ repository.Repository, r *github.Repository) (string, error) {
+			return get(r), nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			update := &github.Repository{}
+			set(update, expected)
+			_, err := EditRepository(ctx, g, repo, update)
+			return err
+		},
+	}
+}
+
+func securityAndAnalysisCheck(setting string, expected *bool, get func(s *github.SecurityAndAnalysis) string, set func(s *github.SecurityAndAnalysis, status *string)) *repositoryBaselineCheck {
+	return &repositoryBaselineCheck{
+		setting:  setting,
+		expected: formatEnabled(*expected),
+		actual: func(_ context.Context, _ *GitHubClient, _ repository.Repository, r *github.Repository) (string, error) {
+			status := get(r.GetSecurityAndAnalysis())
+			if status == "" {
+				status = formatEnabled(false)
+			}
+			return status, nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			s := &github.SecurityAndAnalysis{}
+			set(s, github.Ptr(formatEnabled(*expected)))
+			_, err := EditRepository(ctx, g, repo, &github.Repository{SecurityAndAnalysis: s})
+			return err
+		},
+	}
+}
+
+func securityFeatureCheck(setting string, expected *bool, get func(ctx context.Context, g *GitHubClient, repo repository.Repository) (*RepositorySecurityFeatureStatus, error), enable, disable func(ctx context.Context, g *GitHubClient, repo repository.Repository) error) *repositoryBaselineCheck {
+	return &repositoryBaselineCheck{
+		setting:  setting,
+		expected: formatEnabled(*expected),
+		actual: func(ctx context.Context, g *GitHubClient, repo repository.Repository, _ *github.Repository) (string, error) {
+			status, err := get(ctx, g, repo)
+			if err != nil {
+				return "", err
+			}
+			return formatEnabled(status.Enabled), nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			if *expected {
+				return enable(ctx, g, repo)
+			}
+			return disable(ctx, g, repo)
+		},
+	}
+}
+
+// checks builds the list of baseline checks for the fields set in the profile.
+func (p *RepositoryBaselineProfile) checks() []*repositoryBaselineCheck {
+	var checks []*repositoryBaselineCheck
+	if s := p.Repository; s != nil {
+		boolSettings := []struct {
+			name     string
+			expected *bool
+			get      func(r *github.Repository) bool
+			set      func(u *github.Repository, v *bool)
+		}{
+			{"allow_merge_commit", s.AllowMergeCommit, (*github.Repository).GetAllowMergeCommit, func(u *github.Repository, v *bool) { u.AllowMergeCommit = v }},
+			{"allow_squash_merge", s.AllowSquashMerge, (*github.Repository).GetAllowSquashMerge, func(u *github.Repository, v *bool) { u.AllowSquashMerge = v }},
+			{"allow_rebase_merge", s.AllowRebaseMerge, (*github.Repository).GetAllowRebaseMerge, func(u *github.Repository, v *bool) { u.AllowRebaseMerge = v }},
+			{"allow_auto_merge", s.AllowAutoMerge, (*github.Repository).GetAllowAutoMerge, func(u *github.Repository, v *bool) { u.AllowAutoMerge = v }},
+			{"allow_update_branch", s.AllowUpdateBranch, (*github.Repository).GetAllowUpdateBranch, func(u *github.Repository, v *bool) { u.AllowUpdateBranch = v }},
+			{"delete_branch_on_merge", s.DeleteBranchOnMerge, (*github.Repository).GetDeleteBranchOnMerge, func(u *github.Repository, v *bool) { u.DeleteBranchOnMerge = v }},
+			{"web_commit_signoff_required", s.WebCommitSignoffRequired, (*github.Repository).GetWebCommitSignoffRequired, func(u *github.Repository, v *bool) { u.WebCommitSignoffRequired = v }},
+			{"has_issues", s.HasIssues, (*github.Repository).GetHasIssues, func(u *github.Repository, v *bool) { u.HasIssues = v }},
+			{"has_wiki", s.HasWiki, (*github.Repository).GetHasWiki, func(u *github.Repository, v *bool) { u.HasWiki = v }},
+			{"has_projects", s.HasProjects, (*github.Repository).GetHasProjects, func(u *github.Repository, v *bool) { u.HasProjects = v }},
+			{"has_discussions", s.HasDiscussions, (*github.Repository).GetHasDiscussions, func(u *github.Repository, v *bool) { u.HasDiscussions = v }},
+		}
+		for _, b := range boolSettings {
+			if b.expected != nil {
+				checks = append(checks, repositorySettingBoolCheck(b.name, b.expected, b.get, b.set))
+			}
+		}
+		stringSettings := []struct {
+			name     string
+			expected *string
+			get      func(r *github.Repository) string
+			set      func(u *github.Repository, v *string)
+		}{
+			{"squash_merge_commit_title", s.SquashMergeCommitTitle, (*github.Repository).GetSquashMergeCommitTitle, func(u *github.Repository, v *string) { u.SquashMergeCommitTitle = v }},
+			{"squash_merge_commit_message", s.SquashMergeCommitMessage, (*github.Repository).GetSquashMergeCommitMessage, func(u *github.Repository, v *string) { u.SquashMergeCommitMessage = v }},
+			{"merge_commit_title", s.MergeCommitTitle, (*github.Repository).GetMergeCommitTitle, func(u *github.Repository, v *string) { u.MergeCommitTitle = v }},
+			{"merge_commit_message", s.MergeCommitMessage, (*github.Repository).GetMergeCommitMessage, func(u *github.Repository, v *string) { u.MergeCommitMessage = v }},
+		}
+		for _, st := range stringSettings {
+			if st.expected != nil {
+				checks = append(checks, repositorySettingStringCheck(st.name, st.expected, st.get, st.set))
+			}
+		}
+	}
+
+	if s := p.Security; s != nil {
+		if s.AdvancedSecurity != nil {
+			checks = append(checks, securityAndAnalysisCheck("advanced_security", s.AdvancedSecurity,
+				func(sa *github.SecurityAndAnalysis) string { return sa.GetAdvancedSecurity().GetStatus() },
+				func(sa *github.SecurityAndAnalysis, status *string) {
+					sa.AdvancedSecurity = &github.AdvancedSecurity{Status: status}
+				}))
+		}
+		if s.SecretScanning != nil {
+			checks = append(checks, securityAndAnalysisCheck("secret_scanning", s.SecretScanning,
+				func(sa *github.SecurityAndAnalysis) string { return sa.GetSecretScanning().GetStatus() },
+				func(sa *github.SecurityAndAnalysis, status *string) {
+					sa.SecretScanning = &github.SecretScanning{Status: status}
+				}))
+		}
+		if s.SecretScanningPushProtection != nil {
+			checks = append(checks, securityAndAnalysisCheck("secret_scanning_push_protection", s.SecretScanningPushProtection,
+				func(sa *github.SecurityAndAnalysis) string { return sa.GetSecretScanningPushProtection().GetStatus() },
+				func(sa *github.SecurityAndAnalysis, status *string) {
+					sa.SecretScanningPushProtection = &github.SecretScanningPushProtection{Status: status}
+				}))
+		}
+		if s.VulnerabilityAlerts != nil {
+			checks = append(checks, securityFeatureCheck("vulnerability_alerts", s.VulnerabilityAlerts,
+				GetVulnerabilityAlerts, EnableVulnerabilityAlerts, DisableVulnerabilityAlerts))
+		}
+		if s.AutomatedSecurityFixes != nil {
+			checks = append(checks, securityFeatureCheck("automated_security_fixes", s.AutomatedSecurityFixes,
+				GetAutomatedSecurityFixes, EnableAutomatedSecurityFixes, DisableAutomatedSecurityFixes))
+		}
+		if s.PrivateVulnerabilityReporting != nil {
+			checks = append(checks, securityFeatureCheck("private_vulnerability_reporting", s.PrivateVulnerabilityReporting,
+				GetPrivateVulnerabilityReporting, EnablePrivateVulnerabilityReporting, DisablePrivateVulnerabilityReporting))
+		}
+		if s.DependencyGraph != nil {
+			checks = append(checks, dependencyGraphCheck(s.DependencyGraph, s.DependencyGraphOrganizationWide))
+		}
+		if s.CodeScanningDefaultSetup != nil {
+			checks = append(checks, codeScanningDefaultSetupCheck(s.CodeScanningDefaultSetup, s.CodeScanningQuerySuite))
+		}
+		if s.CodeQualitySetup != nil {
+			checks = append(checks, codeQualitySetupCheck(s.CodeQualitySetup))
+		}
+	}
+
+	if d := p.DeployKeys; d != nil {
+		if d.Allowed != nil && !*d.Allowed {
+			checks = append(checks, deployKeyCheck("deploy_keys", "none", func(key *github.Key) bool { return true }))
+		}
+		if d.ReadOnly != nil && *d.ReadOnly {
+			checks = append(checks, deployKeyCheck("deploy_keys_read_only", "all read-only", func(key *github.Key) bool { return !key.GetReadOnly() }))
+		}
+	}
+	return checks
+}
+
+// dependencyGraphCheck audits the dependency graph through the SBOM endpoint, which returns 404 when it is disabled.
+// There is no per-repository API, so the fix changes the whole organization and is refused unless orgWide is set.
+func dependencyGraphCheck(expected *bool, orgWide bool) *repositoryBaselineCheck {
+	return &repositoryBaselineCheck{
+		setting:  "dependency_graph",
+		expected: formatEnabled(*expected),
+		actual: func(ctx context.Context, g *GitHubClient, repo repository.Repository, _ *github.Repository) (string, error) {
+			_, err := GetRepositoryDependencyGraphSBOM(ctx, g, repo)
+			if err != nil {
+				if IsHTTPNotFound(err) {
+					return formatEnabled(false), nil
+				}
+				return "", err
+			}
+			return formatEnabled(true), nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			if !orgWide {
+				return fmt.Errorf("dependency graph can only be changed for the whole organization %s; set dependency_graph_organization_wide to apply it", repo.Owner)
+			}
+			owner := repository.Repository{Host: repo.Host, Owner: repo.Owner}
+			if *expected {
+				return EnableDependencyGraph(ctx, g, owner)
+			}
+			return DisableDependencyGraph(ctx, g, owner)
+		},
+	}
+}
+
+func codeScanningDefaultSetupCheck(state *string, querySuite *string) *repositoryBaselineCheck {
+	expected := *state
+	if querySuite != nil && *state == "configured" {
+		expected = *state + "/" + *querySuite
+	}
+	return &repositoryBaselineCheck{
+		setting:  "code_scanning_default_setup",
+		expected: expected,
+		actual: func(ctx context.Context, g *GitHubClient, repo repository.Repository, _ *github.Repository) (string, error) {
+			config, err := GetCodeScanningDefaultSetupConfiguration(ctx, g, repo)
+			if err != nil {
+				return "", err
+			}
+			actual := config.GetState()
+			if querySuite != nil && actual == "configured" {
+				actual = actual + "/" + config.GetQuerySuite()
+			}
+			return actual, nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			opts := &UpdateCodeScanningDefaultSetupConfigurationOptions{State: *state}
+			if querySuite != nil {
+				opts.QuerySuite = *querySuite
+			}
+			_, err := UpdateCodeScanningDefaultSetupConfiguration(ctx, g, repo, opts)
+			return err
+		},
+	}
+}
+
+func codeQualitySetupCheck(state *string) *repositoryBaselineCheck {
+	return &repositoryBaselineCheck{
+		setting:  "code_quality_setup",
+		expected: *state,
+		actual: func(ctx context.Context, g *GitHubClient, repo repository.Repository, _ *github.Repository) (string, error) {
+			setup, err := GetCodeQualitySetup(ctx, g, repo)
+			if err != nil {
+				return "", err
+			}
+			return setup.State, nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			return UpdateCodeQualitySetup(ctx, g, repo, &UpdateCodeQualitySetupOptions{State: *state})
+		},
+	}
+}
+
+// deployKeyCheck audits deploy keys against a violation predicate. Deploy keys cannot be edited,
+// so the fix deletes every violating key.
+func deployKeyCheck(setting string, expected string, violates func(key *github.Key) bool) *repositoryBaselineCheck {
+	violating := func(ctx context.Context, g *GitHubClient, repo repository.Repository) ([]*github.Key, error) {
+		keys, err := ListDeployKeys(ctx, g, repo)
+		if err != nil {
+			return nil, err
+		}
+		var result []*github.Key
+		for _, key := range keys {
+			if violates(key) {
+				result = append(result, key)
+			}
+		}
+		return result, nil
+	}
+	return &repositoryBaselineCheck{
+		setting:  setting,
+		expected: expected,
+		actual: func(ctx context.Context, g *GitHubClient, repo repository.Repository, _ *github.Repository) (string, error) {
+			keys, err := violating(ctx, g, repo)
+			if err != nil {
+				return "", err
+			}
+			if len(keys) == 0 {
+				return expected, nil
+			}
+			return fmt.Sprintf("%d violating key(s)", len(keys)), nil
+		},
+		fix: func(ctx context.Context, g *GitHubClient, repo repository.Repository) error {
+			keys, err := violating(ctx, g, repo)
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				if err := DeleteDeployKey(ctx, g, repo, key.GetID()); err != nil {
+					return err
+				}
+				logger.Info("Deleted deploy key violating baseline", "repository", repo.Owner+"/"+repo.Name, "title", key.GetTitle())
+			}
+			return nil
+		},
+	}
+}
+
+// AuditRepositoryBaseline checks a repository against the baseline profile and returns one result per setting.
+// Settings that cannot be read are reported with Error set and are treated as non-compliant.
+func AuditRepositoryBaseline(ctx context.Context, g *GitHubClient, repo repository.Repository, profile *RepositoryBaselineProfile) ([]*RepositoryBaselineResult, error) {
+	r, err := GetRepository(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	fullName := repo.Owner + "/" + repo.Name
+	var results []*RepositoryBaselineResult
+	for _, check := range profile.checks() {
+		result := &RepositoryBaselineResult{
+			Repository: fullName,
+			Setting:    check.setting,
+			Expected:   check.expected,
+			repo:       repo,
+			fix:        check.fix,
+		}
+		actual, err := check.actual(ctx, g, repo, r)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Actual = actual
+			result.Compliant = actual == check.expected
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// AuditOrganizationBaseline audits every non-archived repository of the owner against the baseline profile.
+// Repositories that cannot be read are logged and skipped.
+func AuditOrganizationBaseline(ctx context.Context, g *GitHubClient, repo repository.Repository, profile *RepositoryBaselineProfile) ([]*RepositoryBaselineResult, error) {
+	repos, err := ListOwnerRepositories(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	var results []*RepositoryBaselineResult
+	for _, r := range repos {
+		if r.GetArchived() {
+			continue
+		}
+		target := repository.Repository{Host: repo.Host, Owner: r.GetOwner().GetLogin(), Name: r.GetName()}
+		repoResults, err := AuditRepositoryBaseline(ctx, g, target, profile)
+		if err != nil {
+			logger.Warn("Failed to audit repository baseline", "repository", r.GetFullName(), "error", err)
+			continue
+		}
+		results = append(results, repoResults...)
+	}
+	return results, nil
+}
+
+// EnforceRepositoryBaseline fixes the non-compliant results returned by an audit.
+// Results that failed to be read are not touched. Organization-wide settings such as the dependency graph
+// are fixed once per owner, and only when the profile opts in. When dryRun is true, nothing is written.
+func EnforceRepositoryBaseline(ctx context.Context, g *GitHubClient, results []*RepositoryBaselineResult, dryRun bool) {
+	orgWideFixed := map[string]error{}
+	for _, result := range results {
+		if result.Compliant || result.Error != "" || result.fix == nil {
+			continue
+		}
+		if dryRun {
+			logger.Info("Would fix repository baseline setting", "repository", result.Repository, "setting", result.Setting, "expected", result.Expected, "actual", result.Actual)
+			continue
+		}
+		var err error
+		if result.Setting == "dependency_graph" {
+			key := result.repo.Host + "/" + result.repo.Owner
+			var done bool
+			if err, done = orgWideFixed[key]; !done {
+				err = result.fix(ctx, g, result.repo)
+				orgWideFixed[key] = err
+			}
+		} else {
+			err = result.fix(ctx, g, result.repo)
+		}
+		if err != nil {
+			logger.Warn("Failed to fix repository baseline setting", "repository", result.Repository, "setting", result.Setting, "error", err)
+			result.Error = err.Error()
+			continue
+		}
+		result.Fixed = true
+	}
+}
+
+// FilterNonCompliantBaselineResults returns the results that are not compliant.
+func FilterNonCompliantBaselineResults(results []*RepositoryBaselineResult) []*RepositoryBaselineResult {
+	var filtered []*RepositoryBaselineResult
+	for _, result := range results {
+		if !result.Compliant {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,103 @@
+package gh
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+)
+
+func TestLoadRepositoryBaselineProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	content := `repository:
+  allow_merge_commit: false
+  delete_branch_on_merge: true
+security:
+  secret_scanning: true
+  code_scanning_default_setup: configured
+deploy_keys:
+  read_only: true
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := LoadRepositoryBaselineProfile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checks := profile.checks()
+	want := []string{"allow_merge_commit", "delete_branch_on_merge", "secret_scanning", "code_scanning_default_setup", "deploy_keys_read_only"}
+	if len(checks) != len(want) {
+		t.Fatalf("expected %d checks, got %d", len(want), len(checks))
+	}
+	for i, check := range checks {
+		if check.setting != want[i] {
+			t.Errorf("check[%d] = %q, want %q", i, check.setting, want[i])
+		}
+	}
+}
+
+func TestRepositoryBaselineProfile_RepositoryChecks(t *testing.T) {
+	profile := &RepositoryBaselineProfile{
+		Repository: &RepositoryBaselineSettings{
+			AllowMergeCommit:    github.Ptr(false),
+			DeleteBranchOnMerge: github.Ptr(true),
+			MergeCommitTitle:    github.Ptr("PR_TITLE"),
+		},
+		Security: &RepositorySecurityBaseline{
+			SecretScanning:   github.Ptr(true),
+			AdvancedSecurity: github.Ptr(true),
+		},
+	}
+	r := &github.Repository{
+		AllowMergeCommit:    github.Ptr(true),
+		DeleteBranchOnMerge: github.Ptr(true),
+		MergeCommitTitle:    github.Ptr("MERGE_MESSAGE"),
+		SecurityAndAnalysis: &github.SecurityAndAnalysis{
+			SecretScanning: &github.SecretScanning{Status: github.Ptr("enabled")},
+		},
+	}
+	want := map[string]bool{
+		"allow_merge_commit":     false,
+		"delete_branch_on_merge": true,
+		"merge_commit_title":     false,
+		"secret_scanning":        true,
+		"advanced_security":      false,
+	}
+	checks := profile.checks()
+	if len(checks) != len(want) {
+		t.Fatalf("expected %d checks, got %d", len(want), len(checks))
+	}
+	for _, check := range checks {
+		actual, err := check.actual(context.Background(), nil, repository.Repository{}, r)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", check.setting, err)
+		}
+		if got := actual == check.expected; got != want[check.setting] {
+			t.Errorf("%s: compliant = %v (expected %q, actual %q), want %v", check.setting, got, check.expected, actual, want[check.setting])
+		}
+	}
+}
+
+func TestDependencyGraphCheck_RequiresOrganizationWideOptIn(t *testing.T) {
+	check := dependencyGraphCheck(github.Ptr(true), false)
+	err := check.fix(context.Background(), nil, repository.Repository{Owner: "octo-org", Name: "repo"})
+	if err == nil || !strings.Contains(err.Error(), "dependency_graph_organization_wide") {
+		t.Errorf("fix() error = %v, want the organization-wide opt-in error", err)
+	}
+}
+
+func TestFilterNonCompliantBaselineResults(t *testing.T) {
+	results := []*RepositoryBaselineResult{
+		{Setting: "a", Compliant: true},
+		{Setting: "b", Compliant: false},
+	}
+	filtered := FilterNonCompliantBaselineResults(results)
+	if len(filtered) != 1 || filtered[0].Setting != "b" {
+		t.Errorf("FilterNonCompliantBaselineResults() = %+v", filtered)
+	}
+}
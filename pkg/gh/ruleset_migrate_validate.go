@@ -0,0 +1,445 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/settings"
+)
+
+const (
+	RulesetMigrationKindBypassActor           = "bypass_actor"
+	RulesetMigrationKindStatusCheck           = "status_check"
+	RulesetMigrationKindRepository            = "repository"
+	RulesetMigrationKindWorkflow              = "workflow"
+	RulesetMigrationKindDeploymentEnvironment = "deployment_environment"
+)
+
+const (
+	// RulesetMigrationStatusResolved means the reference exists in the destination as-is.
+	RulesetMigrationStatusResolved = "resolved"
+	// RulesetMigrationStatusMapped means the reference was found in the destination under a different ID.
+	RulesetMigrationStatusMapped = "mapped"
+	// RulesetMigrationStatusUnresolved means the reference could not be found in the destination
+	// and will be dropped or degraded by TransformMigrateRuleset.
+	RulesetMigrationStatusUnresolved = "unresolved"
+	// RulesetMigrationStatusUnchecked means the reference type cannot be verified through the API.
+	RulesetMigrationStatusUnchecked = "unchecked"
+)
+
+// RulesetMigrationReference describes how a single reference in a ruleset resolves against the migration destination.
+type RulesetMigrationReference struct {
+	Kind          string `json:"kind"`
+	Type          string `json:"type,omitempty"`
+	Source        string `json:"source"`
+	SourceID      *int64 `json:"source_id,omitempty"`
+	Status        string `json:"status"`
+	Destination   string `json:"destination,omitempty"`
+	DestinationID *int64 `json:"destination_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Suggestion    string `json:"suggestion,omitempty"`
+	// SuggestedLogin is the destination login proposed for an unresolved User bypass actor.
+	SuggestedLogin string `json:"suggested_login,omitempty"`
+}
+
+// RulesetMigrationReport is the result of a pre-flight validation of a ruleset migration.
+type RulesetMigrationReport struct {
+	Ruleset     string                       `json:"ruleset"`
+	Destination string                       `json:"destination"`
+	References  []*RulesetMigrationReference `json:"references"`
+}
+
+// Unresolved returns the references that could not be resolved in the destination.
+func (r *RulesetMigrationReport) Unresolved() []*RulesetMigrationReference {
+	var refs []*RulesetMigrationReference
+	for _, ref := range r.References {
+		if ref.Status == RulesetMigrationStatusUnresolved {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// HasUnresolved reports whether the report contains unresolved references.
+func (r *RulesetMigrationReport) HasUnresolved() bool {
+	return len(r.Unresolved()) > 0
+}
+
+// SuggestedUserMappings returns usermap entries for unresolved User bypass actors that have a suggested
+// destination login. The result can be written with settings.Write and passed back as a usermap.
+func (r *RulesetMigrationReport) SuggestedUserMappings() []settings.UserMapping {
+	var mappings []settings.UserMapping
+	for _, ref := range r.Unresolved() {
+		if ref.Kind != RulesetMigrationKindBypassActor || ref.Type != string(bypassActorTypeUser) || ref.SuggestedLogin == "" {
+			continue
+		}
+		mappings = append(mappings, settings.UserMapping{Src: ref.Source, Dst: ref.SuggestedLogin})
+	}
+	return mappings
+}
+
+func (r *RulesetMigrationReport) add(ref *RulesetMigrationReference) {
+	r.References = append(r.References, ref)
+}
+
+// ValidateMigrateRuleset resolves every reference of a migrate config against the destination without writing anything.
+// It checks bypass actors, required status check integrations, repository ID conditions, required workflow
+// repositories and required deployment environments, following the same resolution rules as TransformMigrateRuleset.
+// The migrate config is not modified. resolve is the optional usermap lookup used for User bypass actors.
+func ValidateMigrateRuleset(ctx context.Context, g *GitHubClient, repo repository.Repository, migrateConfig *RepositoryRulesetMigrateConfig, gitHubActionsAppID *int64, resolve func(string) (string, bool)) (*RulesetMigrationReport, error) {
+	if migrateConfig == nil || migrateConfig.Ruleset == nil {
+		return nil, fmt.Errorf("migrate config has no ruleset")
+	}
+	ruleset := migrateConfig.Ruleset
+	destination := repo.Owner
+	if repo.Name != "" {
+		destination = repo.Owner + "/" + repo.Name
+	}
+	report := &RulesetMigrationReport{
+		Ruleset:     ruleset.Name,
+		Destination: destination,
+	}
+
+	org, err := GetOrganizationProfile(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRulesetBypassActors(ctx, g, repo, report, migrateConfig, org, resolve); err != nil {
+		return nil, err
+	}
+	migrateRepositories := validateRulesetRepositories(ctx, g, repo, report, migrateConfig)
+	validateRulesetWorkflows(report, ruleset, migrateConfig, migrateRepositories)
+	if err := validateRulesetRequiredStatusChecks(ctx, g, repo, report, migrateConfig, org, gitHubActionsAppID, migrateRepositories); err != nil {
+		return nil, err
+	}
+	validateRulesetRequiredDeployments(ctx, g, repo, report, ruleset)
+
+	return report, nil
+}
+
+func validateRulesetBypassActors(ctx context.Context, g *GitHubClient, repo repository.Repository, report *RulesetMigrationReport, migrateConfig *RepositoryRulesetMigrateConfig, org *OrganizationProfile, resolve func(string) (string, bool)) error {
+	var dstTeams []*github.Team
+	teamsLoaded := false
+	getTeamBySlug := func(slug string) (*github.Team, error) {
+		return GetTeamBySlug(ctx, g, repo, slug)
+	}
+	for _, actor := range migrateConfig.Ruleset.BypassActors {
+		if actor.ActorType == nil {
+			continue
+		}
+		ref := &RulesetMigrationReference{
+			Kind:     RulesetMigrationKindBypassActor,
+			Type:     string(*actor.ActorType),
+			SourceID: actor.ActorID,
+			Source:   formatOptionalID(actor.ActorID),
+		}
+		report.add(ref)
+
+		switch *actor.ActorType {
+		case github.BypassActorTypeOrganizationAdmin:
+			ref.Source = "organization admin"
+			if org.IsUser() {
+				ref.Status = RulesetMigrationStatusUnresolved
+				ref.Reason = "organization admin bypass is not supported on user accounts"
+			} else {
+				ref.Status = RulesetMigrationStatusResolved
+			}
+		case bypassActorTypeUser:
+			validateRulesetBypassUser(ctx, g, ref, actor, migrateConfig, resolve)
+		case github.BypassActorTypeTeam:
+			if !teamsLoaded {
+				teamTree, err := TeamByOwner(ctx, g, repo, true)
+				if err != nil {
+					return fmt.Errorf("failed to list teams of %s: %w", repo.Owner, err)
+				}
+				dstTeams = teamTree.Flatten()
+				teamsLoaded = true
+			}
+			if err := validateRulesetBypassTeam(ref, actor, migrateConfig, dstTeams, getTeamBySlug); err != nil {
+				return err
+			}
+		default:
+			ref.Status = RulesetMigrationStatusUnchecked
+			ref.Reason = "actor type cannot be verified in the destination"
+		}
+	}
+	return nil
+}
+
+func validateRulesetBypassUser(ctx context.Context, g *GitHubClient, ref *RulesetMigrationReference, actor *github.BypassActor, migrateConfig *RepositoryRulesetMigrateConfig, resolve func(string) (string, bool)) {
+	srcUser := migrateConfig.Users[actor.GetActorID()]
+	srcLogin := srcUser.GetLogin()
+	if srcLogin == "" {
+		ref.Status = RulesetMigrationStatusUnresolved
+		ref.Reason = "source user login is unknown"
+		return
+	}
+	ref.Source = srcLogin
+
+	if resolve != nil {
+		if dstLogin, ok := resolve(srcLogin); ok {
+			dstUser, err := FindUser(ctx, g, dstLogin)
+			if err != nil {
+				ref.Status = RulesetMigrationStatusUnresolved
+				ref.Reason = fmt.Sprintf("usermap destination %q not found", dstLogin)
+				ref.Suggestion = "fix the usermap entry for this user"
+				return
+			}
+			ref.Status = RulesetMigrationStatusMapped
+			ref.Destination = dstUser.GetLogin()
+			ref.DestinationID = dstUser.ID
+			return
+		}
+	}
+
+	// Without a mapping the original actor_id is kept, which is only valid if it refers to the same user.
+	if dstUser, err := FindUserByID(ctx, g, actor.GetActorID()); err == nil && dstUser.GetLogin() == srcLogin {
+		ref.Status = RulesetMigrationStatusResolved
+		ref.Destination = dstUser.GetLogin()
+		ref.DestinationID = dstUser.ID
+		return
+	}
+	ref.Status = RulesetMigrationStatusUnresolved
+	ref.Reason = "user is not mapped and actor_id does not refer to the same user in the destination"
+	if dstUser, err := FindUser(ctx, g, srcLogin); err == nil {
+		ref.SuggestedLogin = dstUser.GetLogin()
+		ref.Suggestion = fmt.Sprintf("add usermap entry %s -> %s", srcLogin, dstUser.GetLogin())
+	} else {
+		ref.Suggestion = fmt.Sprintf("add usermap entry for %s", srcLogin)
+	}
+}
+
+// validateRulesetBypassTeam resolves a team bypass actor like TransformMigrateRuleset: the actor ID is kept
+// when it is a team of the destination, otherwise the source team is looked up by slug with getTeamBySlug.
+// dstTeams are the teams of the destination, used to match IDs and to suggest similar teams.
+func validateRulesetBypassTeam(ref *RulesetMigrationReference, actor *github.BypassActor, migrateConfig *RepositoryRulesetMigrateConfig, dstTeams []*github.Team, getTeamBySlug func(slug string) (*github.Team, error)) error {
+	srcTeam := migrateConfig.Teams[actor.GetActorID()]
+	if srcTeam != nil {
+		ref.Source = srcTeam.GetSlug()
+	}
+	for _, t := range dstTeams {
+		if t.GetID() == actor.GetActorID() {
+			ref.Status = RulesetMigrationStatusResolved
+			ref.Destination = t.GetSlug()
+			ref.DestinationID = t.ID
+			return nil
+		}
+	}
+	if srcTeam == nil {
+		ref.Status = RulesetMigrationStatusUnresolved
+		ref.Reason = "source team is unknown"
+		return nil
+	}
+	t, err := getTeamBySlug(srcTeam.GetSlug())
+	if err == nil {
+		ref.Status = RulesetMigrationStatusMapped
+		ref.Destination = t.GetSlug()
+		ref.DestinationID = t.ID
+		return nil
+	}
+	if !IsHTTPNotFound(err) {
+		return fmt.Errorf("failed to get team %s: %w", srcTeam.GetSlug(), err)
+	}
+	ref.Status = RulesetMigrationStatusUnresolved
+	ref.Reason = "team with the same slug not found in the destination"
+	if candidates := suggestTeams(srcTeam, dstTeams); len(candidates) > 0 {
+		ref.Suggestion = "similar teams: " + strings.Join(candidates, ", ")
+	} else {
+		ref.Suggestion = fmt.Sprintf("create team %s in the destination", srcTeam.GetSlug())
+	}
+	return nil
+}
+
+// suggestTeams returns destination team slugs whose name or slug resembles the source team.
+func suggestTeams(srcTeam *github.Team, dstTeams []*github.Team) []string {
+	slug := strings.ToLower(srcTeam.GetSlug())
+	name := strings.ToLower(srcTeam.GetName())
+	var candidates []string
+	for _, t := range dstTeams {
+		dstSlug := strings.ToLower(t.GetSlug())
+		dstName := strings.ToLower(t.GetName())
+		if dstName == name || strings.Contains(dstSlug, slug) || strings.Contains(slug, dstSlug) {
+			candidates = append(candidates, t.GetSlug())
+		}
+	}
+	return candidates
+}
+
+// validateRulesetRepositories resolves repositories referenced by ID (conditions and workflows) in the destination owner.
+// It returns the mapping from source repository ID to destination repository for the resolved ones.
+func validateRulesetRepositories(ctx context.Context, g *GitHubClient, repo repository.Repository, report *RulesetMigrationReport, migrateConfig *RepositoryRulesetMigrateConfig) map[int64]*github.Repository {
+	migrateRepositories := map[int64]*github.Repository{}
+	for id, r := range migrateConfig.Repositories {
+		ref := &RulesetMigrationReference{
+			Kind:     RulesetMigrationKindRepository,
+			Source:   r.GetFullName(),
+			SourceID: github.Ptr(id),
+		}
+		report.add(ref)
+		dstRepo, err := g.GetRepository(ctx, repo.Owner, r.GetName())
+		if err != nil {
+			ref.Status = RulesetMigrationStatusUnresolved
+			ref.Reason = "repository not found in the destination owner"
+			ref.Suggestion = fmt.Sprintf("create or transfer %s to %s", r.GetName(), repo.Owner)
+			continue
+		}
+		ref.Status = RulesetMigrationStatusMapped
+		if dstRepo.GetID() == id {
+			ref.Status = RulesetMigrationStatusResolved
+		}
+		ref.Destination = dstRepo.GetFullName()
+		ref.DestinationID = dstRepo.ID
+		migrateRepositories[id] = dstRepo
+	}
+	return migrateRepositories
+}
+
+func validateRulesetWorkflows(report *RulesetMigrationReport, ruleset *github.RepositoryRuleset, migrateConfig *RepositoryRulesetMigrateConfig, migrateRepositories map[int64]*github.Repository) {
+	if ruleset.Rules == nil || ruleset.Rules.Workflows == nil {
+		return
+	}
+	for _, workflow := range ruleset.Rules.Workflows.Workflows {
+		ref := &RulesetMigrationReference{
+			Kind:     RulesetMigrationKindWorkflow,
+			Source:   workflow.Path,
+			SourceID: workflow.RepositoryID,
+		}
+		report.add(ref)
+		if r, ok := migrateConfig.Repositories[workflow.GetRepositoryID()]; ok {
+			ref.Source = r.GetFullName() + "/" + workflow.Path
+		}
+		dstRepo, ok := migrateRepositories[workflow.GetRepositoryID()]
+		if !ok {
+			ref.Status = RulesetMigrationStatusUnresolved
+			ref.Reason = "workflow repository not found in the destination; the workflow will be removed"
+			continue
+		}
+		ref.Status = RulesetMigrationStatusMapped
+		ref.Destination = dstRepo.GetFullName() + "/" + workflow.Path
+		ref.DestinationID = dstRepo.ID
+	}
+}
+
+func validateRulesetRequiredStatusChecks(ctx context.Context, g *GitHubClient, repo repository.Repository, report *RulesetMigrationReport, migrateConfig *RepositoryRulesetMigrateConfig, org *OrganizationProfile, gitHubActionsAppID *int64, migrateRepositories map[int64]*github.Repository) error {
+	ruleset := migrateConfig.Ruleset
+	if ruleset.Rules == nil || ruleset.Rules.RequiredStatusChecks == nil {
+		return nil
+	}
+
+	checkRunRepo, err := resolveMigrationCheckRunRepository(ctx, g, repo, ruleset, migrateRepositories)
+	if err != nil {
+		return err
+	}
+	ref := "HEAD"
+	if checkRunRepo != nil {
+		ref = resolveStatusCheckRunRef(ctx, g, *checkRunRepo, ruleset)
+	}
+
+	for _, check := range ruleset.Rules.RequiredStatusChecks.RequiredStatusChecks {
+		item := &RulesetMigrationReference{
+			Kind:     RulesetMigrationKindStatusCheck,
+			Source:   check.Context,
+			SourceID: check.IntegrationID,
+		}
+		report.add(item)
+		if check.IntegrationID == nil {
+			item.Status = RulesetMigrationStatusResolved
+			item.Reason = "any source"
+			continue
+		}
+		if checkRunRepo == nil {
+			item.Status = RulesetMigrationStatusUnresolved
+			item.Reason = "no destination repository available to resolve check runs"
+			item.Suggestion = "add a repository condition so that a target repository can be resolved"
+			continue
+		}
+		found, err := findIntegrationID(ctx, g, *checkRunRepo, ref, check.Context, check.IntegrationID, nil)
+		if err == nil && found != nil {
+			item.Status = RulesetMigrationStatusResolved
+			item.DestinationID = check.IntegrationID
+			continue
+		}
+		checkRun := migrateConfig.CheckRuns[*check.IntegrationID]
+		if checkRun != nil {
+			found, err = findIntegrationID(ctx, g, *checkRunRepo, ref, check.Context, nil, checkRun.App)
+			if err == nil && found != nil && found.App != nil {
+				item.Status = RulesetMigrationStatusMapped
+				item.Destination = found.App.GetSlug()
+				item.DestinationID = found.App.ID
+				continue
+			}
+			if checkRun.App != nil && checkRun.App.GetSlug() == "github-actions" {
+				if actionAppID := resolveGitHubActionsAppID(gitHubActionsAppID, org); actionAppID != nil {
+					item.Status = RulesetMigrationStatusMapped
+					item.Destination = "github-actions"
+					item.DestinationID = actionAppID
+					continue
+				}
+				item.Suggestion = "specify the GitHub Actions app ID of the destination"
+			}
+		}
+		item.Status = RulesetMigrationStatusUnresolved
+		item.Reason = fmt.Sprintf("integration not found on %s@%s; the check will accept any source", checkRunRepo.Name, ref)
+		if item.Suggestion == "" && checkRun != nil && checkRun.App != nil {
+			item.Suggestion = fmt.Sprintf("install the %s app in the destination or run the check once", checkRun.App.GetSlug())
+		}
+	}
+	return nil
+}
+
+// resolveMigrationCheckRunRepository picks the destination repository used to look up check runs.
+// Repository ID conditions are resolved through the migration repository mapping because the source IDs
+// are not valid in the destination.
+func resolveMigrationCheckRunRepository(ctx context.Context, g *GitHubClient, repo repository.Repository, ruleset *github.RepositoryRuleset, migrateRepositories map[int64]*github.Repository) (*repository.Repository, error) {
+	if repo.Name != "" {
+		return &repo, nil
+	}
+	if ruleset.Conditions != nil && ruleset.Conditions.RepositoryID != nil {
+		for _, id := range ruleset.Conditions.RepositoryID.RepositoryIDs {
+			if r, ok := migrateRepositories[id]; ok {
+				return &repository.Repository{Host: repo.Host, Owner: repo.Owner, Name: r.GetName()}, nil
+			}
+		}
+		return nil, nil
+	}
+	return GetRulesetTargetRepository(ctx, g, repo, ruleset)
+}
+
+func validateRulesetRequiredDeployments(ctx context.Context, g *GitHubClient, repo repository.Repository, report *RulesetMigrationReport, ruleset *github.RepositoryRuleset) {
+	if ruleset.Rules == nil || ruleset.Rules.RequiredDeployments == nil {
+		return
+	}
+	for _, env := range ruleset.Rules.RequiredDeployments.RequiredDeploymentEnvironments {
+		ref := &RulesetMigrationReference{
+			Kind:   RulesetMigrationKindDeploymentEnvironment,
+			Source: env,
+		}
+		report.add(ref)
+		if repo.Name == "" {
+			ref.Status = RulesetMigrationStatusUnresolved
+			ref.Reason = "required deployments are not supported for organization rulesets"
+			continue
+		}
+		deployments, err := ListEnvironmentDeployments(ctx, g, repo, env)
+		if err != nil || len(deployments) == 0 {
+			ref.Status = RulesetMigrationStatusUnresolved
+			ref.Reason = "no deployments found for the environment in the destination"
+			ref.Suggestion = fmt.Sprintf("create environment %s and deploy to it once", env)
+			continue
+		}
+		ref.Status = RulesetMigrationStatusResolved
+		ref.Destination = env
+	}
+}
+
+func formatOptionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
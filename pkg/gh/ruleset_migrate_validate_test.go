@@ -0,0 +1,122 @@
+package gh
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func TestValidateRulesetBypassTeam(t *testing.T) {
+	migrateConfig := &RepositoryRulesetMigrateConfig{
+		Teams: map[int64]*github.Team{
+			1: {ID: github.Ptr(int64(1)), Slug: github.Ptr("platform"), Name: github.Ptr("Platform")},
+			2: {ID: github.Ptr(int64(2)), Slug: github.Ptr("security"), Name: github.Ptr("Security")},
+			3: {ID: github.Ptr(int64(3)), Slug: github.Ptr("release"), Name: github.Ptr("Release")},
+		},
+	}
+	dstTeams := []*github.Team{
+		{ID: github.Ptr(int64(1)), Slug: github.Ptr("platform"), Name: github.Ptr("Platform")},
+		{ID: github.Ptr(int64(20)), Slug: github.Ptr("security"), Name: github.Ptr("Security")},
+		{ID: github.Ptr(int64(30)), Slug: github.Ptr("release-managers"), Name: github.Ptr("Release Managers")},
+	}
+	tests := []struct {
+		actorID        int64
+		wantStatus     string
+		wantDstID      int64
+		wantSuggestion string
+	}{
+		{actorID: 1, wantStatus: RulesetMigrationStatusResolved, wantDstID: 1},
+		{actorID: 2, wantStatus: RulesetMigrationStatusMapped, wantDstID: 20},
+		{actorID: 3, wantStatus: RulesetMigrationStatusUnresolved, wantSuggestion: "similar teams: release-managers"},
+		{actorID: 4, wantStatus: RulesetMigrationStatusUnresolved},
+	}
+	getTeamBySlug := func(slug string) (*github.Team, error) {
+		for _, t := range dstTeams {
+			if t.GetSlug() == slug {
+				return t, nil
+			}
+		}
+		return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	}
+	for _, tt := range tests {
+		ref := &RulesetMigrationReference{}
+		actor := &github.BypassActor{ActorID: github.Ptr(tt.actorID), ActorType: github.Ptr(github.BypassActorTypeTeam)}
+		if err := validateRulesetBypassTeam(ref, actor, migrateConfig, dstTeams, getTeamBySlug); err != nil {
+			t.Fatalf("actor %d: unexpected error: %v", tt.actorID, err)
+		}
+		if ref.Status != tt.wantStatus {
+			t.Errorf("actor %d: status = %q, want %q", tt.actorID, ref.Status, tt.wantStatus)
+		}
+		if tt.wantDstID != 0 && (ref.DestinationID == nil || *ref.DestinationID != tt.wantDstID) {
+			t.Errorf("actor %d: destination id = %v, want %d", tt.actorID, ref.DestinationID, tt.wantDstID)
+		}
+		if tt.wantSuggestion != "" && ref.Suggestion != tt.wantSuggestion {
+			t.Errorf("actor %d: suggestion = %q, want %q", tt.actorID, ref.Suggestion, tt.wantSuggestion)
+		}
+	}
+}
+
+func TestValidateRulesetBypassTeam_LookupError(t *testing.T) {
+	migrateConfig := &RepositoryRulesetMigrateConfig{
+		Teams: map[int64]*github.Team{1: {ID: github.Ptr(int64(1)), Slug: github.Ptr("platform")}},
+	}
+	failure := errors.New("boom")
+	ref := &RulesetMigrationReference{}
+	actor := &github.BypassActor{ActorID: github.Ptr(int64(1)), ActorType: github.Ptr(github.BypassActorTypeTeam)}
+	err := validateRulesetBypassTeam(ref, actor, migrateConfig, nil, func(string) (*github.Team, error) { return nil, failure })
+	if !errors.Is(err, failure) {
+		t.Errorf("err = %v, want %v", err, failure)
+	}
+}
+
+func TestValidateRulesetWorkflows(t *testing.T) {
+	ruleset := &github.RepositoryRuleset{
+		Rules: &github.RepositoryRulesetRules{
+			Workflows: &github.WorkflowsRuleParameters{
+				Workflows: []*github.RuleWorkflow{
+					{Path: ".github/workflows/ci.yml", RepositoryID: github.Ptr(int64(10))},
+					{Path: ".github/workflows/lint.yml", RepositoryID: github.Ptr(int64(11))},
+				},
+			},
+		},
+	}
+	migrateConfig := &RepositoryRulesetMigrateConfig{
+		Repositories: map[int64]*github.Repository{
+			10: {FullName: github.Ptr("src/ci")},
+			11: {FullName: github.Ptr("src/lint")},
+		},
+	}
+	migrateRepositories := map[int64]*github.Repository{
+		10: {ID: github.Ptr(int64(100)), FullName: github.Ptr("dst/ci")},
+	}
+	report := &RulesetMigrationReport{}
+	validateRulesetWorkflows(report, ruleset, migrateConfig, migrateRepositories)
+	if len(report.References) != 2 {
+		t.Fatalf("expected 2 references, got %d", len(report.References))
+	}
+	if ref := report.References[0]; ref.Status != RulesetMigrationStatusMapped || ref.Destination != "dst/ci/.github/workflows/ci.yml" {
+		t.Errorf("first workflow = %+v", ref)
+	}
+	if ref := report.References[1]; ref.Status != RulesetMigrationStatusUnresolved || ref.Source != "src/lint/.github/workflows/lint.yml" {
+		t.Errorf("second workflow = %+v", ref)
+	}
+	if !report.HasUnresolved() {
+		t.Error("expected HasUnresolved to be true")
+	}
+}
+
+func TestRulesetMigrationReport_SuggestedUserMappings(t *testing.T) {
+	report := &RulesetMigrationReport{
+		References: []*RulesetMigrationReference{
+			{Kind: RulesetMigrationKindBypassActor, Type: "User", Source: "alice", Status: RulesetMigrationStatusUnresolved, SuggestedLogin: "alice-corp"},
+			{Kind: RulesetMigrationKindBypassActor, Type: "User", Source: "bob", Status: RulesetMigrationStatusMapped, SuggestedLogin: "bob"},
+			{Kind: RulesetMigrationKindBypassActor, Type: "User", Source: "carol", Status: RulesetMigrationStatusUnresolved},
+		},
+	}
+	mappings := report.SuggestedUserMappings()
+	if len(mappings) != 1 || mappings[0].Src != "alice" || mappings[0].Dst != "alice-corp" {
+		t.Errorf("SuggestedUserMappings() = %+v", mappings)
+	}
+}
@@ -0,0 +1,492 @@
+package gh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RulesetMergeStrategyMerge deep-merges maps and appends list items that are not already present.
+	RulesetMergeStrategyMerge = "merge"
+	// RulesetMergeStrategyReplace deep-merges maps but replaces lists entirely.
+	RulesetMergeStrategyReplace = "replace"
+)
+
+// RulesetMergeStrategyList is the list of valid overlay merge strategies.
+var RulesetMergeStrategyList = []string{
+	RulesetMergeStrategyMerge,
+	RulesetMergeStrategyReplace,
+}
+
+// RulesetTemplate is a base ruleset document whose string values may contain
+// text/template expressions such as "{{ .DefaultBranch }}".
+// The ruleset document uses the same shape as RepositoryRulesetConfig. Team bypass actors may
+// name their team by slug in bypass_actor_meta; the actor_id is resolved per repository owner.
+type RulesetTemplate struct {
+	Variables map[string]any `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Ruleset   map[string]any `json:"ruleset" yaml:"ruleset"`
+}
+
+// RulesetOverlay is a partial ruleset document applied on top of a RulesetTemplate
+// for the repositories matching one of the Repositories patterns.
+// Patterns are matched against both the repository name and "owner/name".
+type RulesetOverlay struct {
+	Repositories  []string       `json:"repositories" yaml:"repositories"`
+	Variables     map[string]any `json:"variables,omitempty" yaml:"variables,omitempty"`
+	MergeStrategy string         `json:"merge_strategy,omitempty" yaml:"merge_strategy,omitempty"`
+	Ruleset       map[string]any `json:"ruleset" yaml:"ruleset"`
+}
+
+// RulesetTemplateRenderer expands a RulesetTemplate and its overlays for repositories.
+type RulesetTemplateRenderer struct {
+	Template *RulesetTemplate
+	Overlays []*RulesetOverlay
+}
+
+// RenderedRuleset is the result of expanding a ruleset template for a single repository.
+type RenderedRuleset struct {
+	Repository repository.Repository     `json:"-"`
+	FullName   string                    `json:"repository"`
+	Config     *RepositoryRulesetConfig  `json:"config,omitempty"`
+	Ruleset    *github.RepositoryRuleset `json:"ruleset,omitempty"`
+	Error      error                     `json:"-"`
+}
+
+// loadYAMLDocument reads a JSON or YAML file into a generic map.
+func loadYAMLDocument(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+// LoadRulesetTemplate loads a ruleset template from a JSON or YAML file.
+// A file with a top-level "ruleset" key is read as a RulesetTemplate;
+// otherwise the whole document is treated as the ruleset (e.g. an exported RepositoryRulesetConfig).
+func LoadRulesetTemplate(path string) (*RulesetTemplate, error) {
+	doc, err := loadYAMLDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	ruleset, ok := doc["ruleset"].(map[string]any)
+	if !ok {
+		return &RulesetTemplate{Ruleset: doc}, nil
+	}
+	tmpl := &RulesetTemplate{Ruleset: ruleset}
+	if vars, ok := doc["variables"].(map[string]any); ok {
+		tmpl.Variables = vars
+	}
+	return tmpl, nil
+}
+
+// LoadRulesetOverlay loads a per-repository overlay from a JSON or YAML file.
+func LoadRulesetOverlay(path string) (*RulesetOverlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overlay RulesetOverlay
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	if overlay.MergeStrategy == "" {
+		overlay.MergeStrategy = RulesetMergeStrategyMerge
+	}
+	if !isValidRulesetMergeStrategy(overlay.MergeStrategy) {
+		return nil, fmt.Errorf("invalid merge_strategy %q in %q: must be one of %s", overlay.MergeStrategy, path, strings.Join(RulesetMergeStrategyList, ", "))
+	}
+	return &overlay, nil
+}
+
+// LoadRulesetOverlays loads multiple overlay files in the given order.
+func LoadRulesetOverlays(paths []string) ([]*RulesetOverlay, error) {
+	overlays := make([]*RulesetOverlay, 0, len(paths))
+	for _, path := range paths {
+		overlay, err := LoadRulesetOverlay(path)
+		if err != nil {
+			return nil, err
+		}
+		overlays = append(overlays, overlay)
+	}
+	return overlays, nil
+}
+
+func isValidRulesetMergeStrategy(strategy string) bool {
+	for _, s := range RulesetMergeStrategyList {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the overlay applies to the given repository.
+func (o *RulesetOverlay) Matches(repo repository.Repository) bool {
+	fullName := repo.Owner + "/" + repo.Name
+	for _, pattern := range o.Repositories {
+		if pattern == "*" || pattern == repo.Name || pattern == fullName {
+			return true
+		}
+		if ok, err := filepath.Match(pattern, repo.Name); err == nil && ok {
+			return true
+		}
+		if ok, err := filepath.Match(pattern, fullName); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRulesetTemplateVariables returns the built-in template variables for a repository.
+// Built-in variables are Owner, Repo, FullName and DefaultBranch; user variables are
+// layered on top in the given order so later maps take precedence.
+func NewRulesetTemplateVariables(repo repository.Repository, defaultBranch string, vars ...map[string]any) map[string]any {
+	result := map[string]any{
+		"Owner":         repo.Owner,
+		"Repo":          repo.Name,
+		"FullName":      repo.Owner + "/" + repo.Name,
+		"DefaultBranch": defaultBranch,
+	}
+	for _, v := range vars {
+		for key, value := range v {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// Render expands the template and the matching overlays for a repository.
+// Variables are resolved from the built-ins, the template variables, and then each matching
+// overlay's variables, so overlays can override values used by the base template.
+func (r *RulesetTemplateRenderer) Render(repo repository.Repository, defaultBranch string) (*RepositoryRulesetConfig, error) {
+	if r.Template == nil {
+		return nil, fmt.Errorf("ruleset template is not set")
+	}
+	overlays := []*RulesetOverlay{}
+	layers := []map[string]any{r.Template.Variables}
+	for _, overlay := range r.Overlays {
+		if overlay.Matches(repo) {
+			overlays = append(overlays, overlay)
+			layers = append(layers, overlay.Variables)
+		}
+	}
+	vars := NewRulesetTemplateVariables(repo, defaultBranch, layers...)
+
+	expanded, err := expandRulesetTemplateValue(r.Template.Ruleset, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand ruleset template for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	doc, _ := expanded.(map[string]any)
+	for _, overlay := range overlays {
+		patch, err := expandRulesetTemplateValue(overlay.Ruleset, vars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand ruleset overlay for %s/%s: %w", repo.Owner, repo.Name, err)
+		}
+		patchMap, _ := patch.(map[string]any)
+		doc = MergeRulesetDocument(doc, patchMap, overlay.MergeStrategy)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var config RepositoryRulesetConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("rendered ruleset for %s/%s is invalid: %w", repo.Owner, repo.Name, err)
+	}
+	return &config, nil
+}
+
+// rulesetTemplateVariableRef matches a string consisting of a single variable reference,
+// e.g. "{{ .RequiredChecks }}". Such values are replaced with the raw variable value so that
+// lists and maps can be injected, not only strings.
+var rulesetTemplateVariableRef = regexp.MustCompile(`^\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}$`)
+
+// expandRulesetTemplateValue walks a decoded document and expands template expressions in string values.
+func expandRulesetTemplateValue(value any, vars map[string]any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			expanded, err := expandRulesetTemplateValue(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = expanded
+		}
+		return result, nil
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			expanded, err := expandRulesetTemplateValue(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			// A list variable referenced as a list item is spliced into the parent list.
+			if s, ok := item.(string); ok && rulesetTemplateVariableRef.MatchString(s) {
+				if list, ok := expanded.([]any); ok {
+					result = append(result, list...)
+					continue
+				}
+			}
+			result = append(result, expanded)
+		}
+		return result, nil
+	case string:
+		return expandRulesetTemplateString(v, vars)
+	default:
+		return value, nil
+	}
+}
+
+func expandRulesetTemplateString(s string, vars map[string]any) (any, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	if m := rulesetTemplateVariableRef.FindStringSubmatch(s); m != nil {
+		value, ok := vars[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("undefined template variable %q", m[1])
+		}
+		if _, isString := value.(string); !isString {
+			return normalizeRulesetTemplateValue(value), nil
+		}
+	}
+	tmpl, err := template.New("ruleset").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+// normalizeRulesetTemplateValue converts typed slices (e.g. []string) into []any so that
+// they can be spliced and merged like decoded documents.
+func normalizeRulesetTemplateValue(value any) any {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return value
+	}
+	if list, ok := value.([]any); ok {
+		return list
+	}
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list
+}
+
+// MergeRulesetDocument merges an overlay document into a base ruleset document.
+// Maps are merged recursively and a null overlay value removes the key from the base.
+// With RulesetMergeStrategyMerge, list items from the overlay are appended unless an item
+// with the same identity (see rulesetListItemKey) already exists, in which case it is merged.
+// With RulesetMergeStrategyReplace, lists from the overlay replace the base list.
+func MergeRulesetDocument(base map[string]any, overlay map[string]any, strategy string) map[string]any {
+	result := make(map[string]any, len(base))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range overlay {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		current, exists := result[key]
+		if !exists {
+			result[key] = value
+			continue
+		}
+		result[key] = mergeRulesetValue(current, value, strategy)
+	}
+	return result
+}
+
+func mergeRulesetValue(base any, overlay any, strategy string) any {
+	switch o := overlay.(type) {
+	case map[string]any:
+		if b, ok := base.(map[string]any); ok {
+			return MergeRulesetDocument(b, o, strategy)
+		}
+	case []any:
+		if b, ok := base.([]any); ok && strategy != RulesetMergeStrategyReplace {
+			return mergeRulesetList(b, o, strategy)
+		}
+	}
+	return overlay
+}
+
+func mergeRulesetList(base []any, overlay []any, strategy string) []any {
+	result := make([]any, len(base))
+	copy(result, base)
+	for _, item := range overlay {
+		key := rulesetListItemKey(item)
+		merged := false
+		for i, existing := range result {
+			if key != "" && rulesetListItemKey(existing) == key {
+				result[i] = mergeRulesetValue(existing, item, strategy)
+				merged = true
+				break
+			}
+			if key == "" && reflect.DeepEqual(existing, item) {
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// rulesetListItemKeyFields lists the fields that identify an item in ruleset lists,
+// e.g. rules by type, required status checks by context and bypass actors by type and ID.
+var rulesetListItemKeyFields = [][]string{
+	{"type"},
+	{"context"},
+	{"actor_type", "actor_id"},
+	{"repository_id", "path"},
+	{"tool"},
+	{"reviewer_id"},
+}
+
+// rulesetListItemKey returns an identity key for a list item, or "" if it has none.
+func rulesetListItemKey(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, fields := range rulesetListItemKeyFields {
+		parts := make([]string, 0, len(fields))
+		for _, field := range fields {
+			value, exists := m[field]
+			if !exists {
+				break
+			}
+			parts = append(parts, fmt.Sprintf("%s=%v", field, value))
+		}
+		if len(parts) == len(fields) {
+			return strings.Join(parts, ",")
+		}
+	}
+	return ""
+}
+
+// RenderRulesetTemplates expands the ruleset template for each repository.
+// The default branch of each repository is fetched to populate the DefaultBranch variable.
+// Per-repository failures are recorded in RenderedRuleset.Error rather than aborting the whole run.
+func RenderRulesetTemplates(ctx context.Context, g *GitHubClient, repos []repository.Repository, renderer *RulesetTemplateRenderer) []*RenderedRuleset {
+	results := make([]*RenderedRuleset, 0, len(repos))
+	for _, repo := range repos {
+		result := &RenderedRuleset{
+			Repository: repo,
+			FullName:   repo.Owner + "/" + repo.Name,
+		}
+		results = append(results, result)
+
+		r, err := GetRepository(ctx, g, repo)
+		if err != nil {
+			result.Error = err
+			continue
+		}
+		config, err := renderer.Render(repo, r.GetDefaultBranch())
+		if err != nil {
+			result.Error = err
+			continue
+		}
+		err = resolveRulesetTemplateTeams(config, func(slug string) (*github.Team, error) {
+			return GetTeamBySlug(ctx, g, repo, slug)
+		})
+		if err != nil {
+			result.Error = fmt.Errorf("failed to resolve bypass actors for %s: %w", result.FullName, err)
+			continue
+		}
+		result.Config = config
+	}
+	return results
+}
+
+// resolveRulesetTemplateTeams sets the actor_id of Team bypass actors whose bypass_actor_meta entry has a slug
+// to the ID of the team returned by getTeamBySlug, like TransformMigrateRuleset does for migrated teams.
+// The meta entries are re-keyed by the resolved IDs. An error is returned when a slug cannot be resolved.
+func resolveRulesetTemplateTeams(config *RepositoryRulesetConfig, getTeamBySlug func(slug string) (*github.Team, error)) error {
+	if config == nil || len(config.BypassActors) == 0 || len(config.BypassActorsMeta) == 0 {
+		return nil
+	}
+	resolvedMeta := make(map[string]*BypassActorMeta, len(config.BypassActorsMeta))
+	for key, meta := range config.BypassActorsMeta {
+		resolvedMeta[key] = meta
+	}
+	for _, actor := range config.BypassActors {
+		if actor.ActorType == nil || *actor.ActorType != github.BypassActorTypeTeam || actor.ActorID == nil {
+			continue
+		}
+		key := strconv.FormatInt(*actor.ActorID, 10)
+		meta, ok := config.BypassActorsMeta[key]
+		if !ok || meta.Slug == "" {
+			continue
+		}
+		team, err := getTeamBySlug(meta.Slug)
+		if err != nil {
+			return fmt.Errorf("failed to resolve team %q: %w", meta.Slug, err)
+		}
+		delete(resolvedMeta, key)
+		*actor.ActorID = team.GetID()
+		resolvedMeta[strconv.FormatInt(team.GetID(), 10)] = meta
+	}
+	config.BypassActorsMeta = resolvedMeta
+	return nil
+}
+
+// ApplyRenderedRulesets creates or updates the rendered rulesets in their repositories.
+// Results that already carry an error are skipped. When dryRun is true, the rulesets are
+// converted but not written.
+func ApplyRenderedRulesets(ctx context.Context, g *GitHubClient, rendered []*RenderedRuleset, dryRun bool) {
+	for _, result := range rendered {
+		if result.Error != nil || result.Config == nil {
+			continue
+		}
+		ruleset := result.Config.ToRepositoryRuleset(nil)
+		ruleset.ID = result.Config.ID
+		if ruleset.ID == nil {
+			ruleset.ID = github.Ptr(int64(0))
+		}
+		if dryRun {
+			result.Ruleset = ruleset
+			continue
+		}
+		applied, err := CreateOrUpdateRuleset(ctx, g, result.Repository, ruleset)
+		if err != nil {
+			logger.Warn("Failed to apply rendered ruleset", "repository", result.FullName, "ruleset", ruleset.Name, "error", err)
+			result.Error = err
+			continue
+		}
+		result.Ruleset = applied
+	}
+}
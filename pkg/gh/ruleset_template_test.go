@@ -0,0 +1,238 @@
+package gh
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+)
+
+func TestRulesetTemplateRenderer_Render(t *testing.T) {
+	renderer := &RulesetTemplateRenderer{
+		Template: &RulesetTemplate{
+			Variables: map[string]any{
+				"RequiredChecks": []string{"build"},
+			},
+			Ruleset: map[string]any{
+				"name":        "protect-{{ .DefaultBranch }}",
+				"source":      "{{ .FullName }}",
+				"enforcement": "active",
+				"conditions": map[string]any{
+					"ref_name": map[string]any{
+						"include": []any{"refs/heads/{{ .DefaultBranch }}"},
+						"exclude": []any{},
+					},
+				},
+				"rules": []any{
+					map[string]any{"type": "deletion"},
+					map[string]any{
+						"type": "required_status_checks",
+						"parameters": map[string]any{
+							"strict_required_status_checks_policy": true,
+							"required_status_checks": []any{
+								map[string]any{"context": "build"},
+							},
+						},
+					},
+				},
+			},
+		},
+		Overlays: []*RulesetOverlay{
+			{
+				Repositories:  []string{"owner/app-*"},
+				Variables:     map[string]any{"ExtraCheck": "e2e"},
+				MergeStrategy: RulesetMergeStrategyMerge,
+				Ruleset: map[string]any{
+					"rules": []any{
+						map[string]any{
+							"type": "required_status_checks",
+							"parameters": map[string]any{
+								"required_status_checks": []any{
+									map[string]any{"context": "build", "integration_id": 1},
+									map[string]any{"context": "{{ .ExtraCheck }}"},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Repositories: []string{"other"},
+				Ruleset: map[string]any{
+					"enforcement": "disabled",
+				},
+			},
+		},
+	}
+
+	config, err := renderer.Render(repository.Repository{Owner: "owner", Name: "app-web"}, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Name != "protect-main" {
+		t.Errorf("Name = %q, want %q", config.Name, "protect-main")
+	}
+	if config.Source != "owner/app-web" {
+		t.Errorf("Source = %q, want %q", config.Source, "owner/app-web")
+	}
+	if config.Enforcement != "active" {
+		t.Errorf("Enforcement = %q, want %q", config.Enforcement, "active")
+	}
+	if got := config.Conditions.RefName.Include; len(got) != 1 || got[0] != "refs/heads/main" {
+		t.Errorf("RefName.Include = %v, want [refs/heads/main]", got)
+	}
+	if config.Rules.Deletion == nil {
+		t.Error("expected deletion rule to be kept from the base template")
+	}
+	checks := config.Rules.RequiredStatusChecks.RequiredStatusChecks
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 required status checks, got %d", len(checks))
+	}
+	if checks[0].Context != "build" || checks[0].IntegrationID == nil || *checks[0].IntegrationID != 1 {
+		t.Errorf("first check = %+v, want build with integration_id 1", checks[0])
+	}
+	if checks[1].Context != "e2e" {
+		t.Errorf("second check context = %q, want %q", checks[1].Context, "e2e")
+	}
+}
+
+func TestRulesetTemplateRenderer_RenderUndefinedVariable(t *testing.T) {
+	renderer := &RulesetTemplateRenderer{
+		Template: &RulesetTemplate{
+			Ruleset: map[string]any{"name": "{{ .Missing }}"},
+		},
+	}
+	if _, err := renderer.Render(repository.Repository{Owner: "o", Name: "r"}, "main"); err == nil {
+		t.Error("expected error for undefined variable")
+	}
+}
+
+func TestResolveRulesetTemplateTeams(t *testing.T) {
+	renderer := &RulesetTemplateRenderer{
+		Template: &RulesetTemplate{
+			Variables: map[string]any{"ReleaseTeam": "release"},
+			Ruleset: map[string]any{
+				"name":        "protect",
+				"enforcement": "active",
+				"bypass_actors": []any{
+					map[string]any{"actor_id": 1, "actor_type": "Team", "bypass_mode": "always"},
+					map[string]any{"actor_id": 5, "actor_type": "RepositoryRole", "bypass_mode": "always"},
+				},
+				"bypass_actor_meta": map[string]any{
+					"1": map[string]any{"slug": "{{ .ReleaseTeam }}"},
+				},
+			},
+		},
+	}
+	teams := map[string]*github.Team{
+		"release": {ID: github.Ptr(int64(42)), Slug: github.Ptr("release")},
+	}
+	getTeamBySlug := func(slug string) (*github.Team, error) {
+		if team, ok := teams[slug]; ok {
+			return team, nil
+		}
+		return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	}
+
+	config, err := renderer.Render(repository.Repository{Owner: "o", Name: "r"}, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := resolveRulesetTemplateTeams(config, getTeamBySlug); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := config.BypassActors[0].GetActorID(); got != 42 {
+		t.Errorf("team actor_id = %d, want 42", got)
+	}
+	if got := config.BypassActors[1].GetActorID(); got != 5 {
+		t.Errorf("repository role actor_id = %d, want 5", got)
+	}
+	if meta, ok := config.BypassActorsMeta["42"]; !ok || meta.Slug != "release" {
+		t.Errorf("bypass_actor_meta = %v, want release keyed by 42", config.BypassActorsMeta)
+	}
+
+	teams = nil
+	config, err = renderer.Render(repository.Repository{Owner: "o", Name: "r"}, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := resolveRulesetTemplateTeams(config, getTeamBySlug); err == nil {
+		t.Error("expected error for an unknown team slug")
+	}
+}
+
+func TestExpandRulesetTemplateValue_ListVariable(t *testing.T) {
+	vars := map[string]any{"Contexts": []string{"a", "b"}}
+	got, err := expandRulesetTemplateValue([]any{"x", "{{ .Contexts }}"}, vars)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list, ok := got.([]any)
+	if !ok || len(list) != 3 || list[0] != "x" || list[1] != "a" || list[2] != "b" {
+		t.Errorf("got %v, want [x a b]", got)
+	}
+}
+
+func TestMergeRulesetDocument(t *testing.T) {
+	base := map[string]any{
+		"name":  "base",
+		"keep":  "value",
+		"drop":  "value",
+		"items": []any{"a", "b"},
+	}
+	tests := []struct {
+		name     string
+		strategy string
+		want     []any
+	}{
+		{name: "merge appends unique items", strategy: RulesetMergeStrategyMerge, want: []any{"a", "b", "c"}},
+		{name: "replace overrides list", strategy: RulesetMergeStrategyReplace, want: []any{"b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlay := map[string]any{
+				"name":  "overlay",
+				"drop":  nil,
+				"items": []any{"b", "c"},
+			}
+			got := MergeRulesetDocument(base, overlay, tt.strategy)
+			if got["name"] != "overlay" {
+				t.Errorf("name = %v, want overlay", got["name"])
+			}
+			if got["keep"] != "value" {
+				t.Errorf("keep = %v, want value", got["keep"])
+			}
+			if _, ok := got["drop"]; ok {
+				t.Error("drop should be removed by null overlay value")
+			}
+			items, _ := got["items"].([]any)
+			if len(items) != len(tt.want) {
+				t.Fatalf("items = %v, want %v", items, tt.want)
+			}
+			for i := range items {
+				if items[i] != tt.want[i] {
+					t.Errorf("items = %v, want %v", items, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRulesetOverlay_Matches(t *testing.T) {
+	overlay := &RulesetOverlay{Repositories: []string{"svc-*", "org/exact"}}
+	tests := []struct {
+		repo repository.Repository
+		want bool
+	}{
+		{repository.Repository{Owner: "org", Name: "svc-a"}, true},
+		{repository.Repository{Owner: "org", Name: "exact"}, true},
+		{repository.Repository{Owner: "other", Name: "exact"}, false},
+		{repository.Repository{Owner: "org", Name: "web"}, false},
+	}
+	for _, tt := range tests {
+		if got := overlay.Matches(tt.repo); got != tt.want {
+			t.Errorf("Matches(%s/%s) = %v, want %v", tt.repo.Owner, tt.repo.Name, got, tt.want)
+		}
+	}
+}
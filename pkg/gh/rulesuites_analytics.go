@@ -0,0 +1,274 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+const (
+	RuleSuiteGroupByRuleset    = "ruleset"
+	RuleSuiteGroupByRuleType   = "rule_type"
+	RuleSuiteGroupByActor      = "actor"
+	RuleSuiteGroupByRef        = "ref"
+	RuleSuiteGroupByRepository = "repository"
+)
+
+// RuleSuiteGroupByList is the list of valid dimensions for aggregating rule suites.
+var RuleSuiteGroupByList = []string{
+	RuleSuiteGroupByRuleset,
+	RuleSuiteGroupByRuleType,
+	RuleSuiteGroupByActor,
+	RuleSuiteGroupByRef,
+	RuleSuiteGroupByRepository,
+}
+
+const (
+	ruleSuiteResultPass        = "pass"
+	ruleSuiteResultFail        = "fail"
+	ruleSuiteResultBypass      = "bypass"
+	ruleEnforcementModeEval    = "evaluate"
+	ruleSuiteStatsUnknownValue = "(unknown)"
+)
+
+// RuleSuiteStats holds aggregated rule suite evaluation counts for one group.
+// Dimensions that are not part of the grouping are left empty.
+type RuleSuiteStats struct {
+	Ruleset    string `json:"ruleset,omitempty"`
+	RuleType   string `json:"rule_type,omitempty"`
+	Actor      string `json:"actor,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	// Suites is the number of distinct rule suites counted in the group.
+	Suites int `json:"suites"`
+	// Total is the number of evaluations (or rule suites when no rule evaluations are available).
+	Total int `json:"total"`
+	// Pass and Fail count enforced rules; evaluate-mode rules are counted by Evaluate and EvaluateFail.
+	Pass int `json:"pass"`
+	Fail int `json:"fail"`
+	// Bypass counts failing active rules that were bypassed by the actor.
+	Bypass int `json:"bypass"`
+	// EvaluateFail counts evaluate-mode rules that would have failed if the ruleset were active.
+	EvaluateFail int `json:"evaluate_fail"`
+	// Evaluate counts all evaluate-mode evaluations.
+	Evaluate int        `json:"evaluate"`
+	FirstAt  *time.Time `json:"first_at,omitempty"`
+	LastAt   *time.Time `json:"last_at,omitempty"`
+	// ReadyToActivate is true when evaluate-mode evaluations exist and none of them failed.
+	ReadyToActivate bool `json:"ready_to_activate"`
+
+	suiteIDs map[int64]struct{}
+}
+
+// RuleSuiteAnalyticsOptions configures the rule suite aggregation.
+type RuleSuiteAnalyticsOptions struct {
+	// GroupBy lists the dimensions to group by (see RuleSuiteGroupByList). Defaults to ruleset and rule_type.
+	GroupBy []string
+	// Since and Until filter rule suites by pushed_at. Zero values are ignored.
+	Since time.Time
+	Until time.Time
+}
+
+// ValidateRuleSuiteGroupBy checks that every group-by dimension is supported.
+func ValidateRuleSuiteGroupBy(groupBy []string) error {
+	for _, g := range groupBy {
+		valid := false
+		for _, v := range RuleSuiteGroupByList {
+			if g == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid group by %q: must be one of %s", g, strings.Join(RuleSuiteGroupByList, ", "))
+		}
+	}
+	return nil
+}
+
+// ListRuleSuitesWithEvaluations lists rule suites and fetches each one individually so that
+// RuleEvaluations are populated. The list API does not return rule evaluations.
+// Rule suites whose details cannot be fetched are kept without evaluations.
+func ListRuleSuitesWithEvaluations(ctx context.Context, g *GitHubClient, repo repository.Repository, options *ListRuleSuitesOptions) ([]*RuleSuite, error) {
+	suites, err := ListRuleSuites(ctx, g, repo, options)
+	if err != nil {
+		return nil, err
+	}
+	detailed := make([]*RuleSuite, 0, len(suites))
+	for _, suite := range suites {
+		if suite.ID == nil {
+			detailed = append(detailed, suite)
+			continue
+		}
+		d, err := GetRuleSuite(ctx, g, repo, *suite.ID)
+		if err != nil {
+			logger.Warn("Failed to get rule suite details", "id", *suite.ID, "error", err)
+			detailed = append(detailed, suite)
+			continue
+		}
+		detailed = append(detailed, d)
+	}
+	return detailed, nil
+}
+
+// AggregateRuleSuites aggregates rule suites into per-group statistics.
+// When rule evaluations are available each evaluation is counted individually, which allows grouping
+// by ruleset and rule type; otherwise the suite-level result is counted.
+// The returned slice is sorted by the group dimensions.
+func AggregateRuleSuites(suites []*RuleSuite, options *RuleSuiteAnalyticsOptions) []*RuleSuiteStats {
+	if options == nil {
+		options = &RuleSuiteAnalyticsOptions{}
+	}
+	groupBy := options.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = []string{RuleSuiteGroupByRuleset, RuleSuiteGroupByRuleType}
+	}
+
+	groups := map[string]*RuleSuiteStats{}
+	for _, suite := range suites {
+		if suite == nil {
+			continue
+		}
+		var pushedAt *time.Time
+		if suite.PushedAt != nil {
+			t := suite.PushedAt.Time
+			if !options.Since.IsZero() && t.Before(options.Since) {
+				continue
+			}
+			if !options.Until.IsZero() && t.After(options.Until) {
+				continue
+			}
+			pushedAt = &t
+		}
+
+		suiteResult := derefString(suite.Result)
+		if len(suite.RuleEvaluations) == 0 {
+			stats := ruleSuiteStatsGroup(groups, groupBy, suite, "", "")
+			stats.add(suite, pushedAt)
+			switch suiteResult {
+			case ruleSuiteResultPass:
+				stats.Pass++
+			case ruleSuiteResultFail:
+				stats.Fail++
+			case ruleSuiteResultBypass:
+				stats.Bypass++
+			}
+			// EvaluationResult is only set when the suite had evaluate-mode rules
+			if evaluationResult := derefString(suite.EvaluationResult); evaluationResult != "" {
+				stats.Evaluate++
+				if evaluationResult == ruleSuiteResultFail {
+					stats.EvaluateFail++
+				}
+			}
+			continue
+		}
+
+		for _, evaluation := range suite.RuleEvaluations {
+			if evaluation == nil {
+				continue
+			}
+			rulesetName := ""
+			if evaluation.RuleSource != nil {
+				rulesetName = derefString(evaluation.RuleSource.Name)
+			}
+			stats := ruleSuiteStatsGroup(groups, groupBy, suite, rulesetName, derefString(evaluation.RuleType))
+			stats.add(suite, pushedAt)
+
+			result := derefString(evaluation.Result)
+			mode := derefString(evaluation.EnforcementMode)
+			if mode == ruleEnforcementModeEval {
+				stats.Evaluate++
+				if result == ruleSuiteResultFail {
+					stats.EvaluateFail++
+				}
+				continue
+			}
+			switch result {
+			case ruleSuiteResultPass:
+				stats.Pass++
+			case ruleSuiteResultFail:
+				if suiteResult == ruleSuiteResultBypass {
+					stats.Bypass++
+				} else {
+					stats.Fail++
+				}
+			}
+		}
+	}
+
+	result := make([]*RuleSuiteStats, 0, len(groups))
+	for _, stats := range groups {
+		stats.Suites = len(stats.suiteIDs)
+		// Switching from evaluate to active would not have blocked any push in the window.
+		stats.ReadyToActivate = stats.Evaluate > 0 && stats.EvaluateFail == 0
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return ruleSuiteStatsSortKey(result[i]) < ruleSuiteStatsSortKey(result[j])
+	})
+	return result
+}
+
+func ruleSuiteStatsGroup(groups map[string]*RuleSuiteStats, groupBy []string, suite *RuleSuite, rulesetName string, ruleType string) *RuleSuiteStats {
+	stats := &RuleSuiteStats{}
+	for _, g := range groupBy {
+		switch g {
+		case RuleSuiteGroupByRuleset:
+			stats.Ruleset = valueOrUnknown(rulesetName)
+		case RuleSuiteGroupByRuleType:
+			stats.RuleType = valueOrUnknown(ruleType)
+		case RuleSuiteGroupByActor:
+			stats.Actor = valueOrUnknown(derefString(suite.ActorName))
+		case RuleSuiteGroupByRef:
+			stats.Ref = valueOrUnknown(derefString(suite.Ref))
+		case RuleSuiteGroupByRepository:
+			stats.Repository = valueOrUnknown(derefString(suite.RepositoryName))
+		}
+	}
+	key := ruleSuiteStatsSortKey(stats)
+	if existing, ok := groups[key]; ok {
+		return existing
+	}
+	stats.suiteIDs = map[int64]struct{}{}
+	groups[key] = stats
+	return stats
+}
+
+func (s *RuleSuiteStats) add(suite *RuleSuite, pushedAt *time.Time) {
+	s.Total++
+	if suite.ID != nil {
+		s.suiteIDs[*suite.ID] = struct{}{}
+	}
+	if pushedAt == nil {
+		return
+	}
+	if s.FirstAt == nil || pushedAt.Before(*s.FirstAt) {
+		s.FirstAt = pushedAt
+	}
+	if s.LastAt == nil || pushedAt.After(*s.LastAt) {
+		s.LastAt = pushedAt
+	}
+}
+
+func ruleSuiteStatsSortKey(s *RuleSuiteStats) string {
+	return strings.Join([]string{s.Ruleset, s.RuleType, s.Repository, s.Ref, s.Actor}, "\x00")
+}
+
+func valueOrUnknown(v string) string {
+	if v == "" {
+		return ruleSuiteStatsUnknownValue
+	}
+	return v
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,109 @@
+package gh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/client"
+)
+
+func newTestRuleSuite(id int64, actor string, result string, pushedAt time.Time, evaluations ...*client.RuleSuiteRuleEvaluation) *RuleSuite {
+	return &RuleSuite{
+		ID:              github.Ptr(id),
+		ActorName:       github.Ptr(actor),
+		Ref:             github.Ptr("refs/heads/main"),
+		Result:          github.Ptr(result),
+		PushedAt:        &github.Timestamp{Time: pushedAt},
+		RuleEvaluations: evaluations,
+	}
+}
+
+func newTestRuleEvaluation(ruleset string, ruleType string, mode string, result string) *client.RuleSuiteRuleEvaluation {
+	return &client.RuleSuiteRuleEvaluation{
+		RuleSource:      &client.RuleSuiteRuleSource{Name: github.Ptr(ruleset)},
+		RuleType:        github.Ptr(ruleType),
+		EnforcementMode: github.Ptr(mode),
+		Result:          github.Ptr(result),
+	}
+}
+
+func TestAggregateRuleSuites(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	suites := []*RuleSuite{
+		newTestRuleSuite(1, "alice", "pass", now,
+			newTestRuleEvaluation("protect", "pull_request", "active", "pass"),
+			newTestRuleEvaluation("trial", "required_signatures", "evaluate", "fail"),
+		),
+		newTestRuleSuite(2, "bob", "bypass", now.Add(time.Hour),
+			newTestRuleEvaluation("protect", "pull_request", "active", "fail"),
+			newTestRuleEvaluation("trial", "required_signatures", "evaluate", "pass"),
+		),
+		newTestRuleSuite(3, "carol", "fail", now.Add(2*time.Hour),
+			newTestRuleEvaluation("protect", "pull_request", "active", "fail"),
+		),
+		newTestRuleSuite(4, "dave", "pass", now.Add(-48*time.Hour),
+			newTestRuleEvaluation("protect", "pull_request", "active", "fail"),
+		),
+	}
+
+	stats := AggregateRuleSuites(suites, &RuleSuiteAnalyticsOptions{
+		GroupBy: []string{RuleSuiteGroupByRuleset},
+		Since:   now.Add(-time.Hour),
+	})
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(stats))
+	}
+
+	protect := stats[0]
+	if protect.Ruleset != "protect" {
+		t.Fatalf("first group = %q, want protect", protect.Ruleset)
+	}
+	if protect.Suites != 3 || protect.Pass != 1 || protect.Fail != 1 || protect.Bypass != 1 {
+		t.Errorf("protect stats = %+v, want suites=3 pass=1 fail=1 bypass=1", protect)
+	}
+	if protect.ReadyToActivate {
+		t.Error("active-only ruleset should not be reported as ready to activate")
+	}
+	if protect.FirstAt == nil || !protect.FirstAt.Equal(now) || !protect.LastAt.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("protect window = %v..%v", protect.FirstAt, protect.LastAt)
+	}
+
+	trial := stats[1]
+	if trial.Evaluate != 2 || trial.EvaluateFail != 1 || trial.Pass != 0 || trial.ReadyToActivate {
+		t.Errorf("trial stats = %+v, want evaluate=2 evaluate_fail=1 pass=0 not ready", trial)
+	}
+}
+
+func TestAggregateRuleSuites_WithoutEvaluations(t *testing.T) {
+	now := time.Now()
+	suites := []*RuleSuite{
+		newTestRuleSuite(1, "alice", "pass", now),
+		newTestRuleSuite(2, "alice", "bypass", now),
+		newTestRuleSuite(3, "bob", "fail", now),
+	}
+	suites[0].EvaluationResult = github.Ptr("fail")
+	suites[2].EvaluationResult = github.Ptr("pass")
+
+	stats := AggregateRuleSuites(suites, &RuleSuiteAnalyticsOptions{GroupBy: []string{RuleSuiteGroupByActor}})
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(stats))
+	}
+	alice := stats[0]
+	if alice.Actor != "alice" || alice.Pass != 1 || alice.Bypass != 1 || alice.Evaluate != 1 || alice.EvaluateFail != 1 || alice.ReadyToActivate {
+		t.Errorf("alice stats = %+v", alice)
+	}
+	// A passing evaluate-mode suite counts towards activation readiness
+	if stats[1].Actor != "bob" || stats[1].Fail != 1 || stats[1].Evaluate != 1 || stats[1].EvaluateFail != 0 || !stats[1].ReadyToActivate {
+		t.Errorf("bob stats = %+v", stats[1])
+	}
+}
+
+func TestValidateRuleSuiteGroupBy(t *testing.T) {
+	if err := ValidateRuleSuiteGroupBy([]string{"ruleset", "actor"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateRuleSuiteGroupBy([]string{"unknown"}); err == nil {
+		t.Error("expected error for unknown group by")
+	}
+}
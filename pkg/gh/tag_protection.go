@@ -3,9 +3,20 @@ package gh
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
 	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gitglob"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+// Well-known repository role IDs used as ruleset bypass actors.
+const (
+	repositoryRoleMaintainID int64 = 2
+	repositoryRoleAdminID    int64 = 5
 )
 
 // ListTagProtections retrieves all tag protection settings for the repository.
@@ -62,3 +73,305 @@ func ConvertTagProtectionToRuleset(pattern string) *github.RepositoryRuleset {
 
 	return ruleset
 }
+
+// TagProtectionConversion holds a ruleset converted from a repository's tag protections and
+// the result of verifying it against the existing tags.
+type TagProtectionConversion struct {
+	Ruleset        *github.RepositoryRuleset `json:"ruleset"`
+	TagProtections []*github.TagProtection   `json:"tag_protections"`
+	// RulesetPatterns are the ruleset ref patterns converted from TagProtections, index by index.
+	// The pattern is empty for a tag protection without a pattern, which is not converted.
+	RulesetPatterns []string `json:"ruleset_patterns"`
+	// ProtectedTags are the existing tags matched by the tag protection patterns.
+	ProtectedTags []string `json:"protected_tags"`
+	// RulesetTags are the existing tags matched by the converted ruleset.
+	RulesetTags []string `json:"ruleset_tags"`
+	// Missing are tags protected by tag protections but not targeted by the ruleset.
+	Missing []string `json:"missing,omitempty"`
+	// Extra are tags targeted by the ruleset but not protected by tag protections.
+	Extra []string `json:"extra,omitempty"`
+}
+
+// Equivalent reports whether the ruleset targets exactly the tags that the tag protections protect.
+func (c *TagProtectionConversion) Equivalent() bool {
+	return len(c.Missing) == 0 && len(c.Extra) == 0
+}
+
+// convertTagProtectionPattern converts a tag protection pattern to a ruleset ref pattern.
+// Tag protection patterns use fnmatch without the pathname flag, so "*" also matches "/".
+// Rulesets only match "/" with "**", so every single "*" is widened to "**".
+func convertTagProtectionPattern(pattern string) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '*' {
+			sb.WriteByte(pattern[i])
+			continue
+		}
+		j := i
+		for j < len(pattern) && pattern[j] == '*' {
+			j++
+		}
+		sb.WriteString("**")
+		i = j - 1
+	}
+	return "refs/tags/" + sb.String()
+}
+
+// MatchTagProtectionPattern reports whether a tag name matches a tag protection pattern.
+// Tag protections use fnmatch without the pathname flag: "*" and "?" also match "/",
+// "[...]" and "[!...]" match character classes and a backslash escapes the next character.
+// Matching is done byte by byte.
+func MatchTagProtectionPattern(pattern string, tagName string) bool {
+	px, nx := 0, 0
+	nextPx, nextNx := 0, 0
+	for px < len(pattern) || nx < len(tagName) {
+		if px < len(pattern) {
+			switch c := pattern[px]; c {
+			case '*':
+				// Try to match at nx; if that fails, restart here matching one more byte
+				nextPx = px
+				nextNx = nx + 1
+				px++
+				continue
+			case '?':
+				if nx < len(tagName) {
+					px++
+					nx++
+					continue
+				}
+			case '[':
+				if nx < len(tagName) {
+					matched, width, ok := matchFnmatchBracket(pattern[px:], tagName[nx])
+					if !ok {
+						// An unterminated bracket is a literal "["
+						matched, width = tagName[nx] == '[', 1
+					}
+					if matched {
+						px += width
+						nx++
+						continue
+					}
+				}
+			case '\\':
+				if px+1 < len(pattern) {
+					c = pattern[px+1]
+					px++
+				}
+				fallthrough
+			default:
+				if nx < len(tagName) && tagName[nx] == c {
+					px++
+					nx++
+					continue
+				}
+			}
+		}
+		if 0 < nextNx && nextNx <= len(tagName) {
+			px = nextPx
+			nx = nextNx
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// matchFnmatchBracket matches c against the bracket expression at the start of pattern.
+// It returns the width of the expression, or ok false if the expression is not terminated.
+func matchFnmatchBracket(pattern string, c byte) (matched bool, width int, ok bool) {
+	i := 1
+	negate := i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^')
+	if negate {
+		i++
+	}
+	for first := true; i < len(pattern); first = false {
+		if pattern[i] == ']' && !first {
+			return matched != negate, i + 1, true
+		}
+		lo := pattern[i]
+		i++
+		hi := lo
+		if i+1 < len(pattern) && pattern[i] == '-' && pattern[i+1] != ']' {
+			hi = pattern[i+1]
+			i += 2
+		}
+		if lo <= c && c <= hi {
+			matched = true
+		}
+	}
+	return false, 0, false
+}
+
+// matchTagRulesetRefName reports whether a tag name matches the ref name conditions of a tag ruleset.
+// Ruleset patterns are matched with fnmatch where only "**" crosses "/".
+func matchTagRulesetRefName(tagName string, refName *github.RepositoryRulesetRefConditionParameters) bool {
+	if refName == nil {
+		return false
+	}
+	for _, exclude := range refName.Exclude {
+		if matchTagRulesetPattern(exclude, tagName) {
+			return false
+		}
+	}
+	for _, include := range refName.Include {
+		if matchTagRulesetPattern(include, tagName) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTagRulesetPattern(pattern string, tagName string) bool {
+	if pattern == "~ALL" {
+		return true
+	}
+	pattern = strings.TrimPrefix(pattern, "refs/tags/")
+	// "**" matches across "/" separators, which filepath.Match does not support.
+	// Anchor the pattern so that gitglob matches it against the full tag name.
+	if strings.Contains(pattern, "**") {
+		return gitglob.MatchPattern("/"+pattern, tagName)
+	}
+	matched, err := filepath.Match(pattern, tagName)
+	return err == nil && matched
+}
+
+// ConvertTagProtectionsToRuleset converts all tag protection patterns of a repository into a single
+// tag ruleset. Tag protections block creation, update and deletion of matching tags for users without
+// the maintain or admin role, so those roles are added as bypass actors.
+func ConvertTagProtectionsToRuleset(name string, tagProtections []*github.TagProtection) *github.RepositoryRuleset {
+	target := github.RulesetTargetTag
+	include := []string{}
+	for _, pattern := range convertTagProtectionPatterns(tagProtections) {
+		if pattern != "" {
+			include = append(include, pattern)
+		}
+	}
+
+	bypassMode := github.BypassModeAlways
+	roleType := github.BypassActorTypeRepositoryRole
+	return &github.RepositoryRuleset{
+		ID:          github.Ptr(int64(0)),
+		Name:        name,
+		Target:      &target,
+		Enforcement: github.RulesetEnforcementActive,
+		BypassActors: []*github.BypassActor{
+			{ActorID: github.Ptr(repositoryRoleMaintainID), ActorType: &roleType, BypassMode: &bypassMode},
+			{ActorID: github.Ptr(repositoryRoleAdminID), ActorType: &roleType, BypassMode: &bypassMode},
+		},
+		Conditions: &github.RepositoryRulesetConditions{
+			RefName: &github.RepositoryRulesetRefConditionParameters{
+				Include: include,
+				Exclude: []string{},
+			},
+		},
+		Rules: &github.RepositoryRulesetRules{
+			Creation: &github.EmptyRuleParameters{},
+			Update:   &github.UpdateRuleParameters{},
+			Deletion: &github.EmptyRuleParameters{},
+		},
+	}
+}
+
+// convertTagProtectionPatterns converts the pattern of each tag protection, keeping the index of the
+// source tag protection. Tag protections without a pattern yield an empty pattern.
+func convertTagProtectionPatterns(tagProtections []*github.TagProtection) []string {
+	patterns := make([]string, len(tagProtections))
+	for i, tp := range tagProtections {
+		if tp == nil || tp.GetPattern() == "" {
+			continue
+		}
+		patterns[i] = convertTagProtectionPattern(tp.GetPattern())
+	}
+	return patterns
+}
+
+// PlanTagProtectionMigration converts the repository's tag protections into a tag ruleset and verifies
+// that the ruleset matches the same existing tags as the tag protections. Nothing is written.
+func PlanTagProtectionMigration(ctx context.Context, g *GitHubClient, repo repository.Repository, name string) (*TagProtectionConversion, error) {
+	tagProtections, err := ListTagProtections(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	if len(tagProtections) == 0 {
+		return nil, fmt.Errorf("no tag protections found in %s/%s", repo.Owner, repo.Name)
+	}
+	ruleset := ConvertTagProtectionsToRuleset(name, tagProtections)
+
+	tags, err := ListTags(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	tagNames := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.GetName())
+	}
+	return compareTagProtectionConversion(ruleset, tagProtections, tagNames), nil
+}
+
+// compareTagProtectionConversion matches the tag names against the tag protections and, independently,
+// against the ruleset, and records the tags on which they disagree.
+func compareTagProtectionConversion(ruleset *github.RepositoryRuleset, tagProtections []*github.TagProtection, tagNames []string) *TagProtectionConversion {
+	protected := []string{}
+	rulesetTags := []string{}
+	for _, tagName := range tagNames {
+		for _, tp := range tagProtections {
+			if tp != nil && tp.GetPattern() != "" && MatchTagProtectionPattern(tp.GetPattern(), tagName) {
+				protected = append(protected, tagName)
+				break
+			}
+		}
+		if matchTagRulesetRefName(tagName, ruleset.GetConditions().GetRefName()) {
+			rulesetTags = append(rulesetTags, tagName)
+		}
+	}
+
+	conversion := &TagProtectionConversion{
+		Ruleset:         ruleset,
+		TagProtections:  tagProtections,
+		RulesetPatterns: convertTagProtectionPatterns(tagProtections),
+		ProtectedTags:   protected,
+		RulesetTags:     rulesetTags,
+	}
+	for _, tag := range protected {
+		if !slices.Contains(rulesetTags, tag) {
+			conversion.Missing = append(conversion.Missing, tag)
+		}
+	}
+	for _, tag := range rulesetTags {
+		if !slices.Contains(protected, tag) {
+			conversion.Extra = append(conversion.Extra, tag)
+		}
+	}
+	return conversion
+}
+
+// ApplyTagProtectionMigration creates or updates the converted tag ruleset.
+// When deleteTagProtections is true, the old tag protections are removed, but only if the
+// conversion is equivalent and the persisted ruleset is active.
+func ApplyTagProtectionMigration(ctx context.Context, g *GitHubClient, repo repository.Repository, conversion *TagProtectionConversion, deleteTagProtections bool) (*github.RepositoryRuleset, error) {
+	ruleset, err := CreateOrUpdateRepositoryRuleset(ctx, g, repo, conversion.Ruleset)
+	if err != nil {
+		return nil, err
+	}
+	if !deleteTagProtections {
+		return ruleset, nil
+	}
+	if !conversion.Equivalent() {
+		logger.Warn("Converted ruleset does not match the same tags as the tag protections, keeping tag protections", "missing", conversion.Missing, "extra", conversion.Extra)
+		return ruleset, nil
+	}
+	if ruleset.Enforcement != github.RulesetEnforcementActive {
+		logger.Warn("Converted ruleset is not active, keeping tag protections", "ruleset", ruleset.Name, "enforcement", ruleset.Enforcement)
+		return ruleset, nil
+	}
+	for _, tp := range conversion.TagProtections {
+		if tp == nil || tp.ID == nil {
+			continue
+		}
+		if err := RemoveTagProtection(ctx, g, repo, tp.GetID()); err != nil {
+			return ruleset, fmt.Errorf("failed to remove tag protection %q: %w", tp.GetPattern(), err)
+		}
+		logger.Info("Removed tag protection replaced by ruleset", "pattern", tp.GetPattern(), "ruleset", ruleset.Name)
+	}
+	return ruleset, nil
+}
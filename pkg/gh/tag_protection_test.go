@@ -0,0 +1,117 @@
+package gh
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func TestConvertTagProtectionsToRuleset(t *testing.T) {
+	ruleset := ConvertTagProtectionsToRuleset("tag-protection", []*github.TagProtection{
+		{ID: github.Ptr(int64(1)), Pattern: github.Ptr("v*")},
+		{ID: github.Ptr(int64(2)), Pattern: github.Ptr("release/**")},
+	})
+	include := ruleset.Conditions.RefName.Include
+	if len(include) != 2 || include[0] != "refs/tags/v**" || include[1] != "refs/tags/release/**" {
+		t.Errorf("include = %v", include)
+	}
+	if ruleset.Rules.Creation == nil || ruleset.Rules.Update == nil || ruleset.Rules.Deletion == nil {
+		t.Errorf("rules = %+v, want creation, update and deletion", ruleset.Rules)
+	}
+	if len(ruleset.BypassActors) != 2 {
+		t.Errorf("bypass actors = %d, want 2", len(ruleset.BypassActors))
+	}
+}
+
+func TestMatchTagProtectionPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		tag     string
+		want    bool
+	}{
+		{"v*", "v1.0.0", true},
+		{"v*", "v1/rc", true},
+		{"v*", "release-1", false},
+		{"release-?", "release-1", true},
+		{"release?1", "release/1", true},
+		{"v[0-9]*", "v1.0", true},
+		{"v[!0-9]*", "v1.0", false},
+		{"v[!0-9]*", "vx", true},
+		{"v\\*", "v*", true},
+		{"v\\*", "v1", false},
+		{"*-rc", "v1/2-rc", true},
+		{"*-rc", "v1-rc2", false},
+		{"v[", "v[", true},
+	}
+	for _, tt := range tests {
+		if got := MatchTagProtectionPattern(tt.pattern, tt.tag); got != tt.want {
+			t.Errorf("MatchTagProtectionPattern(%q, %q) = %v, want %v", tt.pattern, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTagRulesetPattern(t *testing.T) {
+	if !matchTagRulesetPattern("refs/tags/v**", "v1/rc") {
+		t.Error("refs/tags/v** should match v1/rc")
+	}
+	if matchTagRulesetPattern("refs/tags/v*", "v1/rc") {
+		t.Error("refs/tags/v* should not match v1/rc")
+	}
+	if !matchTagRulesetPattern("refs/tags/v*", "v1") {
+		t.Error("refs/tags/v* should match v1")
+	}
+}
+
+func TestCompareTagProtectionConversion(t *testing.T) {
+	tagProtections := []*github.TagProtection{
+		{ID: github.Ptr(int64(1)), Pattern: github.Ptr("v*")},
+		{ID: github.Ptr(int64(2)), Pattern: github.Ptr("release?1")},
+	}
+	ruleset := ConvertTagProtectionsToRuleset("tag-protection", tagProtections)
+	tags := []string{"v1", "v1/rc", "release/1", "release-1", "other"}
+
+	conversion := compareTagProtectionConversion(ruleset, tagProtections, tags)
+	if want := []string{"v1", "v1/rc", "release/1", "release-1"}; !slices.Equal(conversion.ProtectedTags, want) {
+		t.Errorf("protected = %v, want %v", conversion.ProtectedTags, want)
+	}
+	// "?" does not match "/" in rulesets, so the converted ruleset misses release/1
+	if want := []string{"v1", "v1/rc", "release-1"}; !slices.Equal(conversion.RulesetTags, want) {
+		t.Errorf("ruleset tags = %v, want %v", conversion.RulesetTags, want)
+	}
+	if want := []string{"release/1"}; !slices.Equal(conversion.Missing, want) {
+		t.Errorf("missing = %v, want %v", conversion.Missing, want)
+	}
+	if conversion.Equivalent() {
+		t.Error("conversion should not be equivalent")
+	}
+
+	// A ruleset that narrows "*" to a single path segment is detected as well
+	ruleset.Conditions.RefName.Include = []string{"refs/tags/v*"}
+	conversion = compareTagProtectionConversion(ruleset, tagProtections[:1], tags)
+	if want := []string{"v1/rc"}; !slices.Equal(conversion.Missing, want) {
+		t.Errorf("missing = %v, want %v", conversion.Missing, want)
+	}
+}
+
+func TestCompareTagProtectionConversion_PatternMapping(t *testing.T) {
+	tagProtections := []*github.TagProtection{
+		{ID: github.Ptr(int64(1)), Pattern: github.Ptr("")},
+		nil,
+		{ID: github.Ptr(int64(3)), Pattern: github.Ptr("v*")},
+	}
+	ruleset := ConvertTagProtectionsToRuleset("tag-protection", tagProtections)
+	conversion := compareTagProtectionConversion(ruleset, tagProtections, nil)
+	if want := []string{"", "", "refs/tags/v**"}; !slices.Equal(conversion.RulesetPatterns, want) {
+		t.Errorf("ruleset patterns = %v, want %v", conversion.RulesetPatterns, want)
+	}
+}
+
+func TestTagProtectionConversion_Equivalent(t *testing.T) {
+	if !(&TagProtectionConversion{}).Equivalent() {
+		t.Error("empty conversion should be equivalent")
+	}
+	if (&TagProtectionConversion{Missing: []string{"v1"}}).Equivalent() {
+		t.Error("conversion with missing tags should not be equivalent")
+	}
+}
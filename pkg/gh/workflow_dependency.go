@@ -2,13 +2,16 @@ package gh
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
 	"github.com/srz-zumix/go-gh-extension/pkg/parser"
 )
 
@@ -61,7 +64,15 @@ func ResolveWorkflowFilePath(ctx context.Context, g *GitHubClient, repo reposito
 // GetWorkflowFileDependency fetches and parses a single workflow file to extract action dependencies.
 // If recursive is true, it also traverses referenced action repositories and reusable workflows.
 func GetWorkflowFileDependency(ctx context.Context, g *GitHubClient, repo repository.Repository, filePath string, ref *string, recursive bool, fallback *GitHubClient) ([]parser.WorkflowDependency, error) {
-	content, err := GetFileContent(ctx, g, repo, filePath, ref)
+	return GetSourceWorkflowFileDependency(ctx, NewRemoteWorkflowSource(g, repo), filePath, ref, recursive, fallback)
+}
+
+// GetSourceWorkflowFileDependency reads and parses a single workflow file of src.Repository to extract action dependencies.
+// If recursive is true, it also traverses referenced action repositories and reusable workflows.
+// Remote references are only followed when src has a client.
+func GetSourceWorkflowFileDependency(ctx context.Context, src *WorkflowSource, filePath string, ref *string, recursive bool, fallback *GitHubClient) ([]parser.WorkflowDependency, error) {
+	repo := src.Repository
+	content, err := src.readFile(ctx, repo, filePath, ref)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file content for %s: %w", filePath, err)
 	}
@@ -86,9 +97,9 @@ func GetWorkflowFileDependency(ctx context.Context, g *GitHubClient, repo reposi
 		visitedFiles := make(map[string]bool)
 		visitedFiles[filePath] = true
 
-		resolvedHosts := make(map[string]string)
+		resolvedHosts := make(map[string]resolvedAction)
 		resolvedUsing := make(map[string]string)
-		deps = traverseDependencyActions(ctx, g, fallback, repo, ref, deps, visited, visitedFiles, resolvedHosts, resolvedUsing)
+		deps = traverseDependencyActions(ctx, src, src.fallback(fallback), repo, ref, deps, visited, visitedFiles, resolvedHosts, resolvedUsing)
 	}
 	return deps, nil
 }
@@ -97,12 +108,21 @@ func GetWorkflowFileDependency(ctx context.Context, g *GitHubClient, repo reposi
 // to extract action/reusable workflow dependencies.
 // If recursive is true, it also traverses referenced action repositories and reusable workflows.
 func GetRepositoryWorkflowDependencies(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string, recursive bool, fallback *GitHubClient) ([]parser.WorkflowDependency, error) {
+	return GetSourceWorkflowDependencies(ctx, NewRemoteWorkflowSource(g, repo), ref, recursive, fallback)
+}
+
+// GetSourceWorkflowDependencies reads and parses the workflow files and the root action.yml of src.Repository
+// to extract action/reusable workflow dependencies.
+// If recursive is true, it also traverses referenced action repositories and reusable workflows.
+// Remote references are only followed when src has a client.
+func GetSourceWorkflowDependencies(ctx context.Context, src *WorkflowSource, ref *string, recursive bool, fallback *GitHubClient) ([]parser.WorkflowDependency, error) {
+	repo := src.Repository
 	var deps []parser.WorkflowDependency
 
 	// Fetch workflow files from .github/workflows/
-	workflowDeps, err := getWorkflowFileDependencies(ctx, g, repo, ref)
+	workflowDeps, err := getWorkflowFileDependencies(ctx, src, repo, ref)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get workflow file dependencies for %s: %w", parser.GetRepositoryFullName(repo), err)
+		return nil, fmt.Errorf("failed to get workflow file dependencies for %s: %w", src.Name(), err)
 	}
 	deps = append(deps, workflowDeps...)
 
@@ -111,14 +131,14 @@ func GetRepositoryWorkflowDependencies(ctx context.Context, g *GitHubClient, rep
 	// inaccessible private repositories; without this check a missing .github/workflows
 	// directory would be silently treated as "no workflows" even for repos that cannot be
 	// accessed. Repos that do have workflows skip this extra round-trip entirely.
-	if len(workflowDeps) == 0 {
-		if _, repoErr := GetRepository(ctx, g, repo); repoErr != nil {
+	if len(workflowDeps) == 0 && src.FS == nil {
+		if _, repoErr := GetRepository(ctx, src.Client, repo); repoErr != nil {
 			return nil, fmt.Errorf("failed to access repository %s: %w", parser.GetRepositoryFullName(repo), repoErr)
 		}
 	}
 
 	// Fetch action.yml or action.yaml if present
-	actionDep, _, err := getActionFileDependencies(ctx, g, repo, ref)
+	actionDep, _, err := getActionFileDependencies(ctx, src, repo, ref)
 	if err != nil {
 		// action.yml/action.yaml not found is not an error
 	} else if actionDep != nil {
@@ -135,9 +155,9 @@ func GetRepositoryWorkflowDependencies(ctx context.Context, g *GitHubClient, rep
 			visitedFiles[dep.Source] = true
 		}
 
-		resolvedHosts := make(map[string]string)
+		resolvedHosts := make(map[string]resolvedAction)
 		resolvedUsing := make(map[string]string)
-		deps = traverseDependencyActions(ctx, g, fallback, repo, ref, deps, visited, visitedFiles, resolvedHosts, resolvedUsing)
+		deps = traverseDependencyActions(ctx, src, src.fallback(fallback), repo, ref, deps, visited, visitedFiles, resolvedHosts, resolvedUsing)
 	}
 
 	return deps, nil
@@ -148,9 +168,10 @@ func GetRepositoryWorkflowDependencies(ctx context.Context, g *GitHubClient, rep
 // Workflow files in child repos are not fetched because they represent the child repo's own CI.
 // If actionDir is empty, the repository root action.yml/action.yaml is used.
 // ref specifies the git reference (tag, branch, SHA) to fetch content from; nil uses the default branch.
-// Returns the dependencies, the resolved host (which may differ from repo.Host after fallback),
-// the runs.using value of the action, and any error.
-func getChildRepositoryActionDependencies(ctx context.Context, g *GitHubClient, fallback *GitHubClient, repo repository.Repository, actionDir string, ref *string, visited map[string]bool, resolvedHosts map[string]string, resolvedUsing map[string]string) ([]parser.WorkflowDependency, string, string, error) {
+// Returns the dependencies, where the action resolved (the host may differ from repo.Host after fallback,
+// and is the last attempted host when the action was not found or not followed offline), the runs.using value
+// of the action, and any error other than a missing action file or a disabled remote source.
+func getChildRepositoryActionDependencies(ctx context.Context, src *WorkflowSource, fallback *GitHubClient, repo repository.Repository, actionDir string, ref *string, visited map[string]bool, resolvedHosts map[string]resolvedAction, resolvedUsing map[string]string) ([]parser.WorkflowDependency, resolvedAction, string, error) {
 	repoKey := parser.GetRepositoryFullName(repo)
 	// Use path-specific visited key when actionDir is set to allow traversal from
 	// multiple subdirectories of the same repository.
@@ -159,24 +180,33 @@ func getChildRepositoryActionDependencies(ctx context.Context, g *GitHubClient,
 		visitedKey = repoKey + ":" + actionDir
 	}
 	if visited[visitedKey] {
-		return nil, repo.Host, "", nil
+		if resolved, ok := resolvedHosts[visitedKey]; ok {
+			return nil, resolved, "", nil
+		}
+		return nil, resolvedAction{host: repo.Host, resolution: parser.ActionResolutionHost}, "", nil
 	}
 	visited[visitedKey] = true
 
-	activeClient := g
+	activeSource := src
 	activeFallback := fallback
+	primaryHost := repo.Host
 
 	var deps []parser.WorkflowDependency
 	var resolvedUse string
-	actionDep, using, err := getActionFileDependenciesFromDir(ctx, g, repo, actionDir, ref)
+	actionDep, using, err := getActionFileDependenciesFromDir(ctx, src, repo, actionDir, ref)
 	// Fallback to github.com if the primary host fails (e.g. GHES -> github.com)
 	if err != nil && fallback != nil && repo.Host != defaultHost {
 		repo.Host = defaultHost
-		actionDep, using, err = getActionFileDependenciesFromDir(ctx, fallback, repo, actionDir, ref)
-		activeClient = fallback
+		actionDep, using, err = getActionFileDependenciesFromDir(ctx, src.withClient(fallback), repo, actionDir, ref)
+		activeSource = src.withClient(fallback)
 		activeFallback = nil
 	}
-	if err == nil && actionDep != nil {
+	if err != nil {
+		// Keep the last attempted host so that the reference can still be linked
+		resolved, err := unresolvedAction(repo.Host, err)
+		return nil, resolved, "", err
+	}
+	if actionDep != nil {
 		actionDep.Source = repoKey + ":" + actionDep.Source
 		actionDep.Repository = repo
 		deps = append(deps, *actionDep)
@@ -188,38 +218,56 @@ func getChildRepositoryActionDependencies(ctx context.Context, g *GitHubClient,
 		visitedFiles[dep.Source] = true
 	}
 
-	deps = traverseDependencyActions(ctx, activeClient, activeFallback, repo, ref, deps, visited, visitedFiles, resolvedHosts, resolvedUsing)
-	return deps, repo.Host, resolvedUse, nil
+	deps = traverseDependencyActions(ctx, activeSource, activeFallback, repo, ref, deps, visited, visitedFiles, resolvedHosts, resolvedUsing)
+	return deps, resolvedAction{host: repo.Host, resolution: actionResolution(primaryHost, repo.Host)}, resolvedUse, nil
+}
+
+// resolvedAction records the host an action reference was fetched from, or last attempted when it
+// was not found, and the resulting parser.ActionResolution* value.
+type resolvedAction struct {
+	host       string
+	resolution string
 }
 
 // traverseDependencyActions traverses action references in deps and recursively fetches
 // dependencies from local reusable workflows, remote reusable workflows, and action repositories.
 // New deps are collected separately to avoid modifying the slice being iterated.
-// resolvedHosts caches the resolved host for each action key so that duplicate references
+// resolvedHosts caches the resolved host and resolution for each action key so that duplicate references
 // across deps get the correct host even when the action is already visited.
 // resolvedUsing caches the runs.using value for each action key.
-func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *GitHubClient, repo repository.Repository, ref *string, deps []parser.WorkflowDependency, visited map[string]bool, visitedFiles map[string]bool, resolvedHosts map[string]string, resolvedUsing map[string]string) []parser.WorkflowDependency {
+func traverseDependencyActions(ctx context.Context, src *WorkflowSource, fallback *GitHubClient, repo repository.Repository, ref *string, deps []parser.WorkflowDependency, visited map[string]bool, visitedFiles map[string]bool, resolvedHosts map[string]resolvedAction, resolvedUsing map[string]string) []parser.WorkflowDependency {
 	repoKey := parser.GetRepositoryFullName(repo)
 	var newDeps []parser.WorkflowDependency
 	for i := range deps {
 		for j := range deps[i].Actions {
 			action := deps[i].Actions[j]
+			// Expression references and docker images are never fetched
+			if action.Unresolvable != "" {
+				deps[i].Actions[j].Resolution = parser.ActionResolutionUnresolvable
+				continue
+			}
+			if action.Docker != nil {
+				deps[i].Actions[j].Resolution = parser.ActionResolutionDocker
+				continue
+			}
+
 			// Handle local reusable workflows (e.g. "./.github/workflows/release.yml")
 			if action.IsLocal && action.IsReusableWorkflow() {
 				deps[i].Actions[j].Host = repo.Host
+				deps[i].Actions[j].Resolution = parser.ActionResolutionLocal
 				localPath := action.LocalPath()
 				fileKey := repoKey + ":" + localPath
 				if visitedFiles[localPath] || visitedFiles[fileKey] {
 					continue
 				}
 				visitedFiles[localPath] = true
-				localDeps, localErr := getReusableWorkflowDependencies(ctx, g, repo, localPath, ref)
+				localDeps, localErr := getReusableWorkflowDependencies(ctx, src, repo, localPath, ref)
 				if localErr != nil {
 					continue
 				}
 				// Recursively traverse the local reusable workflow's action references
 				// so that actions referenced within it are also resolved.
-				localDeps = traverseDependencyActions(ctx, g, fallback, repo, ref, localDeps, visited, visitedFiles, resolvedHosts, resolvedUsing)
+				localDeps = traverseDependencyActions(ctx, src, fallback, repo, ref, localDeps, visited, visitedFiles, resolvedHosts, resolvedUsing)
 				newDeps = append(newDeps, localDeps...)
 				continue
 			}
@@ -247,17 +295,21 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 						if action.Ref != "" {
 							actionRef = &action.Ref
 						}
-						childDeps, resolvedHost, resolvedUse, childErr := getChildRepositoryActionDependencies(ctx, g, fallback, childRepo, action.Path, actionRef, visited, resolvedHosts, resolvedUsing)
-						if childErr == nil {
-							resolvedHosts[childKey] = resolvedHost
+						childDeps, resolved, resolvedUse, childErr := getChildRepositoryActionDependencies(ctx, src, fallback, childRepo, action.Path, actionRef, visited, resolvedHosts, resolvedUsing)
+						if childErr != nil {
+							logger.Warn("Failed to fetch action", "action", action.Raw, "error", childErr)
+						} else {
+							resolvedHosts[childKey] = resolved
 							resolvedUsing[childKey] = resolvedUse
-							deps[i].Actions[j].Host = resolvedHost
+							deps[i].Actions[j].Host = resolved.host
 							deps[i].Actions[j].Using = resolvedUse
+							deps[i].Actions[j].Resolution = resolved.resolution
 							newDeps = append(newDeps, childDeps...)
 						}
 					} else {
-						if host, ok := resolvedHosts[childKey]; ok {
-							deps[i].Actions[j].Host = host
+						if resolved, ok := resolvedHosts[childKey]; ok {
+							deps[i].Actions[j].Host = resolved.host
+							deps[i].Actions[j].Resolution = resolved.resolution
 						}
 						if use, ok := resolvedUsing[childKey]; ok {
 							deps[i].Actions[j].Using = use
@@ -266,8 +318,9 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 				} else {
 					// Local action in the current repository (e.g. "./my-action")
 					deps[i].Actions[j].Host = repo.Host
+					deps[i].Actions[j].Resolution = parser.ActionResolutionLocal
 					localPath := action.LocalPath()
-					localActionDeps, localUsing := getLocalActionDependencies(ctx, g, repo, localPath, ref, visitedFiles, repoKey)
+					localActionDeps, localUsing := getLocalActionDependencies(ctx, src, repo, localPath, ref, visitedFiles, repoKey)
 					if localUsing != "" {
 						resolvedUsing[localPath] = localUsing
 						deps[i].Actions[j].Using = localUsing
@@ -276,7 +329,7 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 					}
 					// Recursively traverse the local action's dependencies
 					// so that actions referenced within it are also resolved.
-					localActionDeps = traverseDependencyActions(ctx, g, fallback, repo, ref, localActionDeps, visited, visitedFiles, resolvedHosts, resolvedUsing)
+					localActionDeps = traverseDependencyActions(ctx, src, fallback, repo, ref, localActionDeps, visited, visitedFiles, resolvedHosts, resolvedUsing)
 					newDeps = append(newDeps, localActionDeps...)
 				}
 				continue
@@ -295,32 +348,44 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 				}
 				fileKey := parser.GetRepositoryFullName(childRepo) + ":" + action.Path
 				if visitedFiles[fileKey] {
-					if host, ok := resolvedHosts[fileKey]; ok {
-						deps[i].Actions[j].Host = host
+					if resolved, ok := resolvedHosts[fileKey]; ok {
+						deps[i].Actions[j].Host = resolved.host
+						deps[i].Actions[j].Resolution = resolved.resolution
 					}
 					continue
 				}
 				visitedFiles[fileKey] = true
-				activeClient := g
+				activeSource := src
 				activeFallback := fallback
 				var actionRef *string
 				if action.Ref != "" {
 					actionRef = &action.Ref
 				}
-				rwDeps, rwErr := getReusableWorkflowDependencies(ctx, g, childRepo, action.Path, actionRef)
+				rwDeps, rwErr := getReusableWorkflowDependencies(ctx, src, childRepo, action.Path, actionRef)
 				// Fallback to github.com if the primary host fails
 				if rwErr != nil && fallback != nil && childRepo.Host != defaultHost {
 					childRepo.Host = defaultHost
-					rwDeps, rwErr = getReusableWorkflowDependencies(ctx, fallback, childRepo, action.Path, actionRef)
-					activeClient = fallback
+					rwDeps, rwErr = getReusableWorkflowDependencies(ctx, src.withClient(fallback), childRepo, action.Path, actionRef)
+					activeSource = src.withClient(fallback)
 					activeFallback = nil
 				}
 				if rwErr != nil {
+					// Keep the last attempted host so that the reference can still be linked
+					resolved, err := unresolvedAction(childRepo.Host, rwErr)
+					if err != nil {
+						logger.Warn("Failed to fetch reusable workflow", "workflow", action.Raw, "error", err)
+						continue
+					}
+					resolvedHosts[fileKey] = resolved
+					deps[i].Actions[j].Host = resolved.host
+					deps[i].Actions[j].Resolution = resolved.resolution
 					continue
 				}
 				// Set the resolved host on the action reference itself
-				deps[i].Actions[j].Host = childRepo.Host
-				resolvedHosts[fileKey] = childRepo.Host
+				resolved := resolvedAction{host: childRepo.Host, resolution: actionResolution(repo.Host, childRepo.Host)}
+				deps[i].Actions[j].Host = resolved.host
+				deps[i].Actions[j].Resolution = resolved.resolution
+				resolvedHosts[fileKey] = resolved
 				// Prefix source with repo key for remote workflows
 				childKey := parser.GetRepositoryFullName(childRepo)
 				for k := range rwDeps {
@@ -328,7 +393,7 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 					rwDeps[k].Repository = childRepo
 				}
 				// Recursively traverse the remote reusable workflow's action references
-				rwDeps = traverseDependencyActions(ctx, activeClient, activeFallback, childRepo, actionRef, rwDeps, visited, visitedFiles, resolvedHosts, resolvedUsing)
+				rwDeps = traverseDependencyActions(ctx, activeSource, activeFallback, childRepo, actionRef, rwDeps, visited, visitedFiles, resolvedHosts, resolvedUsing)
 				newDeps = append(newDeps, rwDeps...)
 				continue
 			}
@@ -344,8 +409,9 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 				childKey = childKey + ":" + action.Path
 			}
 			if visited[childKey] {
-				if host, ok := resolvedHosts[childKey]; ok {
-					deps[i].Actions[j].Host = host
+				if resolved, ok := resolvedHosts[childKey]; ok {
+					deps[i].Actions[j].Host = resolved.host
+					deps[i].Actions[j].Resolution = resolved.resolution
 				}
 				if use, ok := resolvedUsing[childKey]; ok {
 					deps[i].Actions[j].Using = use
@@ -357,15 +423,17 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 			if action.Ref != "" {
 				actionRef = &action.Ref
 			}
-			childDeps, resolvedHost, resolvedUse, err := getChildRepositoryActionDependencies(ctx, g, fallback, childRepo, action.Path, actionRef, visited, resolvedHosts, resolvedUsing)
+			childDeps, resolved, resolvedUse, err := getChildRepositoryActionDependencies(ctx, src, fallback, childRepo, action.Path, actionRef, visited, resolvedHosts, resolvedUsing)
 			if err != nil {
 				// Skip repos that are not accessible
+				logger.Warn("Failed to fetch action", "action", action.Raw, "error", err)
 				continue
 			}
-			resolvedHosts[childKey] = resolvedHost
+			resolvedHosts[childKey] = resolved
 			resolvedUsing[childKey] = resolvedUse
-			deps[i].Actions[j].Host = resolvedHost
+			deps[i].Actions[j].Host = resolved.host
 			deps[i].Actions[j].Using = resolvedUse
+			deps[i].Actions[j].Resolution = resolved.resolution
 			newDeps = append(newDeps, childDeps...)
 		}
 	}
@@ -373,9 +441,37 @@ func traverseDependencyActions(ctx context.Context, g *GitHubClient, fallback *G
 	return append(deps, newDeps...)
 }
 
+// actionResolution describes where a remote action that was found resolved, given the host of the
+// referencing repository and the host the action was fetched from. Every action is looked up on the
+// referencing host first and on github.com through the fallback client only when it is not available there.
+func actionResolution(primaryHost, resolvedHost string) string {
+	if strings.EqualFold(resolvedHost, primaryHost) {
+		return parser.ActionResolutionHost
+	}
+	return parser.ActionResolutionFallback
+}
+
+// unresolvedAction describes a remote action or reusable workflow that could not be fetched from host.
+// A missing file is not-found and a remote reference that was not followed because no client is
+// available is offline; any other failure (e.g. authentication, rate limit or server errors) is returned.
+func unresolvedAction(host string, err error) (resolvedAction, error) {
+	switch {
+	case errors.Is(err, ErrRemoteSourceDisabled):
+		return resolvedAction{host: host, resolution: parser.ActionResolutionOffline}, nil
+	case isFileNotFound(err):
+		return resolvedAction{host: host, resolution: parser.ActionResolutionNotFound}, nil
+	}
+	return resolvedAction{}, err
+}
+
+// isFileNotFound returns true if err indicates that a file does not exist, either locally or on GitHub.
+func isFileNotFound(err error) bool {
+	return IsHTTPNotFound(err) || errors.Is(err, fs.ErrNotExist)
+}
+
 // getReusableWorkflowDependencies fetches and parses a single reusable workflow file
-func getReusableWorkflowDependencies(ctx context.Context, g *GitHubClient, repo repository.Repository, path string, ref *string) ([]parser.WorkflowDependency, error) {
-	content, err := GetFileContent(ctx, g, repo, path, ref)
+func getReusableWorkflowDependencies(ctx context.Context, src *WorkflowSource, repo repository.Repository, path string, ref *string) ([]parser.WorkflowDependency, error) {
+	content, err := src.readFile(ctx, repo, path, ref)
 	if err != nil {
 		return nil, err
 	}
@@ -401,7 +497,7 @@ func getReusableWorkflowDependencies(ctx context.Context, g *GitHubClient, repo
 
 // getLocalActionDependencies fetches action.yml/action.yaml from a local action directory
 // in the current repository and returns its dependencies and the runs.using value.
-func getLocalActionDependencies(ctx context.Context, g *GitHubClient, repo repository.Repository, localPath string, ref *string, visitedFiles map[string]bool, repoKey string) ([]parser.WorkflowDependency, string) {
+func getLocalActionDependencies(ctx context.Context, src *WorkflowSource, repo repository.Repository, localPath string, ref *string, visitedFiles map[string]bool, repoKey string) ([]parser.WorkflowDependency, string) {
 	for _, filename := range []string{"action.yml", "action.yaml"} {
 		actionPath := localPath + "/" + filename
 		fileKey := repoKey + ":" + actionPath
@@ -409,7 +505,7 @@ func getLocalActionDependencies(ctx context.Context, g *GitHubClient, repo repos
 			return nil, ""
 		}
 
-		content, err := GetFileContent(ctx, g, repo, actionPath, ref)
+		content, err := src.readFile(ctx, repo, actionPath, ref)
 		if err != nil {
 			continue
 		}
@@ -432,30 +528,22 @@ func getLocalActionDependencies(ctx context.Context, g *GitHubClient, repo repos
 	return nil, ""
 }
 
-// getWorkflowFileDependencies fetches all workflow YAML files from .github/workflows/ and parses them
-func getWorkflowFileDependencies(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string) ([]parser.WorkflowDependency, error) {
-	_, dirContent, err := g.GetRepositoryContent(ctx, repo.Owner, repo.Name, workflowsDir, ref)
+// getWorkflowFileDependencies reads all workflow YAML files from .github/workflows/ and parses them
+func getWorkflowFileDependencies(ctx context.Context, src *WorkflowSource, repo repository.Repository, ref *string) ([]parser.WorkflowDependency, error) {
+	names, err := src.listFiles(ctx, repo, workflowsDir, ref)
 	if err != nil {
-		// If the workflows directory does not exist, return empty result without error
-		if IsHTTPNotFound(err) {
-			return nil, nil
-		}
 		return nil, fmt.Errorf("failed to list workflow directory: %w", err)
 	}
 
 	var deps []parser.WorkflowDependency
-	for _, entry := range dirContent {
-		if entry.Name == nil || entry.Type == nil {
-			continue
-		}
-		name := *entry.Name
+	for _, name := range names {
 		// Only process YAML files
-		if *entry.Type != "file" || (!strings.HasSuffix(name, ".yml") && !strings.HasSuffix(name, ".yaml")) {
+		if !strings.HasSuffix(name, ".yml") && !strings.HasSuffix(name, ".yaml") {
 			continue
 		}
 
 		filePath := workflowsDir + "/" + name
-		content, err := GetFileContent(ctx, g, repo, filePath, ref)
+		content, err := src.readFile(ctx, repo, filePath, ref)
 		if err != nil {
 			continue // skip files that cannot be read
 		}
@@ -480,20 +568,27 @@ func getWorkflowFileDependencies(ctx context.Context, g *GitHubClient, repo repo
 
 // getActionFileDependenciesFromDir fetches action.yml or action.yaml from the specified directory
 // in the repository. If dir is empty, fetches from the repository root.
-// Returns the dependency, the runs.using value, and any error.
-func getActionFileDependenciesFromDir(ctx context.Context, g *GitHubClient, repo repository.Repository, dir string, ref *string) (*parser.WorkflowDependency, string, error) {
+// Returns the dependency, the runs.using value, and any error. When neither file can be read,
+// the error wraps the read failure, preferring one other than a missing file.
+func getActionFileDependenciesFromDir(ctx context.Context, src *WorkflowSource, repo repository.Repository, dir string, ref *string) (*parser.WorkflowDependency, string, error) {
+	var readErr error
 	for _, base := range []string{"action.yml", "action.yaml"} {
 		filename := base
 		if dir != "" {
 			filename = dir + "/" + base
 		}
-		content, err := GetFileContent(ctx, g, repo, filename, ref)
+		content, err := src.readFile(ctx, repo, filename, ref)
 		if err != nil {
+			// Prefer a failure other than a missing file so that callers can report it
+			if readErr == nil || isFileNotFound(readErr) {
+				readErr = err
+			}
 			continue
 		}
 
 		refs, using, err := parser.ParseActionYAML(content)
 		if err != nil {
+			readErr = fmt.Errorf("%s: %w", filename, err)
 			continue
 		}
 
@@ -506,12 +601,12 @@ func getActionFileDependenciesFromDir(ctx context.Context, g *GitHubClient, repo
 		// File exists but has no dependencies
 		return nil, "", nil
 	}
-	return nil, "", fmt.Errorf("no action.yml or action.yaml found")
+	return nil, "", fmt.Errorf("failed to read action.yml or action.yaml: %w", readErr)
 }
 
 // getActionFileDependencies fetches action.yml or action.yaml from the repository root and parses it
-func getActionFileDependencies(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string) (*parser.WorkflowDependency, string, error) {
-	return getActionFileDependenciesFromDir(ctx, g, repo, "", ref)
+func getActionFileDependencies(ctx context.Context, src *WorkflowSource, repo repository.Repository, ref *string) (*parser.WorkflowDependency, string, error) {
+	return getActionFileDependenciesFromDir(ctx, src, repo, "", ref)
 }
 
 // FlattenWorkflowDependencies extracts all unique ActionReferences from multiple WorkflowDependencies
@@ -0,0 +1,293 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// maxWorkflowDispatchInputs is the maximum number of inputs the dispatch API accepts
+const maxWorkflowDispatchInputs = 25
+
+// Default intervals and timeout of DispatchWorkflow
+const (
+	DefaultWorkflowDispatchPollInterval = 5 * time.Second
+	DefaultWorkflowDispatchTimeout      = 30 * time.Minute
+	// workflowDispatchClockSkew is subtracted from the dispatch time when looking for the created run
+	workflowDispatchClockSkew = 10 * time.Second
+)
+
+// GetWorkflowDispatchInputs fetches a workflow file at ref and returns its workflow_dispatch inputs.
+// workflowFile is a file name under .github/workflows or a path. It fails when the workflow has no
+// workflow_dispatch trigger.
+func GetWorkflowDispatchInputs(ctx context.Context, g *GitHubClient, repo repository.Repository, workflowFile string, ref *string) ([]*parser.WorkflowInput, error) {
+	filePath := workflowFile
+	if !strings.Contains(filePath, "/") {
+		filePath = path.Join(workflowsDir, workflowFile)
+	}
+	content, err := GetFileContent(ctx, g, repo, filePath, ref)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := parser.ParseWorkflow(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow %s: %w", filePath, err)
+	}
+	event := wf.Event(actions.EventWorkflowDispatch)
+	if event == nil {
+		return nil, fmt.Errorf("workflow %s is not triggered by %s", filePath, actions.EventWorkflowDispatch)
+	}
+	return event.Inputs, nil
+}
+
+// ValidateWorkflowDispatchInputs checks inputs against the workflow_dispatch inputs of a workflow and
+// converts them to the JSON types the dispatch API expects: boolean inputs become bool and number
+// inputs float64. Unknown inputs, missing required inputs without a default, values that do not parse
+// as their type and choices that are not among the options are all reported in the returned error.
+func ValidateWorkflowDispatchInputs(schema []*parser.WorkflowInput, inputs map[string]string) (map[string]any, error) {
+	known := make(map[string]*parser.WorkflowInput, len(schema))
+	for _, input := range schema {
+		known[input.Name] = input
+	}
+
+	var errs []error
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	coerced := make(map[string]any, len(inputs))
+	for _, name := range names {
+		input, ok := known[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown input %q", name))
+			continue
+		}
+		value, err := coerceWorkflowDispatchInput(input, inputs[name])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		coerced[name] = value
+	}
+	for _, input := range schema {
+		if _, ok := inputs[input.Name]; !ok && input.Required && !input.HasDefault {
+			errs = append(errs, fmt.Errorf("input %q is required", input.Name))
+		}
+	}
+	if len(inputs) > maxWorkflowDispatchInputs {
+		errs = append(errs, fmt.Errorf("%d inputs given, at most %d are allowed", len(inputs), maxWorkflowDispatchInputs))
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid workflow inputs: %w", errors.Join(errs...))
+	}
+	return coerced, nil
+}
+
+func coerceWorkflowDispatchInput(input *parser.WorkflowInput, value string) (any, error) {
+	switch input.Type {
+	case "boolean":
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("input %q must be a boolean, got %q", input.Name, value)
+		}
+		return b, nil
+	case "number":
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("input %q must be a number, got %q", input.Name, value)
+		}
+		return n, nil
+	case "choice":
+		if !slices.Contains(input.Options, value) {
+			return nil, fmt.Errorf("input %q must be one of %s, got %q", input.Name, strings.Join(input.Options, ", "), value)
+		}
+		return value, nil
+	default:
+		// string, environment and untyped inputs
+		if input.Required && value == "" {
+			return nil, fmt.Errorf("input %q is required", input.Name)
+		}
+		return value, nil
+	}
+}
+
+// WorkflowDispatchOptions configures DispatchWorkflow
+type WorkflowDispatchOptions struct {
+	Ref          string            // branch or tag; defaults to the repository's default branch
+	Inputs       map[string]string // validated against the workflow's workflow_dispatch inputs
+	Wait         bool              // wait for the run to complete
+	Timeout      time.Duration     // how long to wait for the run; defaults to DefaultWorkflowDispatchTimeout
+	PollInterval time.Duration     // defaults to DefaultWorkflowDispatchPollInterval
+}
+
+// WorkflowDispatchResult is the run created by DispatchWorkflow
+type WorkflowDispatchResult struct {
+	Run        *github.WorkflowRun   `json:"run"`
+	FailedJobs []*github.WorkflowJob `json:"failed_jobs,omitempty"` // set when waited for a run that did not succeed
+}
+
+// Conclusion returns the conclusion of the run, or "" while it is not completed
+func (r *WorkflowDispatchResult) Conclusion() string {
+	return r.Run.GetConclusion()
+}
+
+// SetActionsOutputs writes run-id, run-url, status and conclusion as step outputs with actions.Output,
+// so that a dispatching step can pass the run to later steps
+func (r *WorkflowDispatchResult) SetActionsOutputs() error {
+	outputs := [][2]string{
+		{"run-id", strconv.FormatInt(r.Run.GetID(), 10)},
+		{"run-url", r.Run.GetHTMLURL()},
+		{"status", r.Run.GetStatus()},
+		{"conclusion", r.Run.GetConclusion()},
+	}
+	for _, output := range outputs {
+		if err := actions.Output(output[0], output[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DispatchWorkflow validates inputs against the workflow's workflow_dispatch inputs at the target ref,
+// dispatches the workflow and returns the created run. The run is taken from the dispatch response
+// and, on servers that do not return it, found by polling the workflow's runs. With options.Wait it
+// waits until the run completes or options.Timeout passes, and reports the jobs that did not succeed.
+func DispatchWorkflow(ctx context.Context, g *GitHubClient, repo repository.Repository, workflowFile string, options *WorkflowDispatchOptions) (*WorkflowDispatchResult, error) {
+	if options == nil {
+		options = &WorkflowDispatchOptions{}
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWorkflowDispatchTimeout
+	}
+	interval := options.PollInterval
+	if interval <= 0 {
+		interval = DefaultWorkflowDispatchPollInterval
+	}
+
+	ref := options.Ref
+	if ref == "" {
+		r, err := GetRepository(ctx, g, repo)
+		if err != nil {
+			return nil, err
+		}
+		ref = r.GetDefaultBranch()
+	}
+	schema, err := GetWorkflowDispatchInputs(ctx, g, repo, workflowFile, &ref)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := ValidateWorkflowDispatchInputs(schema, options.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatchedAt := time.Now()
+	details, err := CreateWorkflowDispatchEventByFileName(ctx, g, repo, path.Base(workflowFile), github.CreateWorkflowDispatchEventRequest{
+		Ref:              ref,
+		Inputs:           inputs,
+		ReturnRunDetails: github.Ptr(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispatch workflow %s: %w", workflowFile, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var run *github.WorkflowRun
+	if runID := details.GetWorkflowRunID(); runID != 0 {
+		run, err = GetWorkflowRunByID(ctx, g, repo, runID)
+	} else {
+		run, err = findDispatchedWorkflowRun(ctx, g, repo, workflowFile, ref, dispatchedAt, interval)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := &WorkflowDispatchResult{Run: run}
+	if !options.Wait {
+		return result, nil
+	}
+
+	for result.Run.GetStatus() != "completed" {
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("timed out waiting for workflow run %d: %w", result.Run.GetID(), ctx.Err())
+		case <-time.After(interval):
+		}
+		run, err := GetWorkflowRunByID(ctx, g, repo, result.Run.GetID())
+		if err != nil {
+			return result, fmt.Errorf("failed to get workflow run %d: %w", result.Run.GetID(), err)
+		}
+		result.Run = run
+	}
+
+	jobs, err := ListWorkflowJobs(ctx, g, repo, result.Run.GetID(), github.Ptr("latest"))
+	if err != nil {
+		return result, fmt.Errorf("failed to list jobs of workflow run %d: %w", result.Run.GetID(), err)
+	}
+	result.FailedJobs = failedWorkflowJobs(jobs)
+	return result, nil
+}
+
+// findDispatchedWorkflowRun polls the workflow_dispatch runs of the workflow on ref until one
+// created after dispatchedAt appears
+func findDispatchedWorkflowRun(ctx context.Context, g *GitHubClient, repo repository.Repository, workflowFile, ref string, dispatchedAt time.Time, interval time.Duration) (*github.WorkflowRun, error) {
+	since := dispatchedAt.Add(-workflowDispatchClockSkew)
+	for {
+		runs, err := ListWorkflowRunsByFileName(ctx, g, repo, path.Base(workflowFile), &ListWorkflowRunsOptions{
+			Branch:  ref,
+			Event:   actions.EventWorkflowDispatch,
+			Created: ">=" + since.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runs of workflow %s: %w", workflowFile, err)
+		}
+		if run := earliestWorkflowRunSince(runs, since); run != nil {
+			return run, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the dispatched run of workflow %s: %w", workflowFile, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// earliestWorkflowRunSince returns the first run created at or after since, or nil
+func earliestWorkflowRunSince(runs []*github.WorkflowRun, since time.Time) *github.WorkflowRun {
+	var earliest *github.WorkflowRun
+	for _, run := range runs {
+		if run.GetCreatedAt().Before(since) {
+			continue
+		}
+		if earliest == nil || run.GetCreatedAt().Before(earliest.GetCreatedAt().Time) {
+			earliest = run
+		}
+	}
+	return earliest
+}
+
+// failedWorkflowJobs returns the jobs that completed without success, neutral or skipped
+func failedWorkflowJobs(jobs []*github.WorkflowJob) []*github.WorkflowJob {
+	var failed []*github.WorkflowJob
+	for _, job := range jobs {
+		switch job.GetConclusion() {
+		case "success", "neutral", "skipped", "":
+			continue
+		}
+		failed = append(failed, job)
+	}
+	return failed
+}
@@ -0,0 +1,132 @@
+package gh
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+var testDispatchWorkflow = []byte(`on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: choice
+        required: true
+        options: [staging, production]
+      dry-run:
+        type: boolean
+        default: true
+      replicas:
+        type: number
+      message:
+        required: true
+        default: hello
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`)
+
+func testDispatchInputs(t *testing.T) []*parser.WorkflowInput {
+	t.Helper()
+	wf, err := parser.ParseWorkflow(testDispatchWorkflow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wf.Event("workflow_dispatch").Inputs
+}
+
+func TestValidateWorkflowDispatchInputs(t *testing.T) {
+	schema := testDispatchInputs(t)
+	got, err := ValidateWorkflowDispatchInputs(schema, map[string]string{
+		"environment": "staging",
+		"dry-run":     "false",
+		"replicas":    "3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["environment"] != "staging" || got["dry-run"] != false || got["replicas"] != 3.0 {
+		t.Errorf("coerced = %#v", got)
+	}
+	if _, ok := got["message"]; ok {
+		t.Error("omitted inputs must be left to their defaults")
+	}
+}
+
+func TestValidateWorkflowDispatchInputs_Errors(t *testing.T) {
+	schema := testDispatchInputs(t)
+	_, err := ValidateWorkflowDispatchInputs(schema, map[string]string{
+		"environment": "dev",
+		"dry-run":     "maybe",
+		"replicas":    "three",
+		"unknown":     "x",
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`"environment" must be one of staging, production`, `"dry-run" must be a boolean`, `"replicas" must be a number`, `unknown input "unknown"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	if _, err := ValidateWorkflowDispatchInputs(schema, nil); err == nil || !strings.Contains(err.Error(), `input "environment" is required`) {
+		t.Errorf("err = %v, want required environment", err)
+	}
+}
+
+func TestEarliestWorkflowRunSince(t *testing.T) {
+	since := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	at := func(id int64, d time.Duration) *github.WorkflowRun {
+		return &github.WorkflowRun{ID: github.Ptr(id), CreatedAt: &github.Timestamp{Time: since.Add(d)}}
+	}
+	runs := []*github.WorkflowRun{at(3, 20*time.Second), at(1, -time.Minute), at(2, 5*time.Second)}
+	if got := earliestWorkflowRunSince(runs, since); got.GetID() != 2 {
+		t.Errorf("earliestWorkflowRunSince() = %d, want 2", got.GetID())
+	}
+	if got := earliestWorkflowRunSince(runs[1:2], since); got != nil {
+		t.Errorf("earliestWorkflowRunSince() = %d, want nil", got.GetID())
+	}
+}
+
+func TestFailedWorkflowJobs(t *testing.T) {
+	job := func(name, conclusion string) *github.WorkflowJob {
+		return &github.WorkflowJob{Name: github.Ptr(name), Conclusion: github.Ptr(conclusion)}
+	}
+	failed := failedWorkflowJobs([]*github.WorkflowJob{job("a", "success"), job("b", "failure"), job("c", "skipped"), job("d", "timed_out")})
+	if len(failed) != 2 || failed[0].GetName() != "b" || failed[1].GetName() != "d" {
+		t.Errorf("failedWorkflowJobs() = %v", failed)
+	}
+}
+
+func TestWorkflowDispatchResult_SetActionsOutputs(t *testing.T) {
+	path := t.TempDir() + "/output"
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_OUTPUT", path)
+	result := &WorkflowDispatchResult{Run: &github.WorkflowRun{
+		ID:         github.Ptr(int64(42)),
+		HTMLURL:    github.Ptr("https://github.com/o/r/actions/runs/42"),
+		Status:     github.Ptr("completed"),
+		Conclusion: github.Ptr("success"),
+	}}
+	if err := result.SetActionsOutputs(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"run-id", "42", "run-url", "https://github.com/o/r/actions/runs/42", "conclusion", "success"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("GITHUB_OUTPUT %q does not contain %q", data, want)
+		}
+	}
+}
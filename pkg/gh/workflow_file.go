@@ -0,0 +1,115 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// WorkflowFile is a workflow or action metadata file loaded from a repository or a local directory.
+// LocalPath is set for files read from disk; BlobSHA is set for files fetched through the contents API.
+type WorkflowFile struct {
+	Repository repository.Repository `json:"repository"`
+	Path       string                `json:"path"`
+	LocalPath  string                `json:"local_path,omitempty"`
+	BlobSHA    string                `json:"blob_sha,omitempty"`
+	Content    []byte                `json:"-"`
+}
+
+// IsActionMetadata returns true if the file is an action.yml/action.yaml file
+func (f *WorkflowFile) IsActionMetadata() bool {
+	return isActionMetadataFile(filepath.Base(f.Path))
+}
+
+// GetRepositoryWorkflowFiles fetches the workflow files under .github/workflows and the root
+// action.yml/action.yaml of a repository.
+func GetRepositoryWorkflowFiles(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string) ([]*WorkflowFile, error) {
+	var paths []string
+	_, dirContent, err := g.GetRepositoryContent(ctx, repo.Owner, repo.Name, workflowsDir, ref)
+	if err != nil && !IsHTTPNotFound(err) {
+		return nil, fmt.Errorf("failed to list workflow directory: %w", err)
+	}
+	for _, entry := range dirContent {
+		if entry.GetType() == "file" && isYAMLFile(entry.GetName()) {
+			paths = append(paths, workflowsDir+"/"+entry.GetName())
+		}
+	}
+	paths = append(paths, "action.yml", "action.yaml")
+
+	var files []*WorkflowFile
+	for _, path := range paths {
+		fileContent, err := GetRepositoryFileContent(ctx, g, repo, path, ref)
+		if err != nil {
+			if isActionMetadataFile(path) {
+				continue
+			}
+			return nil, err
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode file content for %s: %w", path, err)
+		}
+		files = append(files, &WorkflowFile{
+			Repository: repo,
+			Path:       path,
+			BlobSHA:    fileContent.GetSHA(),
+			Content:    []byte(content),
+		})
+	}
+	return files, nil
+}
+
+// LoadLocalWorkflowFiles reads the workflow files under .github/workflows and every
+// action.yml/action.yaml below dir. Paths are reported relative to dir.
+func LoadLocalWorkflowFiles(dir string) ([]*WorkflowFile, error) {
+	var files []*WorkflowFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (d.Name() == ".git" || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		isWorkflow := filepath.ToSlash(filepath.Dir(rel)) == workflowsDir && isYAMLFile(d.Name())
+		if !isWorkflow && !isActionMetadataFile(d.Name()) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files = append(files, &WorkflowFile{
+			Path:      rel,
+			LocalPath: path,
+			Content:   content,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// isYAMLFile returns true if name has a .yml or .yaml extension
+func isYAMLFile(name string) bool {
+	return strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")
+}
+
+// isActionMetadataFile returns true if name is action.yml or action.yaml
+func isActionMetadataFile(name string) bool {
+	return name == "action.yml" || name == "action.yaml"
+}
@@ -0,0 +1,244 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// WorkflowNode is a workflow file node in job and trigger graphs.
+// Path is empty for workflows that are only known by name (e.g. an unresolved workflow_run source).
+type WorkflowNode struct {
+	Repository repository.Repository `json:"repository"`
+	Path       string                `json:"path,omitempty"`
+	Name       string                `json:"name,omitempty"`
+}
+
+// GetName returns the node label in the same "owner/repo:path" form used by WorkflowDependency.Source
+func (n WorkflowNode) GetName() string {
+	file := n.Path
+	if file == "" {
+		file = n.Name
+	}
+	if n.Repository.Owner == "" {
+		return file
+	}
+	return parser.GetRepositoryFullName(n.Repository) + ":" + file
+}
+
+// WorkflowJobNode is a job node in a job graph
+type WorkflowJobNode struct {
+	Repository repository.Repository `json:"repository"`
+	Path       string                `json:"path"`
+	JobID      string                `json:"job_id"`
+}
+
+// GetName returns the node label, e.g. "ci.yml:build"
+func (n WorkflowJobNode) GetName() string {
+	return path.Base(n.Path) + ":" + n.JobID
+}
+
+// WorkflowEventNode is an event node in a trigger graph, e.g. a repository_dispatch event type
+type WorkflowEventNode struct {
+	Repository repository.Repository `json:"repository"`
+	Event      string                `json:"event"`
+	Type       string                `json:"type,omitempty"`
+}
+
+// GetName returns the node label, e.g. "owner/repo:repository_dispatch(deploy)"
+func (n WorkflowEventNode) GetName() string {
+	name := n.Event
+	if n.Type != "" {
+		name += "(" + n.Type + ")"
+	}
+	if n.Repository.Owner == "" {
+		return name
+	}
+	return parser.GetRepositoryFullName(n.Repository) + ":" + name
+}
+
+// workflowHTMLURL returns the blob URL of a workflow file on the default branch
+func workflowHTMLURL(repo repository.Repository, filePath string, host string) string {
+	if repo.Owner == "" || filePath == "" {
+		return ""
+	}
+	if repo.Host != "" {
+		host = repo.Host
+	}
+	return "https://" + host + "/" + repo.Owner + "/" + repo.Name + "/blob/HEAD/" + filePath
+}
+
+// parsedWorkflowFile is a workflow file together with its parsed model
+type parsedWorkflowFile struct {
+	file     *WorkflowFile
+	workflow *parser.Workflow
+}
+
+// parseWorkflowFiles parses the workflow files, skipping action metadata files
+func parseWorkflowFiles(files []*WorkflowFile) ([]parsedWorkflowFile, error) {
+	var parsed []parsedWorkflowFile
+	for _, file := range files {
+		if file.IsActionMetadata() {
+			continue
+		}
+		wf, err := parser.ParseWorkflow(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse workflow file %s: %w", file.Path, err)
+		}
+		parsed = append(parsed, parsedWorkflowFile{file: file, workflow: wf})
+	}
+	return parsed, nil
+}
+
+// BuildWorkflowJobGraph builds the job dependency graph of each workflow from jobs.<job_id>.needs.
+// Jobs without needs are linked from their workflow node so that every job appears in the graph.
+func BuildWorkflowJobGraph(files []*WorkflowFile) ([]GraphEdge, error) {
+	parsed, err := parseWorkflowFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	var edges []GraphEdge
+	for _, p := range parsed {
+		repo := p.file.Repository
+		wfNode := WorkflowNode{Repository: repo, Path: p.file.Path, Name: p.workflow.Name}
+		for _, job := range p.workflow.Jobs {
+			jobNode := WorkflowJobNode{Repository: repo, Path: p.file.Path, JobID: job.ID}
+			if len(job.Needs) == 0 {
+				edges = append(edges, GraphEdge{From: wfNode, To: jobNode, Host: repo.Host})
+				continue
+			}
+			for _, need := range job.Needs {
+				edges = append(edges, GraphEdge{
+					From: WorkflowJobNode{Repository: repo, Path: p.file.Path, JobID: need},
+					To:   jobNode,
+					Host: repo.Host,
+				})
+			}
+		}
+	}
+	return edges, nil
+}
+
+// BuildWorkflowTriggerGraph builds the graph of how workflows trigger each other across the given files,
+// which may come from several repositories:
+//   - workflow_run: the named source workflows of the same repository -> the workflow
+//   - workflow_call: each caller workflow -> the reusable workflow it uses
+//   - repository_dispatch: the event type -> the workflow, and workflows sending the event type -> the event type
+func BuildWorkflowTriggerGraph(files []*WorkflowFile) ([]GraphEdge, error) {
+	parsed, err := parseWorkflowFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// workflow_run refers to workflows by name; a workflow without a name is known by its path
+	byName := make(map[string]WorkflowNode)
+	for _, p := range parsed {
+		node := WorkflowNode{Repository: p.file.Repository, Path: p.file.Path, Name: p.workflow.Name}
+		repoKey := strings.ToLower(parser.GetRepositoryFullName(p.file.Repository))
+		byName[repoKey+":"+p.file.Path] = node
+		if p.workflow.Name != "" {
+			byName[repoKey+":"+p.workflow.Name] = node
+		}
+	}
+
+	var edges []GraphEdge
+	for _, p := range parsed {
+		repo := p.file.Repository
+		wfNode := WorkflowNode{Repository: repo, Path: p.file.Path, Name: p.workflow.Name}
+		repoKey := strings.ToLower(parser.GetRepositoryFullName(repo))
+
+		if event := p.workflow.Event("workflow_run"); event != nil {
+			for _, name := range event.Workflows {
+				source, ok := byName[repoKey+":"+name]
+				if !ok {
+					source = WorkflowNode{Repository: repo, Name: name}
+				}
+				edges = append(edges, GraphEdge{From: source, To: wfNode, Host: repo.Host})
+			}
+		}
+		if event := p.workflow.Event("repository_dispatch"); event != nil {
+			types := event.Types
+			if len(types) == 0 {
+				types = []string{""}
+			}
+			for _, t := range types {
+				eventNode := WorkflowEventNode{Repository: repo, Event: "repository_dispatch", Type: t}
+				edges = append(edges, GraphEdge{From: eventNode, To: wfNode, Host: repo.Host})
+			}
+		}
+
+		for _, job := range p.workflow.Jobs {
+			if job.Uses != "" {
+				if callee, ok := reusableWorkflowNode(repo, job.Uses); ok {
+					edges = append(edges, GraphEdge{From: wfNode, To: callee, Host: repo.Host})
+				}
+			}
+			for _, step := range job.Steps {
+				if eventNode, ok := repositoryDispatchSenderEvent(repo, step); ok {
+					edges = append(edges, GraphEdge{From: wfNode, To: eventNode, Host: repo.Host})
+				}
+			}
+		}
+	}
+	return edges, nil
+}
+
+// reusableWorkflowNode resolves a jobs.<job_id>.uses value to the called workflow node
+func reusableWorkflowNode(repo repository.Repository, uses string) (WorkflowNode, bool) {
+	action := parser.ParseActionReference(uses)
+	if !action.IsReusableWorkflow() {
+		return WorkflowNode{}, false
+	}
+	if action.IsLocal {
+		return WorkflowNode{Repository: repo, Path: action.LocalPath()}, true
+	}
+	return WorkflowNode{
+		Repository: repository.Repository{Host: repo.Host, Owner: action.Owner, Name: action.Repo},
+		Path:       action.Path,
+	}, true
+}
+
+// repositoryDispatchSenderEvent returns the event node a step sends through a repository-dispatch action
+// (e.g. peter-evans/repository-dispatch), which takes the event type and an optional target repository as inputs.
+func repositoryDispatchSenderEvent(repo repository.Repository, step *parser.WorkflowStep) (WorkflowEventNode, bool) {
+	if step.Uses == "" || !strings.EqualFold(parser.ParseActionReference(step.Uses).Repo, "repository-dispatch") {
+		return WorkflowEventNode{}, false
+	}
+	eventType := step.With["event-type"]
+	if eventType == "" {
+		return WorkflowEventNode{}, false
+	}
+	target := repo
+	if name := step.With["repository"]; name != "" && !strings.Contains(name, "${{") {
+		if owner, repoName, ok := strings.Cut(name, "/"); ok {
+			target = repository.Repository{Host: repo.Host, Owner: owner, Name: repoName}
+		}
+	}
+	return WorkflowEventNode{Repository: target, Event: "repository_dispatch", Type: eventType}, true
+}
+
+// GetRepositoryWorkflowJobGraph builds the job dependency graph of the workflows in a repository
+func GetRepositoryWorkflowJobGraph(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string) ([]GraphEdge, error) {
+	files, err := GetRepositoryWorkflowFiles(ctx, g, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return BuildWorkflowJobGraph(files)
+}
+
+// GetRepositoriesWorkflowTriggerGraph builds the trigger graph across the workflows of the given repositories
+func GetRepositoriesWorkflowTriggerGraph(ctx context.Context, g *GitHubClient, repos []repository.Repository, ref *string) ([]GraphEdge, error) {
+	var files []*WorkflowFile
+	for _, repo := range repos {
+		repoFiles, err := GetRepositoryWorkflowFiles(ctx, g, repo, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow files for %s: %w", parser.GetRepositoryFullName(repo), err)
+		}
+		files = append(files, repoFiles...)
+	}
+	return BuildWorkflowTriggerGraph(files)
+}
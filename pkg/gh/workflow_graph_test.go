@@ -0,0 +1,104 @@
+package gh
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+func graphEdgeNames(edges []GraphEdge) []string {
+	names := make([]string, len(edges))
+	for i, edge := range edges {
+		names[i] = edge.GetFromName() + " -> " + edge.GetToName()
+	}
+	return names
+}
+
+func assertGraphEdges(t *testing.T, edges []GraphEdge, want []string) {
+	t.Helper()
+	got := graphEdgeNames(edges)
+	if len(got) != len(want) {
+		t.Fatalf("edges = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("edge[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuildWorkflowJobGraph(t *testing.T) {
+	files := []*WorkflowFile{
+		{Path: ".github/workflows/ci.yml", Content: []byte(`jobs:
+  build:
+    runs-on: ubuntu-latest
+  lint:
+    runs-on: ubuntu-latest
+  test:
+    needs: build
+  deploy:
+    needs: [build, test]
+`)},
+		{Path: "action.yml", Content: []byte("runs:\n  using: node20\n")},
+	}
+	edges, err := BuildWorkflowJobGraph(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGraphEdges(t, edges, []string{
+		".github/workflows/ci.yml -> ci.yml:build",
+		".github/workflows/ci.yml -> ci.yml:lint",
+		"ci.yml:build -> ci.yml:test",
+		"ci.yml:build -> ci.yml:deploy",
+		"ci.yml:test -> ci.yml:deploy",
+	})
+}
+
+func TestBuildWorkflowTriggerGraph(t *testing.T) {
+	app := repository.Repository{Host: "github.com", Owner: "octo", Name: "app"}
+	infra := repository.Repository{Host: "github.com", Owner: "octo", Name: "infra"}
+	files := []*WorkflowFile{
+		{Repository: app, Path: ".github/workflows/ci.yml", Content: []byte(`name: CI
+on: push
+jobs:
+  release:
+    uses: ./.github/workflows/release.yml
+`)},
+		{Repository: app, Path: ".github/workflows/release.yml", Content: []byte(`on: workflow_call
+jobs:
+  notify:
+    steps:
+      - uses: peter-evans/repository-dispatch@v3
+        with:
+          repository: octo/infra
+          event-type: deploy
+`)},
+		{Repository: app, Path: ".github/workflows/report.yml", Content: []byte(`on:
+  workflow_run:
+    workflows: [CI, Nightly]
+jobs: {}
+`)},
+		{Repository: infra, Path: ".github/workflows/deploy.yml", Content: []byte(`on:
+  repository_dispatch:
+    types: [deploy]
+jobs:
+  shared:
+    uses: octo/templates/.github/workflows/deploy.yml@v1
+`)},
+	}
+	edges, err := BuildWorkflowTriggerGraph(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGraphEdges(t, edges, []string{
+		"octo/app:.github/workflows/ci.yml -> octo/app:.github/workflows/release.yml",
+		"octo/app:.github/workflows/release.yml -> octo/infra:repository_dispatch(deploy)",
+		"octo/app:.github/workflows/ci.yml -> octo/app:.github/workflows/report.yml",
+		"octo/app:Nightly -> octo/app:.github/workflows/report.yml",
+		"octo/infra:repository_dispatch(deploy) -> octo/infra:.github/workflows/deploy.yml",
+		"octo/infra:.github/workflows/deploy.yml -> octo/templates:.github/workflows/deploy.yml",
+	})
+	if got := edges[0].GetToURL(); got != "https://github.com/octo/app/blob/HEAD/.github/workflows/release.yml" {
+		t.Errorf("GetToURL() = %q", got)
+	}
+}
@@ -0,0 +1,283 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// Severity of a workflow lint finding, using the SARIF result levels
+const (
+	WorkflowLintSeverityError   = "error"
+	WorkflowLintSeverityWarning = "warning"
+	WorkflowLintSeverityNote    = "note"
+)
+
+// Workflow lint rule IDs
+const (
+	WorkflowLintRulePullRequestTargetCheckout = "pull-request-target-checkout"
+	WorkflowLintRuleExpressionInjection       = "expression-injection"
+	WorkflowLintRuleMissingPermissions        = "missing-permissions"
+	WorkflowLintRuleWriteAllPermissions       = "write-all-permissions"
+	WorkflowLintRuleUnpinnedAction            = "unpinned-action"
+	WorkflowLintRuleSecretsInherit            = "secrets-inherit"
+	WorkflowLintRuleSelfHostedRunner          = "self-hosted-runner"
+)
+
+// WorkflowLintRule describes a workflow lint rule
+type WorkflowLintRule struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// WorkflowLintRules lists all workflow lint rules
+var WorkflowLintRules = []WorkflowLintRule{
+	{ID: WorkflowLintRulePullRequestTargetCheckout, Severity: WorkflowLintSeverityError, Description: "pull_request_target workflows must not check out the pull request head"},
+	{ID: WorkflowLintRuleExpressionInjection, Severity: WorkflowLintSeverityError, Description: "github.event values must not be interpolated into run scripts; pass them through env instead"},
+	{ID: WorkflowLintRuleMissingPermissions, Severity: WorkflowLintSeverityWarning, Description: "workflows should declare top-level permissions"},
+	{ID: WorkflowLintRuleWriteAllPermissions, Severity: WorkflowLintSeverityError, Description: "write-all permissions grant every scope to the GITHUB_TOKEN"},
+	{ID: WorkflowLintRuleUnpinnedAction, Severity: WorkflowLintSeverityWarning, Description: "third-party actions should be pinned to a full commit SHA"},
+	{ID: WorkflowLintRuleSecretsInherit, Severity: WorkflowLintSeverityWarning, Description: "secrets: inherit passes all secrets to an external reusable workflow"},
+	{ID: WorkflowLintRuleSelfHostedRunner, Severity: WorkflowLintSeverityWarning, Description: "self-hosted runners on public repositories can run untrusted code"},
+}
+
+// GetWorkflowLintRule returns the rule with the given ID, or nil if it does not exist
+func GetWorkflowLintRule(id string) *WorkflowLintRule {
+	for i := range WorkflowLintRules {
+		if WorkflowLintRules[i].ID == id {
+			return &WorkflowLintRules[i]
+		}
+	}
+	return nil
+}
+
+// WorkflowLintFinding is a single rule violation in a workflow or action file
+type WorkflowLintFinding struct {
+	RuleID     string `json:"rule_id"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Repository string `json:"repository,omitempty"`
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column,omitempty"`
+	JobID      string `json:"job_id,omitempty"`
+}
+
+// WorkflowLintOptions holds the context the rules are evaluated in
+type WorkflowLintOptions struct {
+	Owner         string   // owner of the scanned repository; actions and workflows of this owner are not third-party
+	Public        bool     // true if the scanned repository is public
+	TrustedOwners []string // owners whose actions may be referenced without a SHA pin; defaults to actions and github
+	DisableRules  []string // rule IDs to skip
+}
+
+func (o *WorkflowLintOptions) trustedOwners() []string {
+	if len(o.TrustedOwners) > 0 {
+		return o.TrustedOwners
+	}
+	return []string{"actions", "github"}
+}
+
+func (o *WorkflowLintOptions) isThirdParty(action parser.ActionReference) bool {
+	if !action.IsRemote() {
+		return false
+	}
+	if o.Owner != "" && strings.EqualFold(action.Owner, o.Owner) {
+		return false
+	}
+	return true
+}
+
+// untrustedExpressionPattern matches expressions that read attacker-controllable event data
+var untrustedExpressionPattern = regexp.MustCompile(`\$\{\{[^}]*\b(github\.event\.[A-Za-z0-9_.\-\[\]'"*]+|github\.head_ref)[^}]*\}\}`)
+
+// checkoutHeadRefPattern matches checkout refs that point at the pull request head
+var checkoutHeadRefPattern = regexp.MustCompile(`github\.event\.pull_request\.head\.|github\.head_ref|refs/pull/`)
+
+// workflowLinter collects findings for a single file
+type workflowLinter struct {
+	file     *WorkflowFile
+	lines    []string
+	opts     *WorkflowLintOptions
+	findings []*WorkflowLintFinding
+}
+
+func (l *workflowLinter) report(ruleID string, pos parser.Position, jobID string, format string, args ...any) {
+	if slices.Contains(l.opts.DisableRules, ruleID) {
+		return
+	}
+	rule := GetWorkflowLintRule(ruleID)
+	finding := &WorkflowLintFinding{
+		RuleID:   ruleID,
+		Severity: rule.Severity,
+		Message:  fmt.Sprintf(format, args...),
+		Path:     l.file.Path,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		JobID:    jobID,
+	}
+	if l.file.LocalPath == "" && l.file.Repository.Name != "" {
+		finding.Repository = parser.GetRepositoryFullName(l.file.Repository)
+	}
+	l.findings = append(l.findings, finding)
+}
+
+func (l *workflowLinter) lineOf(start parser.Position, needle string) parser.Position {
+	return lineContaining(l.lines, start, needle)
+}
+
+// LintWorkflowFile runs the workflow lint rules on a single workflow or action metadata file
+func LintWorkflowFile(file *WorkflowFile, opts *WorkflowLintOptions) ([]*WorkflowLintFinding, error) {
+	if opts == nil {
+		opts = &WorkflowLintOptions{}
+	}
+	l := &workflowLinter{
+		file:  file,
+		lines: strings.Split(string(file.Content), "\n"),
+		opts:  opts,
+	}
+	if file.IsActionMetadata() {
+		action, err := parser.ParseActionMetadata(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lint %s: %w", file.Path, err)
+		}
+		if action.Runs != nil {
+			l.lintSteps("", action.Runs.Steps)
+		}
+		return l.findings, nil
+	}
+
+	wf, err := parser.ParseWorkflow(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lint %s: %w", file.Path, err)
+	}
+	l.lintWorkflow(wf)
+	return l.findings, nil
+}
+
+func (l *workflowLinter) lintWorkflow(wf *parser.Workflow) {
+	isPullRequestTarget := wf.Event("pull_request_target") != nil
+
+	if wf.Permissions == nil {
+		for _, job := range wf.Jobs {
+			if job.Permissions == nil && job.Uses == "" {
+				l.report(WorkflowLintRuleMissingPermissions, wf.Pos, "", "workflow has no top-level permissions and job %q does not declare any", job.ID)
+				break
+			}
+		}
+	} else if wf.Permissions.All == "write-all" {
+		l.report(WorkflowLintRuleWriteAllPermissions, wf.Permissions.Pos, "", "workflow grants write-all permissions")
+	}
+
+	for _, job := range wf.Jobs {
+		if job.Permissions != nil && job.Permissions.All == "write-all" {
+			l.report(WorkflowLintRuleWriteAllPermissions, job.Permissions.Pos, job.ID, "job %q grants write-all permissions", job.ID)
+		}
+		if l.opts.Public && job.RunsOn != nil && slices.Contains(job.RunsOn.Labels, "self-hosted") {
+			l.report(WorkflowLintRuleSelfHostedRunner, job.RunsOn.Pos, job.ID, "job %q runs on a self-hosted runner in a public repository", job.ID)
+		}
+		if job.Uses != "" {
+			action := parser.ParseActionReference(job.Uses)
+			if job.Secrets != nil && job.Secrets.Inherit && l.opts.isThirdParty(action) {
+				l.report(WorkflowLintRuleSecretsInherit, job.Secrets.Pos, job.ID, "job %q passes all secrets to external workflow %s", job.ID, action.Name())
+			}
+			l.lintUses(job.ID, action, job.UsesPos)
+		}
+		if isPullRequestTarget {
+			for _, step := range job.Steps {
+				if !isCheckoutAction(step.Uses) {
+					continue
+				}
+				if ref := step.With["ref"]; checkoutHeadRefPattern.MatchString(ref) {
+					l.report(WorkflowLintRulePullRequestTargetCheckout, step.UsesPos, job.ID, "job %q checks out the pull request head (%s) in a pull_request_target workflow", job.ID, ref)
+				}
+			}
+		}
+		l.lintSteps(job.ID, job.Steps)
+	}
+}
+
+func (l *workflowLinter) lintSteps(jobID string, steps []*parser.WorkflowStep) {
+	for _, step := range steps {
+		if step.Uses != "" {
+			l.lintUses(jobID, parser.ParseActionReference(step.Uses), step.UsesPos)
+		}
+		if step.Run == "" {
+			continue
+		}
+		for _, m := range untrustedExpressionPattern.FindAllStringSubmatch(step.Run, -1) {
+			l.report(WorkflowLintRuleExpressionInjection, l.lineOf(step.RunPos, m[0]), jobID, "%s is interpolated into a run script", m[1])
+		}
+	}
+}
+
+func (l *workflowLinter) lintUses(jobID string, action parser.ActionReference, pos parser.Position) {
+	if !l.opts.isThirdParty(action) || action.IsPinned() {
+		return
+	}
+	if slices.ContainsFunc(l.opts.trustedOwners(), func(owner string) bool { return strings.EqualFold(owner, action.Owner) }) {
+		return
+	}
+	l.report(WorkflowLintRuleUnpinnedAction, pos, jobID, "%s is not pinned to a commit SHA", action.Raw)
+}
+
+// isCheckoutAction returns true if uses refers to actions/checkout
+func isCheckoutAction(uses string) bool {
+	action := parser.ParseActionReference(uses)
+	return strings.EqualFold(action.Owner, "actions") && strings.EqualFold(action.Repo, "checkout")
+}
+
+// LintWorkflowFiles runs the workflow lint rules on every file. Files that cannot be parsed are
+// reported as errors after all other files are linted.
+func LintWorkflowFiles(files []*WorkflowFile, opts *WorkflowLintOptions) ([]*WorkflowLintFinding, error) {
+	var findings []*WorkflowLintFinding
+	var errs []string
+	for _, file := range files {
+		fileFindings, err := LintWorkflowFile(file, opts)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		findings = append(findings, fileFindings...)
+	}
+	if len(errs) > 0 {
+		return findings, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return findings, nil
+}
+
+// LintLocalWorkflows lints the workflow and action files below dir without any API access
+func LintLocalWorkflows(dir string, opts *WorkflowLintOptions) ([]*WorkflowLintFinding, error) {
+	files, err := LoadLocalWorkflowFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return LintWorkflowFiles(files, opts)
+}
+
+// LintRepositoryWorkflows lints the workflow and action files of a repository.
+// The repository owner and visibility are filled into a copy of opts.
+func LintRepositoryWorkflows(ctx context.Context, g *GitHubClient, repo repository.Repository, ref *string, opts *WorkflowLintOptions) ([]*WorkflowLintFinding, error) {
+	r, err := GetRepository(ctx, g, repo)
+	if err != nil {
+		return nil, err
+	}
+	lintOpts := WorkflowLintOptions{}
+	if opts != nil {
+		lintOpts = *opts
+	}
+	lintOpts.Owner = repo.Owner
+	lintOpts.Public = !r.GetPrivate()
+
+	files, err := GetRepositoryWorkflowFiles(ctx, g, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return LintWorkflowFiles(files, &lintOpts)
+}
@@ -0,0 +1,159 @@
+package gh
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+)
+
+// WorkflowLintToolName is the tool name reported in the SARIF output of the workflow linter
+const WorkflowLintToolName = "gh-workflow-lint"
+
+const sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog is the root object of a SARIF 2.1.0 log, limited to the fields the workflow linter emits
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single analysis run in a SARIF log
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the analysis tool of a SARIF run
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the tool component and its rules
+type SARIFDriver struct {
+	Name  string      `json:"name"`
+	Rules []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a reporting rule
+type SARIFRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     SARIFMessage           `json:"shortDescription"`
+	DefaultConfiguration SARIFRuleConfiguration `json:"defaultConfiguration"`
+}
+
+// SARIFRuleConfiguration holds the default level of a rule
+type SARIFRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a single result of a SARIF run
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation is the location of a result
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a file and region
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation is the file of a location, relative to the repository root
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the line and column of a location
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// NewWorkflowLintSARIF converts workflow lint findings to a SARIF log
+func NewWorkflowLintSARIF(findings []*WorkflowLintFinding) *SARIFLog {
+	rules := make([]SARIFRule, 0, len(WorkflowLintRules))
+	for _, rule := range WorkflowLintRules {
+		rules = append(rules, SARIFRule{
+			ID:                   rule.ID,
+			ShortDescription:     SARIFMessage{Text: rule.Description},
+			DefaultConfiguration: SARIFRuleConfiguration{Level: rule.Severity},
+		})
+	}
+	results := make([]SARIFResult, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, SARIFResult{
+			RuleID:  f.RuleID,
+			Level:   f.Severity,
+			Message: SARIFMessage{Text: f.Message},
+			Locations: []SARIFLocation{
+				{
+					PhysicalLocation: SARIFPhysicalLocation{
+						ArtifactLocation: SARIFArtifactLocation{URI: f.Path},
+						Region:           SARIFRegion{StartLine: max(f.Line, 1), StartColumn: f.Column},
+					},
+				},
+			},
+		})
+	}
+	return &SARIFLog{
+		Schema:  sarifSchemaURI,
+		Version: "2.1.0",
+		Runs: []SARIFRun{
+			{
+				Tool:    SARIFTool{Driver: SARIFDriver{Name: WorkflowLintToolName, Rules: rules}},
+				Results: results,
+			},
+		},
+	}
+}
+
+// EncodeSARIF marshals a SARIF log and returns it gzip-compressed and base64-encoded,
+// as expected by UploadSARIF
+func EncodeSARIF(log *SARIFLog) (string, error) {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return "", fmt.Errorf("failed to compress SARIF: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress SARIF: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// UploadWorkflowLintSARIF uploads workflow lint findings to code scanning for the given commit and ref
+func UploadWorkflowLintSARIF(ctx context.Context, g *GitHubClient, repo repository.Repository, findings []*WorkflowLintFinding, commitSHA, ref string) (*github.SarifID, error) {
+	sarif, err := EncodeSARIF(NewWorkflowLintSARIF(findings))
+	if err != nil {
+		return nil, err
+	}
+	return UploadSARIF(ctx, g, repo, &UploadSARIFOptions{
+		CommitSHA: commitSHA,
+		Ref:       ref,
+		SARIF:     sarif,
+		ToolName:  WorkflowLintToolName,
+	})
+}
@@ -0,0 +1,148 @@
+package gh
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const workflowLintTestContent = `on:
+  pull_request_target:
+permissions: write-all
+jobs:
+  build:
+    runs-on: [self-hosted, linux]
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          ref: ${{ github.event.pull_request.head.sha }}
+      - uses: octo/setup@v1
+      - uses: octo/cache@11bd71901bbe5b1630ceea73d27597364c9af683
+      - uses: my-org/tool@v1
+      - run: |
+          echo start
+          echo "${{ github.event.pull_request.title }}"
+  call:
+    uses: octo/workflows/.github/workflows/release.yml@v1
+    secrets: inherit
+`
+
+func lintFindingsByRule(findings []*WorkflowLintFinding) map[string][]*WorkflowLintFinding {
+	byRule := map[string][]*WorkflowLintFinding{}
+	for _, f := range findings {
+		byRule[f.RuleID] = append(byRule[f.RuleID], f)
+	}
+	return byRule
+}
+
+func TestLintWorkflowFile(t *testing.T) {
+	file := &WorkflowFile{Path: ".github/workflows/ci.yml", Content: []byte(workflowLintTestContent)}
+	findings, err := LintWorkflowFile(file, &WorkflowLintOptions{Owner: "my-org", Public: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRule := lintFindingsByRule(findings)
+
+	want := map[string][]int{
+		WorkflowLintRulePullRequestTargetCheckout: {8},
+		WorkflowLintRuleExpressionInjection:       {16},
+		WorkflowLintRuleWriteAllPermissions:       {3},
+		WorkflowLintRuleUnpinnedAction:            {11, 18},
+		WorkflowLintRuleSecretsInherit:            {19},
+		WorkflowLintRuleSelfHostedRunner:          {6},
+	}
+	for rule, lines := range want {
+		got := byRule[rule]
+		if len(got) != len(lines) {
+			t.Errorf("%s: got %d findings, want %d", rule, len(got), len(lines))
+			continue
+		}
+		for i, line := range lines {
+			if got[i].Line != line {
+				t.Errorf("%s[%d]: line = %d, want %d", rule, i, got[i].Line, line)
+			}
+		}
+	}
+	if len(byRule[WorkflowLintRuleMissingPermissions]) != 0 {
+		t.Errorf("unexpected missing-permissions finding")
+	}
+	if got := byRule[WorkflowLintRuleExpressionInjection][0].Column; got != 17 {
+		t.Errorf("expression-injection column = %d, want 17", got)
+	}
+
+	findings, err = LintWorkflowFile(file, &WorkflowLintOptions{Owner: "my-org", DisableRules: []string{WorkflowLintRuleUnpinnedAction}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRule = lintFindingsByRule(findings)
+	if len(byRule[WorkflowLintRuleUnpinnedAction]) != 0 || len(byRule[WorkflowLintRuleSelfHostedRunner]) != 0 {
+		t.Errorf("disabled or private-only rules reported: %v", byRule)
+	}
+}
+
+func TestLintLocalWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	workflowPath := filepath.Join(dir, ".github", "workflows", "ci.yml")
+	if err := os.MkdirAll(filepath.Dir(workflowPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(workflowPath, []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: make test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "action.yml"), []byte("runs:\n  using: composite\n  steps:\n    - run: echo ${{ github.event.issue.title }}\n      shell: bash\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := LintLocalWorkflows(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byRule := lintFindingsByRule(findings)
+	if got := byRule[WorkflowLintRuleMissingPermissions]; len(got) != 1 || got[0].Path != ".github/workflows/ci.yml" {
+		t.Errorf("missing-permissions findings = %v", got)
+	}
+	if got := byRule[WorkflowLintRuleExpressionInjection]; len(got) != 1 || got[0].Path != "action.yml" || got[0].Line != 4 {
+		t.Errorf("expression-injection findings = %v", got)
+	}
+}
+
+func TestEncodeWorkflowLintSARIF(t *testing.T) {
+	findings := []*WorkflowLintFinding{
+		{RuleID: WorkflowLintRuleWriteAllPermissions, Severity: WorkflowLintSeverityError, Message: "write-all", Path: ".github/workflows/ci.yml", Line: 3, Column: 14},
+	}
+	encoded, err := EncodeSARIF(NewWorkflowLintSARIF(findings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log SARIFLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(WorkflowLintRules) {
+		t.Errorf("rules = %d, want %d", len(run.Tool.Driver.Rules), len(WorkflowLintRules))
+	}
+	if len(run.Results) != 1 || run.Results[0].Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("unexpected results: %+v", run.Results)
+	}
+}
@@ -0,0 +1,105 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+)
+
+// WorkflowRunLogDiff is the difference between the logs of a run and a base run of the same workflow
+type WorkflowRunLogDiff struct {
+	Base *github.WorkflowRun `json:"base"`
+	Head *github.WorkflowRun `json:"head"`
+	*actions.LogDiff
+}
+
+// WorkflowRunLogDiffOptions configures DiffWorkflowRunLogs
+type WorkflowRunLogDiffOptions struct {
+	BaseRunID int64 // run to compare against; 0 means the last successful run before the head run
+	Log       *WorkflowRunLogOptions
+	Diff      *actions.LogDiffOptions
+}
+
+// FindLastSuccessfulWorkflowRun returns the most recent successful run of the same workflow and branch
+// created before run, or nil if there is none
+func FindLastSuccessfulWorkflowRun(ctx context.Context, g *GitHubClient, repo repository.Repository, run *github.WorkflowRun) (*github.WorkflowRun, error) {
+	return findLastSuccessfulWorkflowRun(run, func(options *ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+		return GetLatestWorkflowRunByID(ctx, g, repo, run.GetWorkflowID(), options)
+	})
+}
+
+// findLastSuccessfulWorkflowRun asks getLatest for the newest successful run of the branch created before run.
+// Runs are listed newest first, so a single run is enough.
+func findLastSuccessfulWorkflowRun(run *github.WorkflowRun, getLatest func(options *ListWorkflowRunsOptions) (*github.WorkflowRun, error)) (*github.WorkflowRun, error) {
+	base, err := getLatest(&ListWorkflowRunsOptions{
+		Branch:  run.GetHeadBranch(),
+		Status:  "success",
+		Created: "<" + run.GetCreatedAt().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list successful runs of workflow %d: %w", run.GetWorkflowID(), err)
+	}
+	if base.GetID() == run.GetID() {
+		return nil, nil
+	}
+	return base, nil
+}
+
+// DiffWorkflowRunLogs compares the logs of a run, typically a failing one, with those of
+// options.BaseRunID or, by default, the last successful run of the same workflow and branch.
+// Logs are normalized with options.Diff.Normalizers before they are compared.
+func DiffWorkflowRunLogs(ctx context.Context, g *GitHubClient, repo repository.Repository, runID int64, options *WorkflowRunLogDiffOptions) (*WorkflowRunLogDiff, error) {
+	if options == nil {
+		options = &WorkflowRunLogDiffOptions{}
+	}
+	head, err := GetWorkflowRunByID(ctx, g, repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run %d: %w", runID, err)
+	}
+	var base *github.WorkflowRun
+	if options.BaseRunID != 0 {
+		base, err = GetWorkflowRunByID(ctx, g, repo, options.BaseRunID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow run %d: %w", options.BaseRunID, err)
+		}
+	} else {
+		base, err = FindLastSuccessfulWorkflowRun(ctx, g, repo, head)
+		if err != nil {
+			return nil, err
+		}
+		if base == nil {
+			return nil, fmt.Errorf("no successful run of workflow %q on branch %q before run %d", head.GetName(), head.GetHeadBranch(), runID)
+		}
+	}
+
+	// The base run is always compared at its latest attempt; the head attempt follows options.Log
+	baseLogs, err := DownloadWorkflowRunLogArchive(ctx, g, repo, base.GetID(), baseLogOptions(options.Log))
+	if err != nil {
+		return nil, err
+	}
+	defer baseLogs.Close() // nolint
+	headLogs, err := DownloadWorkflowRunLogArchive(ctx, g, repo, head.GetID(), options.Log)
+	if err != nil {
+		return nil, err
+	}
+	defer headLogs.Close() // nolint
+
+	diff, err := actions.DiffLogArchives(baseLogs, headLogs, options.Diff)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkflowRunLogDiff{Base: base, Head: head, LogDiff: diff}, nil
+}
+
+func baseLogOptions(options *WorkflowRunLogOptions) *WorkflowRunLogOptions {
+	if options == nil {
+		return nil
+	}
+	o := *options
+	o.Attempt = 0
+	return &o
+}
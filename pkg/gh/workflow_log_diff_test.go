@@ -0,0 +1,68 @@
+package gh
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func TestFindLastSuccessfulWorkflowRun(t *testing.T) {
+	head := &github.WorkflowRun{
+		ID:         github.Ptr(int64(10)),
+		WorkflowID: github.Ptr(int64(7)),
+		HeadBranch: github.Ptr("main"),
+		CreatedAt:  &github.Timestamp{Time: time.Date(2024, 1, 5, 12, 0, 0, 0, time.FixedZone("JST", 9*60*60))},
+	}
+	var got *ListWorkflowRunsOptions
+	base, err := findLastSuccessfulWorkflowRun(head, func(options *ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+		got = options
+		return &github.WorkflowRun{ID: github.Ptr(int64(3))}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.GetID() != 3 {
+		t.Errorf("base = %d, want 3", base.GetID())
+	}
+	if got.Branch != "main" || got.Status != "success" || got.Created != "<2024-01-05T03:00:00Z" {
+		t.Errorf("options = %+v", got)
+	}
+
+	base, err = findLastSuccessfulWorkflowRun(head, func(*ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+		return nil, nil
+	})
+	if err != nil || base != nil {
+		t.Errorf("no run: base = %v, err = %v", base, err)
+	}
+
+	// The head run itself is never its own base
+	base, err = findLastSuccessfulWorkflowRun(head, func(*ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+		return head, nil
+	})
+	if err != nil || base != nil {
+		t.Errorf("head run: base = %v, err = %v", base, err)
+	}
+
+	failure := errors.New("boom")
+	if _, err := findLastSuccessfulWorkflowRun(head, func(*ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+		return nil, failure
+	}); !errors.Is(err, failure) {
+		t.Errorf("err = %v, want %v", err, failure)
+	}
+}
+
+func TestBaseLogOptions(t *testing.T) {
+	if baseLogOptions(nil) != nil {
+		t.Error("baseLogOptions(nil) should be nil")
+	}
+	head := &WorkflowRunLogOptions{Attempt: 2, TempDir: "/tmp/logs", MaxSize: 1024}
+	base := baseLogOptions(head)
+	if base.Attempt != 0 || base.TempDir != "/tmp/logs" || base.MaxSize != 1024 {
+		t.Errorf("baseLogOptions() = %+v", base)
+	}
+	if head.Attempt != 2 {
+		t.Errorf("head options were modified: %+v", head)
+	}
+}
@@ -0,0 +1,191 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+)
+
+// Default poll intervals of TailWorkflowRunLogs
+const (
+	DefaultLogTailInterval    = 5 * time.Second
+	DefaultLogTailMaxInterval = time.Minute
+)
+
+// WorkflowLogTailOptions configures TailWorkflowRunLogs
+type WorkflowLogTailOptions struct {
+	Attempt     int           // run attempt; 0 means the latest attempt
+	Interval    time.Duration // poll interval after progress; defaults to DefaultLogTailInterval
+	MaxInterval time.Duration // the interval doubles while nothing changes, up to this; defaults to DefaultLogTailMaxInterval
+}
+
+// WorkflowLogTailEvent is the log of a step that has completed
+type WorkflowLogTailEvent struct {
+	Job     *github.WorkflowJob
+	Step    *actions.StepLog // named like the steps of a JobLog
+	Content []byte
+}
+
+// TailWorkflowRunLogs follows the jobs of a workflow run and calls fn with the log of each step, in step
+// order, once the step has completed and its log can be downloaded. Job logs are usually not available
+// until the job completes, so the steps of a running job are reported in batches. It returns the
+// completed run after every step has been reported, or the error of ctx when it is cancelled.
+func TailWorkflowRunLogs(ctx context.Context, g *GitHubClient, repo repository.Repository, runID int64, options *WorkflowLogTailOptions, fn func(*WorkflowLogTailEvent) error) (*github.WorkflowRun, error) {
+	if options == nil {
+		options = &WorkflowLogTailOptions{}
+	}
+	t := &workflowLogTailer{
+		options: options,
+		getRun: func(ctx context.Context) (*github.WorkflowRun, error) {
+			return GetWorkflowRunByID(ctx, g, repo, runID)
+		},
+		listJobs: func(ctx context.Context) ([]*github.WorkflowJob, error) {
+			if options.Attempt > 0 {
+				return ListWorkflowJobsAttempt(ctx, g, repo, runID, int64(options.Attempt))
+			}
+			return ListWorkflowJobs(ctx, g, repo, runID, nil)
+		},
+		fetchLog: func(ctx context.Context, jobID int64) ([]byte, error) {
+			return GetWorkflowJobLogsContent(ctx, g, repo, jobID, logMaxRedirects)
+		},
+	}
+	return t.tail(ctx, fn)
+}
+
+// workflowLogTailer holds the polling state of TailWorkflowRunLogs
+type workflowLogTailer struct {
+	options  *WorkflowLogTailOptions
+	getRun   func(ctx context.Context) (*github.WorkflowRun, error)
+	listJobs func(ctx context.Context) ([]*github.WorkflowJob, error)
+	fetchLog func(ctx context.Context, jobID int64) ([]byte, error)
+	reported map[int64]int // job ID -> number of the last reported step
+	done     map[int64]bool
+}
+
+func (t *workflowLogTailer) tail(ctx context.Context, fn func(*WorkflowLogTailEvent) error) (*github.WorkflowRun, error) {
+	interval := t.options.Interval
+	if interval <= 0 {
+		interval = DefaultLogTailInterval
+	}
+	maxInterval := t.options.MaxInterval
+	if maxInterval < interval {
+		maxInterval = max(DefaultLogTailMaxInterval, interval)
+	}
+	t.reported = map[int64]int{}
+	t.done = map[int64]bool{}
+
+	wait := interval
+	for {
+		// Read the run status before the jobs so that a run reported as completed has no unseen jobs
+		run, err := t.getRun(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow run: %w", err)
+		}
+		jobs, err := t.listJobs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow jobs: %w", err)
+		}
+		progressed, err := t.poll(ctx, jobs, run.GetStatus() == "completed", fn)
+		if err != nil {
+			return nil, err
+		}
+		if run.GetStatus() == "completed" && t.allDone(jobs) {
+			return run, nil
+		}
+
+		if progressed {
+			wait = interval
+		} else {
+			wait = min(wait*2, maxInterval)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// poll reports the newly completed steps of the jobs and returns true if any step was reported.
+// Once the run has completed, a completed job without a log (e.g. a skipped job) will never get one,
+// so its steps are reported with empty content.
+func (t *workflowLogTailer) poll(ctx context.Context, jobs []*github.WorkflowJob, runCompleted bool, fn func(*WorkflowLogTailEvent) error) (bool, error) {
+	progressed := false
+	for _, job := range jobs {
+		if t.done[job.GetID()] {
+			continue
+		}
+		jobCompleted := job.GetStatus() == "completed"
+		if !jobCompleted && lastCompletedStep(job) <= t.reported[job.GetID()] {
+			continue
+		}
+		content, err := t.fetchLog(ctx, job.GetID())
+		if err != nil {
+			if !IsHTTPNotFound(err) {
+				return progressed, fmt.Errorf("failed to download logs of job %q: %w", job.GetName(), err)
+			}
+			if !runCompleted || !jobCompleted {
+				// The log is not available yet
+				continue
+			}
+			content = nil
+		}
+		jobLog := actions.NewJobLog(job.GetName(), job.Steps, func() ([]byte, error) { return content, nil })
+		for _, step := range jobLog.ListSteps() {
+			if step.StepNumber <= t.reported[job.GetID()] {
+				continue
+			}
+			if !jobCompleted && !isStepCompleted(job, step.StepNumber) {
+				break
+			}
+			stepContent, err := step.ReadContent()
+			if err != nil {
+				return progressed, err
+			}
+			if err := fn(&WorkflowLogTailEvent{Job: job, Step: step, Content: stepContent}); err != nil {
+				return progressed, err
+			}
+			t.reported[job.GetID()] = step.StepNumber
+			progressed = true
+		}
+		if jobCompleted {
+			t.done[job.GetID()] = true
+			progressed = true
+		}
+	}
+	return progressed, nil
+}
+
+func (t *workflowLogTailer) allDone(jobs []*github.WorkflowJob) bool {
+	for _, job := range jobs {
+		if !t.done[job.GetID()] {
+			return false
+		}
+	}
+	return true
+}
+
+// lastCompletedStep returns the number of the last step of the job's leading run of completed steps
+func lastCompletedStep(job *github.WorkflowJob) int {
+	last := 0
+	for _, step := range job.Steps {
+		if step.GetStatus() != "completed" {
+			break
+		}
+		last = int(step.GetNumber())
+	}
+	return last
+}
+
+func isStepCompleted(job *github.WorkflowJob, number int) bool {
+	for _, step := range job.Steps {
+		if int(step.GetNumber()) == number {
+			return step.GetStatus() == "completed"
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func tailTestJob(status string, stepStatuses ...string) *github.WorkflowJob {
+	job := &github.WorkflowJob{ID: github.Ptr(int64(1)), Name: github.Ptr("build"), Status: github.Ptr(status)}
+	for i, s := range stepStatuses {
+		started := time.Date(2024, 1, 2, 3, 4, 5+i*10, 0, time.UTC)
+		job.Steps = append(job.Steps, &github.TaskStep{
+			Number:    github.Ptr(int64(i + 1)),
+			Name:      github.Ptr([]string{"Set up job", "Build", "Test"}[i]),
+			Status:    github.Ptr(s),
+			StartedAt: &github.Timestamp{Time: started},
+		})
+	}
+	return job
+}
+
+const tailTestLog = "2024-01-02T03:04:05.0000000Z setup\n" +
+	"2024-01-02T03:04:15.0000000Z build\n" +
+	"2024-01-02T03:04:25.0000000Z test\n"
+
+func TestWorkflowLogTailer(t *testing.T) {
+	type poll struct {
+		runStatus string
+		job       *github.WorkflowJob
+		logReady  bool
+	}
+	polls := []poll{
+		{"in_progress", tailTestJob("in_progress", "completed", "in_progress", "queued"), false},
+		{"in_progress", tailTestJob("in_progress", "completed", "completed", "in_progress"), true},
+		{"in_progress", tailTestJob("in_progress", "completed", "completed", "in_progress"), true},
+		{"completed", tailTestJob("completed", "completed", "completed", "completed"), true},
+	}
+	i := -1
+	fetches := 0
+	tailer := &workflowLogTailer{
+		options: &WorkflowLogTailOptions{Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond},
+		getRun: func(ctx context.Context) (*github.WorkflowRun, error) {
+			i = min(i+1, len(polls)-1)
+			return &github.WorkflowRun{Status: github.Ptr(polls[i].runStatus)}, nil
+		},
+		listJobs: func(ctx context.Context) ([]*github.WorkflowJob, error) {
+			return []*github.WorkflowJob{polls[i].job}, nil
+		},
+		fetchLog: func(ctx context.Context, jobID int64) ([]byte, error) {
+			fetches++
+			if !polls[i].logReady {
+				return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+			}
+			return []byte(tailTestLog), nil
+		},
+	}
+
+	var got []string
+	run, err := tailer.tail(context.Background(), func(e *WorkflowLogTailEvent) error {
+		got = append(got, e.Step.StepName+": "+string(e.Content))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.GetStatus() != "completed" {
+		t.Errorf("run status = %q", run.GetStatus())
+	}
+	want := []string{
+		"Set up job: 2024-01-02T03:04:05.0000000Z setup\n",
+		"Build: 2024-01-02T03:04:15.0000000Z build\n",
+		"Test: 2024-01-02T03:04:25.0000000Z test\n",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+	for j := range want {
+		if got[j] != want[j] {
+			t.Errorf("event[%d] = %q, want %q", j, got[j], want[j])
+		}
+	}
+	// The third poll has no newly completed step and must not download the log again
+	if fetches != 3 {
+		t.Errorf("fetches = %d, want 3", fetches)
+	}
+}
+
+func TestWorkflowLogTailer_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tailer := &workflowLogTailer{
+		options: &WorkflowLogTailOptions{Interval: time.Hour},
+		getRun: func(ctx context.Context) (*github.WorkflowRun, error) {
+			return &github.WorkflowRun{Status: github.Ptr("queued")}, nil
+		},
+		listJobs: func(ctx context.Context) ([]*github.WorkflowJob, error) {
+			cancel()
+			return nil, nil
+		},
+		fetchLog: func(ctx context.Context, jobID int64) ([]byte, error) {
+			return nil, errors.New("unexpected fetch")
+		},
+	}
+	if _, err := tailer.tail(ctx, func(*WorkflowLogTailEvent) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestWorkflowLogTailer_CallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	tailer := &workflowLogTailer{
+		options: &WorkflowLogTailOptions{Interval: time.Millisecond},
+		getRun: func(ctx context.Context) (*github.WorkflowRun, error) {
+			return &github.WorkflowRun{Status: github.Ptr("completed")}, nil
+		},
+		listJobs: func(ctx context.Context) ([]*github.WorkflowJob, error) {
+			return []*github.WorkflowJob{tailTestJob("completed", "completed")}, nil
+		},
+		fetchLog: func(ctx context.Context, jobID int64) ([]byte, error) {
+			return []byte(tailTestLog), nil
+		},
+	}
+	if _, err := tailer.tail(context.Background(), func(*WorkflowLogTailEvent) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("err = %v, want %v", err, stop)
+	}
+}
+
+func TestWorkflowLogTailer_SkippedJob(t *testing.T) {
+	fetches := 0
+	tailer := &workflowLogTailer{
+		options: &WorkflowLogTailOptions{Interval: time.Millisecond},
+		getRun: func(ctx context.Context) (*github.WorkflowRun, error) {
+			return &github.WorkflowRun{Status: github.Ptr("completed")}, nil
+		},
+		listJobs: func(ctx context.Context) ([]*github.WorkflowJob, error) {
+			job := tailTestJob("completed", "completed")
+			job.Conclusion = github.Ptr("skipped")
+			return []*github.WorkflowJob{job}, nil
+		},
+		fetchLog: func(ctx context.Context, jobID int64) ([]byte, error) {
+			fetches++
+			return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		},
+	}
+
+	var got []string
+	run, err := tailer.tail(context.Background(), func(e *WorkflowLogTailEvent) error {
+		got = append(got, e.Step.StepName+": "+string(e.Content))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.GetStatus() != "completed" {
+		t.Errorf("run status = %q", run.GetStatus())
+	}
+	if len(got) != 1 || got[0] != "Set up job: " {
+		t.Errorf("events = %q, want [\"Set up job: \"]", got)
+	}
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1", fetches)
+	}
+}
@@ -0,0 +1,79 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+	"github.com/srz-zumix/go-gh-extension/pkg/ioutil"
+)
+
+// logMaxRedirects is the number of redirects followed when resolving a log download URL
+const logMaxRedirects = 3
+
+// WorkflowRunLogOptions configures how the logs of a workflow run are downloaded
+type WorkflowRunLogOptions struct {
+	Attempt int    // run attempt; 0 means the latest attempt
+	TempDir string // directory for the temporary archive file; defaults to os.TempDir()
+	MaxSize int64  // maximum archive size in bytes; 0 means no limit
+}
+
+// DownloadWorkflowRunLogArchive downloads the log archive of a workflow run into a temporary file
+// and opens it without loading it into memory. The caller must Close the archive to remove the file.
+// Archives larger than options.MaxSize fail with ioutil.ErrArchiveTooLarge; use GetWorkflowRunJobLogs
+// to download the logs job by job instead.
+func DownloadWorkflowRunLogArchive(ctx context.Context, g *GitHubClient, repo repository.Repository, runID int64, options *WorkflowRunLogOptions) (*actions.WorkflowRunLogArchive, error) {
+	if options == nil {
+		options = &WorkflowRunLogOptions{}
+	}
+	logURL, err := GetWorkflowRunAttemptLogsURL(ctx, g, repo, runID, options.Attempt, logMaxRedirects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log URL of workflow run %d: %w", runID, err)
+	}
+	zipArchive, err := ioutil.DownloadZipArchiveToFile(ctx, logURL, &ioutil.ZipArchiveOptions{TempDir: options.TempDir, MaxSize: options.MaxSize})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download logs of workflow run %d: %w", runID, err)
+	}
+	archive, err := actions.NewWorkflowRunLogArchiveWithCloser(ctx, zipArchive.Reader, zipArchive)
+	if err != nil {
+		_ = zipArchive.Close()
+		return nil, err
+	}
+	return archive, nil
+}
+
+// GetWorkflowJobLog returns the log of a workflow job, split into step logs by the job's steps.
+// The log is downloaded with GetWorkflowJobLogsContent when the first step log is read, using ctx.
+func GetWorkflowJobLog(ctx context.Context, g *GitHubClient, repo repository.Repository, job *github.WorkflowJob) *actions.JobLog {
+	jobID := job.GetID()
+	return actions.NewJobLog(job.GetName(), job.Steps, func() ([]byte, error) {
+		content, err := GetWorkflowJobLogsContent(ctx, g, repo, jobID, logMaxRedirects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download logs of job %d: %w", jobID, err)
+		}
+		return content, nil
+	})
+}
+
+// GetWorkflowRunJobLogs returns the logs of a workflow run as an archive whose job logs are downloaded
+// one job at a time, on first read, instead of as a single zip archive.
+// Only options.Attempt is used.
+func GetWorkflowRunJobLogs(ctx context.Context, g *GitHubClient, repo repository.Repository, runID int64, options *WorkflowRunLogOptions) (*actions.WorkflowRunLogArchive, error) {
+	var jobs []*github.WorkflowJob
+	var err error
+	if options != nil && options.Attempt > 0 {
+		jobs, err = ListWorkflowJobsAttempt(ctx, g, repo, runID, int64(options.Attempt))
+	} else {
+		jobs, err = ListWorkflowJobs(ctx, g, repo, runID, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs of workflow run %d: %w", runID, err)
+	}
+	jobLogs := make([]*actions.JobLog, 0, len(jobs))
+	for _, job := range jobs {
+		jobLogs = append(jobLogs, GetWorkflowJobLog(ctx, g, repo, job))
+	}
+	return actions.NewWorkflowRunLogArchiveFromJobs(jobLogs...), nil
+}
@@ -56,6 +56,11 @@ func ListWorkflowRunsByID(ctx context.Context, g *GitHubClient, repo repository.
 	return g.ListWorkflowRunsByID(ctx, repo.Owner, repo.Name, workflowID, toGitHubListWorkflowRunsOptions(options))
 }
 
+// GetLatestWorkflowRunByID returns the most recently created run of a workflow that matches options, or nil if there is none.
+func GetLatestWorkflowRunByID(ctx context.Context, g *GitHubClient, repo repository.Repository, workflowID int64, options *ListWorkflowRunsOptions) (*github.WorkflowRun, error) {
+	return g.GetLatestWorkflowRunByID(ctx, repo.Owner, repo.Name, workflowID, toGitHubListWorkflowRunsOptions(options))
+}
+
 // GetWorkflowByFileName retrieves a workflow definition by its file name.
 func GetWorkflowByFileName(ctx context.Context, g *GitHubClient, repo repository.Repository, workflowFileName string) (*github.Workflow, error) {
 	return g.GetWorkflowByFileName(ctx, repo.Owner, repo.Name, workflowFileName)
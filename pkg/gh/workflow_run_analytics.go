@@ -0,0 +1,438 @@
+package gh
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+)
+
+const (
+	workflowConclusionSuccess   = "success"
+	workflowConclusionFailure   = "failure"
+	workflowConclusionCancelled = "cancelled"
+	workflowConclusionTimedOut  = "timed_out"
+	workflowJobFilterAll        = "all"
+	workflowRunStatusCompleted  = "completed"
+)
+
+// WorkflowRunData is a completed workflow run together with the optional details used for aggregation
+type WorkflowRunData struct {
+	Run *github.WorkflowRun
+	// Jobs holds the jobs of every attempt of the run. Nil when jobs were not fetched.
+	Jobs []*github.WorkflowJob
+	// Usage holds the billable time of the run. Nil when usage was not fetched.
+	Usage *github.WorkflowRunUsage
+}
+
+// WorkflowRunStats holds aggregated statistics for a workflow, or for a job of a workflow when Job is set.
+// Durations are in seconds. Rates are fractions of Runs in the range 0..1.
+type WorkflowRunStats struct {
+	Workflow    string  `json:"workflow"`
+	Job         string  `json:"job,omitempty"`
+	Runs        int     `json:"runs"`
+	Success     int     `json:"success"`
+	Failure     int     `json:"failure"`
+	Cancelled   int     `json:"cancelled"`
+	Reruns      int     `json:"reruns"`
+	Flaky       int     `json:"flaky,omitempty"`
+	SuccessRate float64 `json:"success_rate"`
+	FailureRate float64 `json:"failure_rate"`
+	CancelRate  float64 `json:"cancel_rate"`
+	RerunRate   float64 `json:"rerun_rate"`
+	DurationP50 float64 `json:"duration_p50_seconds"`
+	DurationP95 float64 `json:"duration_p95_seconds"`
+	QueueP50    float64 `json:"queue_p50_seconds"`
+	QueueP95    float64 `json:"queue_p95_seconds"`
+
+	durations []float64
+	queues    []float64
+}
+
+// WorkflowFlakyJob is a job that failed and then passed on a later attempt for the same commit
+type WorkflowFlakyJob struct {
+	Workflow string  `json:"workflow"`
+	Job      string  `json:"job"`
+	HeadSHA  string  `json:"head_sha"`
+	Failures int     `json:"failures"` // failed attempts before the first success
+	RunIDs   []int64 `json:"run_ids"`
+}
+
+// WorkflowBillableStats holds billable time of a workflow on one runner OS
+type WorkflowBillableStats struct {
+	Workflow string  `json:"workflow"`
+	OS       string  `json:"os"` // e.g. "UBUNTU", "MACOS", "WINDOWS"
+	Runs     int     `json:"runs"`
+	Jobs     int     `json:"jobs"`
+	Minutes  float64 `json:"minutes"`
+}
+
+// WorkflowRunAnalytics is the result of aggregating the workflow runs of a repository
+type WorkflowRunAnalytics struct {
+	Repository string                   `json:"repository,omitempty"`
+	Since      *time.Time               `json:"since,omitempty"`
+	Until      *time.Time               `json:"until,omitempty"`
+	Workflows  []*WorkflowRunStats      `json:"workflows"`
+	Jobs       []*WorkflowRunStats      `json:"jobs,omitempty"`
+	FlakyJobs  []*WorkflowFlakyJob      `json:"flaky_jobs,omitempty"`
+	Billable   []*WorkflowBillableStats `json:"billable,omitempty"`
+}
+
+// WorkflowRunAnalyticsOptions configures which runs are collected and aggregated
+type WorkflowRunAnalyticsOptions struct {
+	// Workflow limits the runs to a workflow file name (e.g. "ci.yml"). Empty means all workflows.
+	Workflow string
+	Branch   string
+	Event    string
+	// Since and Until filter runs by created_at. Zero values are ignored.
+	Since time.Time
+	Until time.Time
+	// IncludeJobs fetches the jobs of every run for job statistics and flaky job detection.
+	IncludeJobs bool
+	// IncludeUsage fetches the billable time of every run.
+	IncludeUsage bool
+}
+
+// createdFilter returns the created search qualifier for the date range, or "" for no range
+func (o *WorkflowRunAnalyticsOptions) createdFilter() string {
+	if o.Since.IsZero() && o.Until.IsZero() {
+		return ""
+	}
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return "*"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return format(o.Since) + ".." + format(o.Until)
+}
+
+// ListWorkflowRunData lists the completed workflow runs of a repository and fetches the jobs and usage
+// of each run as requested by options. Runs whose details cannot be fetched are kept without them.
+func ListWorkflowRunData(ctx context.Context, g *GitHubClient, repo repository.Repository, options *WorkflowRunAnalyticsOptions) ([]*WorkflowRunData, error) {
+	if options == nil {
+		options = &WorkflowRunAnalyticsOptions{}
+	}
+	listOptions := &ListWorkflowRunsOptions{
+		Branch:  options.Branch,
+		Event:   options.Event,
+		Status:  workflowRunStatusCompleted,
+		Created: options.createdFilter(),
+	}
+	var runs []*github.WorkflowRun
+	var err error
+	if options.Workflow != "" {
+		runs, err = ListWorkflowRunsByFileName(ctx, g, repo, options.Workflow, listOptions)
+	} else {
+		runs, err = ListRepositoryWorkflowRuns(ctx, g, repo, listOptions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]*WorkflowRunData, 0, len(runs))
+	filter := workflowJobFilterAll
+	for _, run := range runs {
+		d := &WorkflowRunData{Run: run}
+		if options.IncludeJobs {
+			jobs, err := ListWorkflowJobs(ctx, g, repo, run.GetID(), &filter)
+			if err != nil {
+				logger.Warn("Failed to list workflow jobs", "run", run.GetID(), "error", err)
+			} else {
+				d.Jobs = jobs
+			}
+		}
+		if options.IncludeUsage {
+			usage, err := GetWorkflowRunUsageByID(ctx, g, repo, run.GetID())
+			if err != nil {
+				logger.Warn("Failed to get workflow run usage", "run", run.GetID(), "error", err)
+			} else {
+				d.Usage = usage
+			}
+		}
+		data = append(data, d)
+	}
+	return data, nil
+}
+
+// GetWorkflowRunAnalytics collects and aggregates the workflow runs of a repository
+func GetWorkflowRunAnalytics(ctx context.Context, g *GitHubClient, repo repository.Repository, options *WorkflowRunAnalyticsOptions) (*WorkflowRunAnalytics, error) {
+	data, err := ListWorkflowRunData(ctx, g, repo, options)
+	if err != nil {
+		return nil, err
+	}
+	analytics := AggregateWorkflowRuns(data, options)
+	analytics.Repository = repo.Owner + "/" + repo.Name
+	return analytics, nil
+}
+
+// AggregateWorkflowRuns aggregates workflow runs into per-workflow and per-job statistics.
+// Run durations are measured from run_started_at to updated_at of the latest attempt, and run queue
+// time from created_at to run_started_at of first attempts only, since re-runs keep the original created_at.
+// Job durations and queue times use the job timestamps of every attempt.
+// A job is flaky when, for the same workflow and head SHA, a failed attempt is followed by a successful one.
+func AggregateWorkflowRuns(data []*WorkflowRunData, options *WorkflowRunAnalyticsOptions) *WorkflowRunAnalytics {
+	if options == nil {
+		options = &WorkflowRunAnalyticsOptions{}
+	}
+	analytics := &WorkflowRunAnalytics{}
+	if !options.Since.IsZero() {
+		analytics.Since = &options.Since
+	}
+	if !options.Until.IsZero() {
+		analytics.Until = &options.Until
+	}
+
+	workflows := map[string]*WorkflowRunStats{}
+	jobs := map[string]*WorkflowRunStats{}
+	billable := map[string]*WorkflowBillableStats{}
+	var executions []*workflowJobExecution
+
+	for _, d := range data {
+		if d == nil || d.Run == nil {
+			continue
+		}
+		run := d.Run
+		createdAt := run.GetCreatedAt().Time
+		if !options.Since.IsZero() && createdAt.Before(options.Since) {
+			continue
+		}
+		if !options.Until.IsZero() && createdAt.After(options.Until) {
+			continue
+		}
+		name := workflowRunName(run)
+
+		stats := workflowRunStatsGroup(workflows, name, "")
+		stats.count(run.GetConclusion(), run.GetRunAttempt() > 1)
+		startedAt := run.GetRunStartedAt().Time
+		stats.durations = appendDuration(stats.durations, startedAt, run.GetUpdatedAt().Time)
+		if run.GetRunAttempt() <= 1 {
+			stats.queues = appendDuration(stats.queues, createdAt, startedAt)
+		}
+
+		for _, job := range d.Jobs {
+			if job == nil {
+				continue
+			}
+			jobStats := workflowRunStatsGroup(jobs, name, job.GetName())
+			jobStats.count(job.GetConclusion(), job.GetRunAttempt() > 1)
+			jobStats.durations = appendDuration(jobStats.durations, job.GetStartedAt().Time, job.GetCompletedAt().Time)
+			jobStats.queues = appendDuration(jobStats.queues, job.GetCreatedAt().Time, job.GetStartedAt().Time)
+			executions = append(executions, &workflowJobExecution{
+				workflow: name,
+				job:      job.GetName(),
+				headSHA:  firstNonEmpty(job.GetHeadSHA(), run.GetHeadSHA()),
+				runID:    run.GetID(),
+				attempt:  job.GetRunAttempt(),
+				at:       job.GetStartedAt().Time,
+				result:   job.GetConclusion(),
+			})
+		}
+
+		if d.Usage != nil && d.Usage.Billable != nil {
+			for os, bill := range *d.Usage.Billable {
+				if bill == nil {
+					continue
+				}
+				key := name + "\x00" + os
+				b, ok := billable[key]
+				if !ok {
+					b = &WorkflowBillableStats{Workflow: name, OS: os}
+					billable[key] = b
+				}
+				b.Runs++
+				b.Jobs += bill.GetJobs()
+				b.Minutes += float64(bill.GetTotalMS()) / float64(time.Minute/time.Millisecond)
+			}
+		}
+	}
+
+	analytics.FlakyJobs = detectFlakyJobs(executions)
+	for _, flaky := range analytics.FlakyJobs {
+		if stats, ok := jobs[flaky.Workflow+"\x00"+flaky.Job]; ok {
+			stats.Flaky++
+		}
+	}
+	analytics.Workflows = finalizeWorkflowRunStats(workflows)
+	analytics.Jobs = finalizeWorkflowRunStats(jobs)
+
+	analytics.Billable = make([]*WorkflowBillableStats, 0, len(billable))
+	for _, b := range billable {
+		analytics.Billable = append(analytics.Billable, b)
+	}
+	sort.Slice(analytics.Billable, func(i, j int) bool {
+		a, b := analytics.Billable[i], analytics.Billable[j]
+		if a.Workflow != b.Workflow {
+			return a.Workflow < b.Workflow
+		}
+		return a.OS < b.OS
+	})
+	return analytics
+}
+
+// BillableMinutesByOS sums the billable minutes of all workflows per runner OS
+func (a *WorkflowRunAnalytics) BillableMinutesByOS() map[string]float64 {
+	minutes := map[string]float64{}
+	for _, b := range a.Billable {
+		minutes[b.OS] += b.Minutes
+	}
+	return minutes
+}
+
+func workflowRunName(run *github.WorkflowRun) string {
+	return firstNonEmpty(run.GetName(), run.GetPath(), ruleSuiteStatsUnknownValue)
+}
+
+func workflowRunStatsGroup(groups map[string]*WorkflowRunStats, workflow string, job string) *WorkflowRunStats {
+	key := workflow + "\x00" + job
+	if stats, ok := groups[key]; ok {
+		return stats
+	}
+	stats := &WorkflowRunStats{Workflow: workflow, Job: job}
+	groups[key] = stats
+	return stats
+}
+
+func (s *WorkflowRunStats) count(conclusion string, rerun bool) {
+	s.Runs++
+	switch conclusion {
+	case workflowConclusionSuccess:
+		s.Success++
+	case workflowConclusionFailure, workflowConclusionTimedOut:
+		s.Failure++
+	case workflowConclusionCancelled:
+		s.Cancelled++
+	}
+	if rerun {
+		s.Reruns++
+	}
+}
+
+func finalizeWorkflowRunStats(groups map[string]*WorkflowRunStats) []*WorkflowRunStats {
+	result := make([]*WorkflowRunStats, 0, len(groups))
+	for _, s := range groups {
+		if s.Runs > 0 {
+			runs := float64(s.Runs)
+			s.SuccessRate = float64(s.Success) / runs
+			s.FailureRate = float64(s.Failure) / runs
+			s.CancelRate = float64(s.Cancelled) / runs
+			s.RerunRate = float64(s.Reruns) / runs
+		}
+		s.DurationP50 = percentile(s.durations, 50)
+		s.DurationP95 = percentile(s.durations, 95)
+		s.QueueP50 = percentile(s.queues, 50)
+		s.QueueP95 = percentile(s.queues, 95)
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Workflow != result[j].Workflow {
+			return result[i].Workflow < result[j].Workflow
+		}
+		return result[i].Job < result[j].Job
+	})
+	return result
+}
+
+// appendDuration appends the seconds between start and end, ignoring missing or negative spans
+func appendDuration(samples []float64, start time.Time, end time.Time) []float64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return samples
+	}
+	return append(samples, end.Sub(start).Seconds())
+}
+
+// percentile returns the p-th percentile of samples using the nearest-rank method, or 0 for no samples
+func percentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+type workflowJobExecution struct {
+	workflow string
+	job      string
+	headSHA  string
+	runID    int64
+	attempt  int64
+	at       time.Time
+	result   string
+}
+
+// detectFlakyJobs finds jobs that failed and later succeeded for the same workflow and head SHA,
+// across re-run attempts of one run as well as separate runs of the same commit
+func detectFlakyJobs(executions []*workflowJobExecution) []*WorkflowFlakyJob {
+	groups := map[string][]*workflowJobExecution{}
+	var keys []string
+	for _, e := range executions {
+		if e.headSHA == "" {
+			continue
+		}
+		key := strings.Join([]string{e.workflow, e.job, e.headSHA}, "\x00")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], e)
+	}
+	sort.Strings(keys)
+
+	var flaky []*WorkflowFlakyJob
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			if !group[i].at.Equal(group[j].at) {
+				return group[i].at.Before(group[j].at)
+			}
+			return group[i].attempt < group[j].attempt
+		})
+		failures := 0
+		var runIDs []int64
+		for _, e := range group {
+			if e.result == workflowConclusionFailure || e.result == workflowConclusionTimedOut {
+				failures++
+				runIDs = appendUniqueRunID(runIDs, e.runID)
+				continue
+			}
+			if e.result == workflowConclusionSuccess && failures > 0 {
+				flaky = append(flaky, &WorkflowFlakyJob{
+					Workflow: e.workflow,
+					Job:      e.job,
+					HeadSHA:  e.headSHA,
+					Failures: failures,
+					RunIDs:   appendUniqueRunID(runIDs, e.runID),
+				})
+				break
+			}
+		}
+	}
+	return flaky
+}
+
+func appendUniqueRunID(ids []int64, id int64) []int64 {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
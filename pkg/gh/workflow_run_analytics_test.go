@@ -0,0 +1,145 @@
+package gh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v90/github"
+)
+
+func testTimestamp(t time.Time) *github.Timestamp {
+	return &github.Timestamp{Time: t}
+}
+
+func newTestWorkflowRun(id int64, name string, sha string, attempt int, conclusion string, created time.Time, queue, duration time.Duration) *github.WorkflowRun {
+	started := created.Add(queue)
+	return &github.WorkflowRun{
+		ID:           github.Ptr(id),
+		Name:         github.Ptr(name),
+		HeadSHA:      github.Ptr(sha),
+		RunAttempt:   github.Ptr(attempt),
+		Conclusion:   github.Ptr(conclusion),
+		CreatedAt:    testTimestamp(created),
+		RunStartedAt: testTimestamp(started),
+		UpdatedAt:    testTimestamp(started.Add(duration)),
+	}
+}
+
+func newTestWorkflowJob(name string, attempt int64, conclusion string, created time.Time, queue, duration time.Duration) *github.WorkflowJob {
+	started := created.Add(queue)
+	return &github.WorkflowJob{
+		Name:        github.Ptr(name),
+		RunAttempt:  github.Ptr(attempt),
+		Conclusion:  github.Ptr(conclusion),
+		CreatedAt:   testTimestamp(created),
+		StartedAt:   testTimestamp(started),
+		CompletedAt: testTimestamp(started.Add(duration)),
+	}
+}
+
+func TestAggregateWorkflowRuns(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	data := []*WorkflowRunData{
+		{
+			// attempt 1 of test failed, attempt 2 passed
+			Run: newTestWorkflowRun(1, "CI", "aaa", 2, "success", base, 10*time.Second, 2*time.Minute),
+			Jobs: []*github.WorkflowJob{
+				newTestWorkflowJob("test", 1, "failure", base, 5*time.Second, time.Minute),
+				newTestWorkflowJob("test", 2, "success", base.Add(time.Hour), 15*time.Second, time.Minute),
+			},
+			Usage: &github.WorkflowRunUsage{Billable: &github.WorkflowRunBillMap{
+				"UBUNTU": {TotalMS: github.Ptr(int64(120000)), Jobs: github.Ptr(2)},
+			}},
+		},
+		{
+			Run: newTestWorkflowRun(2, "CI", "bbb", 1, "failure", base.Add(2*time.Hour), 20*time.Second, 4*time.Minute),
+			Jobs: []*github.WorkflowJob{
+				newTestWorkflowJob("test", 1, "failure", base.Add(2*time.Hour), 5*time.Second, 3*time.Minute),
+			},
+			Usage: &github.WorkflowRunUsage{Billable: &github.WorkflowRunBillMap{
+				"UBUNTU":  {TotalMS: github.Ptr(int64(60000)), Jobs: github.Ptr(1)},
+				"WINDOWS": {TotalMS: github.Ptr(int64(30000)), Jobs: github.Ptr(1)},
+			}},
+		},
+		{
+			Run: newTestWorkflowRun(3, "CI", "ccc", 1, "cancelled", base.Add(3*time.Hour), 30*time.Second, time.Minute),
+		},
+		{
+			// outside the range
+			Run: newTestWorkflowRun(4, "CI", "ddd", 1, "success", base.Add(-24*time.Hour), 0, time.Minute),
+		},
+		{
+			Run: newTestWorkflowRun(5, "Release", "aaa", 1, "success", base, 0, 10*time.Minute),
+		},
+	}
+
+	analytics := AggregateWorkflowRuns(data, &WorkflowRunAnalyticsOptions{Since: base})
+	if len(analytics.Workflows) != 2 {
+		t.Fatalf("expected 2 workflows, got %d", len(analytics.Workflows))
+	}
+	ci := analytics.Workflows[0]
+	if ci.Workflow != "CI" || ci.Runs != 3 || ci.Success != 1 || ci.Failure != 1 || ci.Cancelled != 1 || ci.Reruns != 1 {
+		t.Errorf("unexpected CI stats: %+v", ci)
+	}
+	if ci.DurationP50 != 120 || ci.DurationP95 != 240 {
+		t.Errorf("duration p50/p95 = %v/%v, want 120/240", ci.DurationP50, ci.DurationP95)
+	}
+	// the re-run attempt is excluded from the run queue time
+	if ci.QueueP50 != 20 || ci.QueueP95 != 30 {
+		t.Errorf("queue p50/p95 = %v/%v, want 20/30", ci.QueueP50, ci.QueueP95)
+	}
+
+	if len(analytics.Jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(analytics.Jobs))
+	}
+	job := analytics.Jobs[0]
+	if job.Runs != 3 || job.Failure != 2 || job.Reruns != 1 || job.Flaky != 1 {
+		t.Errorf("unexpected job stats: %+v", job)
+	}
+	if job.QueueP95 != 15 {
+		t.Errorf("job queue p95 = %v, want 15", job.QueueP95)
+	}
+
+	if len(analytics.FlakyJobs) != 1 {
+		t.Fatalf("expected 1 flaky job, got %d", len(analytics.FlakyJobs))
+	}
+	flaky := analytics.FlakyJobs[0]
+	if flaky.HeadSHA != "aaa" || flaky.Failures != 1 || len(flaky.RunIDs) != 1 || flaky.RunIDs[0] != 1 {
+		t.Errorf("unexpected flaky job: %+v", flaky)
+	}
+
+	minutes := analytics.BillableMinutesByOS()
+	if minutes["UBUNTU"] != 3 || minutes["WINDOWS"] != 0.5 {
+		t.Errorf("billable minutes = %v", minutes)
+	}
+}
+
+func TestDetectFlakyJobs_AcrossRuns(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	flaky := detectFlakyJobs([]*workflowJobExecution{
+		{workflow: "CI", job: "e2e", headSHA: "abc", runID: 2, attempt: 1, at: base.Add(time.Hour), result: "success"},
+		{workflow: "CI", job: "e2e", headSHA: "abc", runID: 1, attempt: 1, at: base, result: "timed_out"},
+		{workflow: "CI", job: "unit", headSHA: "abc", runID: 1, attempt: 1, at: base, result: "success"},
+		{workflow: "CI", job: "lint", headSHA: "abc", runID: 1, attempt: 1, at: base, result: "failure"},
+	})
+	if len(flaky) != 1 || flaky[0].Job != "e2e" || len(flaky[0].RunIDs) != 2 {
+		t.Errorf("unexpected flaky jobs: %+v", flaky)
+	}
+}
+
+func TestWorkflowRunAnalyticsOptions_CreatedFilter(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		opts WorkflowRunAnalyticsOptions
+		want string
+	}{
+		{WorkflowRunAnalyticsOptions{}, ""},
+		{WorkflowRunAnalyticsOptions{Since: since}, "2026-03-01T00:00:00Z..*"},
+		{WorkflowRunAnalyticsOptions{Since: since, Until: since.Add(24 * time.Hour)}, "2026-03-01T00:00:00Z..2026-03-02T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.createdFilter(); got != tt.want {
+			t.Errorf("createdFilter() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/ioutil"
+	"github.com/srz-zumix/go-gh-extension/pkg/logger"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Workflow run cleanup actions
+const (
+	WorkflowRunCleanupDeleteRun      = "delete-run"
+	WorkflowRunCleanupDeleteLogs     = "delete-logs"
+	WorkflowRunCleanupDeleteArtifact = "delete-artifact"
+)
+
+// WorkflowRunRetentionPolicy decides which completed workflow runs, logs and artifacts are deleted.
+// Zero values disable the corresponding rule. Runs that are not completed are never touched.
+type WorkflowRunRetentionPolicy struct {
+	// Workflows limits the policy to workflows with these names or file paths; empty means all workflows
+	Workflows []string `json:"workflows,omitempty" yaml:"workflows,omitempty"`
+	// KeepLast protects the newest N runs of each workflow and branch from every rule
+	KeepLast int `json:"keep_last,omitempty" yaml:"keep_last,omitempty"`
+	// DeleteOlderThanDays deletes runs created more than this many days ago
+	DeleteOlderThanDays int `json:"delete_older_than_days,omitempty" yaml:"delete_older_than_days,omitempty"`
+	// DeleteConclusions deletes runs with these conclusions, e.g. cancelled or skipped, regardless of age
+	DeleteConclusions []string `json:"delete_conclusions,omitempty" yaml:"delete_conclusions,omitempty"`
+	// DeleteLogsOlderThanDays deletes the logs of runs created more than this many days ago and keeps the runs
+	DeleteLogsOlderThanDays int `json:"delete_logs_older_than_days,omitempty" yaml:"delete_logs_older_than_days,omitempty"`
+	// DeleteArtifactsLargerThan deletes artifacts of more than this many bytes from runs that are kept
+	DeleteArtifactsLargerThan int64 `json:"delete_artifacts_larger_than,omitempty" yaml:"delete_artifacts_larger_than,omitempty"`
+	// MeasureLogs requests the size of every log archive that would be deleted, for a better estimate.
+	// Without it log sizes are unknown and count as 0.
+	MeasureLogs bool `json:"measure_logs,omitempty" yaml:"measure_logs,omitempty"`
+}
+
+// ParseWorkflowRunRetentionPolicy parses a YAML or JSON retention policy
+func ParseWorkflowRunRetentionPolicy(data []byte) (*WorkflowRunRetentionPolicy, error) {
+	var policy WorkflowRunRetentionPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse retention policy: %w", err)
+	}
+	if policy.KeepLast < 0 || policy.DeleteOlderThanDays < 0 || policy.DeleteLogsOlderThanDays < 0 || policy.DeleteArtifactsLargerThan < 0 {
+		return nil, fmt.Errorf("invalid retention policy: values must not be negative")
+	}
+	return &policy, nil
+}
+
+// LoadWorkflowRunRetentionPolicy reads a retention policy from a YAML or JSON file
+func LoadWorkflowRunRetentionPolicy(path string) (*WorkflowRunRetentionPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retention policy %q: %w", path, err)
+	}
+	return ParseWorkflowRunRetentionPolicy(data)
+}
+
+// WorkflowRunCleanupItem is a single deletion in a cleanup plan
+type WorkflowRunCleanupItem struct {
+	Repository   string    `json:"repository"`
+	Action       string    `json:"action"`
+	Workflow     string    `json:"workflow"`
+	Branch       string    `json:"branch,omitempty"`
+	RunID        int64     `json:"run_id"`
+	RunNumber    int       `json:"run_number"`
+	Conclusion   string    `json:"conclusion,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	ArtifactID   int64     `json:"artifact_id,omitempty"`
+	ArtifactName string    `json:"artifact_name,omitempty"`
+	Reason       string    `json:"reason"`
+	// Bytes is the estimated storage reclaimed: artifact sizes plus, when measured, the log archive size
+	Bytes int64  `json:"bytes"`
+	Done  bool   `json:"done,omitempty"`
+	Error string `json:"error,omitempty"`
+
+	repo repository.Repository
+}
+
+// WorkflowRunCleanupPlan is the list of deletions a retention policy results in
+type WorkflowRunCleanupPlan struct {
+	Items []*WorkflowRunCleanupItem `json:"items"`
+}
+
+// ReclaimedBytes returns the estimated storage reclaimed by the plan
+func (p *WorkflowRunCleanupPlan) ReclaimedBytes() int64 {
+	var total int64
+	for _, item := range p.Items {
+		total += item.Bytes
+	}
+	return total
+}
+
+// Count returns the number of items with the given action
+func (p *WorkflowRunCleanupPlan) Count(action string) int {
+	count := 0
+	for _, item := range p.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// PlanWorkflowRunCleanup lists the runs and the artifacts of each repository and applies the policy to
+// them at now. Runs are listed without a status filter, which would cap the results at 1,000 runs;
+// runs that are not completed are skipped by the policy. Repositories that cannot be read are logged and skipped.
+func PlanWorkflowRunCleanup(ctx context.Context, g *GitHubClient, repos []repository.Repository, policy *WorkflowRunRetentionPolicy, now time.Time) (*WorkflowRunCleanupPlan, error) {
+	if policy == nil {
+		return nil, fmt.Errorf("retention policy is required")
+	}
+	plan := &WorkflowRunCleanupPlan{}
+	for _, repo := range repos {
+		runs, err := ListRepositoryWorkflowRuns(ctx, g, repo, nil)
+		if err != nil {
+			logger.Warn("Failed to list workflow runs", "repository", parser.GetRepositoryFullName(repo), "error", err)
+			continue
+		}
+		artifacts, err := ListArtifacts(ctx, g, repo, nil)
+		if err != nil {
+			logger.Warn("Failed to list artifacts", "repository", parser.GetRepositoryFullName(repo), "error", err)
+			continue
+		}
+		items := PlanRepositoryWorkflowRunCleanup(repo, runs, artifacts, policy, now)
+		if policy.MeasureLogs {
+			for _, item := range items {
+				if item.Action == WorkflowRunCleanupDeleteArtifact {
+					continue
+				}
+				size, err := measureWorkflowRunLogs(ctx, g, repo, item.RunID)
+				if err != nil {
+					logger.Warn("Failed to measure workflow run logs", "run", item.RunID, "error", err)
+					continue
+				}
+				item.Bytes += size
+			}
+		}
+		plan.Items = append(plan.Items, items...)
+	}
+	return plan, nil
+}
+
+func measureWorkflowRunLogs(ctx context.Context, g *GitHubClient, repo repository.Repository, runID int64) (int64, error) {
+	logURL, err := GetWorkflowRunLogsURL(ctx, g, repo, runID, logMaxRedirects)
+	if err != nil {
+		if IsHTTPNotFound(err) {
+			// Logs already deleted or expired
+			return 0, nil
+		}
+		return 0, err
+	}
+	return ioutil.GetContentLength(ctx, logURL)
+}
+
+// PlanRepositoryWorkflowRunCleanup applies the policy to the runs and artifacts of one repository.
+// Runs are grouped by workflow and branch and ranked newest first for KeepLast. A run that is deleted
+// takes its logs and artifacts with it, so they are not listed separately; expired artifacts are ignored.
+func PlanRepositoryWorkflowRunCleanup(repo repository.Repository, runs []*github.WorkflowRun, artifacts []*github.Artifact, policy *WorkflowRunRetentionPolicy, now time.Time) []*WorkflowRunCleanupItem {
+	artifactsByRun := map[int64][]*github.Artifact{}
+	for _, artifact := range artifacts {
+		if artifact.GetExpired() {
+			continue
+		}
+		runID := artifact.GetWorkflowRun().GetID()
+		artifactsByRun[runID] = append(artifactsByRun[runID], artifact)
+	}
+
+	type groupKey struct {
+		workflowID int64
+		branch     string
+	}
+	groups := map[groupKey][]*github.WorkflowRun{}
+	var keys []groupKey
+	for _, run := range runs {
+		if run.GetStatus() != "completed" || !policy.matchesWorkflow(run) {
+			continue
+		}
+		key := groupKey{run.GetWorkflowID(), run.GetHeadBranch()}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], run)
+	}
+
+	fullName := parser.GetRepositoryFullName(repo)
+	var items []*WorkflowRunCleanupItem
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].GetCreatedAt().After(group[j].GetCreatedAt().Time)
+		})
+		for rank, run := range group {
+			if rank < policy.KeepLast {
+				continue
+			}
+			newItem := func(action, reason string) *WorkflowRunCleanupItem {
+				return &WorkflowRunCleanupItem{
+					Repository: fullName,
+					Action:     action,
+					Workflow:   run.GetName(),
+					Branch:     run.GetHeadBranch(),
+					RunID:      run.GetID(),
+					RunNumber:  run.GetRunNumber(),
+					Conclusion: run.GetConclusion(),
+					CreatedAt:  run.GetCreatedAt().Time,
+					Reason:     reason,
+					repo:       repo,
+				}
+			}
+			age := now.Sub(run.GetCreatedAt().Time)
+
+			if reason := policy.deleteRunReason(run, age); reason != "" {
+				item := newItem(WorkflowRunCleanupDeleteRun, reason)
+				for _, artifact := range artifactsByRun[run.GetID()] {
+					item.Bytes += artifact.GetSizeInBytes()
+				}
+				items = append(items, item)
+				continue
+			}
+			if policy.DeleteLogsOlderThanDays > 0 && age > days(policy.DeleteLogsOlderThanDays) {
+				items = append(items, newItem(WorkflowRunCleanupDeleteLogs, fmt.Sprintf("older than %d days", policy.DeleteLogsOlderThanDays)))
+			}
+			if policy.DeleteArtifactsLargerThan > 0 {
+				for _, artifact := range artifactsByRun[run.GetID()] {
+					if artifact.GetSizeInBytes() <= policy.DeleteArtifactsLargerThan {
+						continue
+					}
+					item := newItem(WorkflowRunCleanupDeleteArtifact, fmt.Sprintf("larger than %d bytes", policy.DeleteArtifactsLargerThan))
+					item.ArtifactID = artifact.GetID()
+					item.ArtifactName = artifact.GetName()
+					item.Bytes = artifact.GetSizeInBytes()
+					items = append(items, item)
+				}
+			}
+		}
+	}
+	return items
+}
+
+func (p *WorkflowRunRetentionPolicy) matchesWorkflow(run *github.WorkflowRun) bool {
+	if len(p.Workflows) == 0 {
+		return true
+	}
+	for _, workflow := range p.Workflows {
+		if workflow == run.GetName() || workflow == run.GetPath() || workflow == path.Base(run.GetPath()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *WorkflowRunRetentionPolicy) deleteRunReason(run *github.WorkflowRun, age time.Duration) string {
+	if slices.Contains(p.DeleteConclusions, run.GetConclusion()) {
+		return "conclusion " + run.GetConclusion()
+	}
+	if p.DeleteOlderThanDays > 0 && age > days(p.DeleteOlderThanDays) {
+		return fmt.Sprintf("older than %d days", p.DeleteOlderThanDays)
+	}
+	return ""
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// ApplyWorkflowRunCleanup executes the plan, which may also have been read back from JSON.
+// Items that are already gone count as done. Failed items are marked with their error and the
+// remaining items are still executed; the returned error joins all failures.
+func ApplyWorkflowRunCleanup(ctx context.Context, g *GitHubClient, plan *WorkflowRunCleanupPlan) error {
+	var errs []error
+	for _, item := range plan.Items {
+		if item.Done {
+			continue
+		}
+		repo := item.repo
+		if repo.Name == "" {
+			r, err := repository.Parse(item.Repository)
+			if err != nil {
+				item.Error = err.Error()
+				errs = append(errs, fmt.Errorf("invalid repository %q: %w", item.Repository, err))
+				continue
+			}
+			repo = r
+		}
+		var err error
+		switch item.Action {
+		case WorkflowRunCleanupDeleteRun:
+			err = DeleteWorkflowRun(ctx, g, repo, item.RunID)
+		case WorkflowRunCleanupDeleteLogs:
+			err = DeleteWorkflowRunLogs(ctx, g, repo, item.RunID)
+		case WorkflowRunCleanupDeleteArtifact:
+			err = DeleteArtifact(ctx, g, repo, item.ArtifactID)
+		default:
+			err = fmt.Errorf("unknown cleanup action %q", item.Action)
+		}
+		if err != nil && !IsHTTPNotFound(err) {
+			item.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s %s run %d: %w", item.Action, item.Repository, item.RunID, err))
+			continue
+		}
+		item.Done = true
+	}
+	return errors.Join(errs...)
+}
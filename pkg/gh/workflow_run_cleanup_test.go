@@ -0,0 +1,106 @@
+package gh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+)
+
+func TestPlanRepositoryWorkflowRunCleanup(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	run := func(id int64, workflowID int64, branch string, daysAgo int, conclusion string) *github.WorkflowRun {
+		return &github.WorkflowRun{
+			ID:         github.Ptr(id),
+			RunNumber:  github.Ptr(int(id)),
+			WorkflowID: github.Ptr(workflowID),
+			Name:       github.Ptr("CI"),
+			Path:       github.Ptr(".github/workflows/ci.yml"),
+			HeadBranch: github.Ptr(branch),
+			Status:     github.Ptr("completed"),
+			Conclusion: github.Ptr(conclusion),
+			CreatedAt:  &github.Timestamp{Time: now.Add(-time.Duration(daysAgo) * 24 * time.Hour)},
+		}
+	}
+	artifact := func(id, runID, size int64) *github.Artifact {
+		return &github.Artifact{ID: github.Ptr(id), Name: github.Ptr("dist"), SizeInBytes: github.Ptr(size), WorkflowRun: &github.ArtifactWorkflowRun{ID: github.Ptr(runID)}}
+	}
+	inProgress := run(99, 1, "main", 400, "")
+	inProgress.Status = github.Ptr("in_progress")
+	runs := []*github.WorkflowRun{
+		run(1, 1, "main", 1, "success"),
+		run(2, 1, "main", 100, "success"), // logs deleted
+		run(3, 1, "main", 200, "success"), // deleted: too old
+		run(4, 1, "main", 20, "cancelled"),
+		run(5, 1, "main", 40, "success"), // logs deleted, artifact too large
+		run(6, 1, "feature", 300, "success"),
+		inProgress,
+	}
+	artifacts := []*github.Artifact{
+		artifact(10, 3, 100),
+		artifact(11, 5, 5000),
+		artifact(12, 5, 10),
+		artifact(14, 5, 1000),
+		{ID: github.Ptr(int64(13)), SizeInBytes: github.Ptr(int64(9000)), Expired: github.Ptr(true), WorkflowRun: &github.ArtifactWorkflowRun{ID: github.Ptr(int64(5))}},
+	}
+	policy := &WorkflowRunRetentionPolicy{
+		KeepLast:                  1,
+		DeleteOlderThanDays:       180,
+		DeleteConclusions:         []string{"cancelled"},
+		DeleteLogsOlderThanDays:   30,
+		DeleteArtifactsLargerThan: 1000,
+	}
+	items := PlanRepositoryWorkflowRunCleanup(repository.Repository{Owner: "o", Name: "r"}, runs, artifacts, policy, now)
+
+	type want struct {
+		action string
+		runID  int64
+		bytes  int64
+	}
+	wants := []want{
+		{WorkflowRunCleanupDeleteRun, 4, 0},
+		{WorkflowRunCleanupDeleteLogs, 5, 0},
+		{WorkflowRunCleanupDeleteArtifact, 5, 5000},
+		{WorkflowRunCleanupDeleteLogs, 2, 0},
+		{WorkflowRunCleanupDeleteRun, 3, 100},
+	}
+	if len(items) != len(wants) {
+		for _, item := range items {
+			t.Logf("%+v", item)
+		}
+		t.Fatalf("len(items) = %d, want %d", len(items), len(wants))
+	}
+	for i, w := range wants {
+		if items[i].Action != w.action || items[i].RunID != w.runID || items[i].Bytes != w.bytes {
+			t.Errorf("items[%d] = %s run %d %d bytes, want %s run %d %d bytes", i, items[i].Action, items[i].RunID, items[i].Bytes, w.action, w.runID, w.bytes)
+		}
+	}
+	if items[0].Repository != "o/r" || items[0].Reason != "conclusion cancelled" {
+		t.Errorf("items[0] = %+v", items[0])
+	}
+
+	plan := &WorkflowRunCleanupPlan{Items: items}
+	if plan.ReclaimedBytes() != 5100 || plan.Count(WorkflowRunCleanupDeleteRun) != 2 {
+		t.Errorf("ReclaimedBytes() = %d, Count(delete-run) = %d", plan.ReclaimedBytes(), plan.Count(WorkflowRunCleanupDeleteRun))
+	}
+
+	// The feature branch has a single run, protected by keep_last
+	policy.Workflows = []string{"other.yml"}
+	if items := PlanRepositoryWorkflowRunCleanup(repository.Repository{Owner: "o", Name: "r"}, runs, artifacts, policy, now); len(items) != 0 {
+		t.Errorf("workflow filter: got %d items, want 0", len(items))
+	}
+}
+
+func TestParseWorkflowRunRetentionPolicy(t *testing.T) {
+	policy, err := ParseWorkflowRunRetentionPolicy([]byte("keep_last: 5\ndelete_older_than_days: 90\ndelete_conclusions: [cancelled, skipped]\nworkflows: [ci.yml]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.KeepLast != 5 || policy.DeleteOlderThanDays != 90 || len(policy.DeleteConclusions) != 2 || policy.Workflows[0] != "ci.yml" {
+		t.Errorf("policy = %+v", policy)
+	}
+	if _, err := ParseWorkflowRunRetentionPolicy([]byte("keep_last: -1\n")); err == nil {
+		t.Error("expected an error for a negative value")
+	}
+}
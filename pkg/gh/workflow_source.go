@@ -0,0 +1,116 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/srz-zumix/go-gh-extension/pkg/parser"
+)
+
+// ErrRemoteSourceDisabled is returned when a file of another repository is requested from a WorkflowSource without a client
+var ErrRemoteSourceDisabled = errors.New("remote references are not followed without a GitHub client")
+
+// WorkflowSource is where workflow dependency analysis reads workflow and action files from.
+// Files of Repository are read from FS when it is set (e.g. a local checkout via os.DirFS),
+// and through Client otherwise. Files of other repositories are always fetched through Client;
+// when Client is nil, remote references are not followed.
+type WorkflowSource struct {
+	Repository repository.Repository // repository being analyzed; may be empty for a local directory of unknown origin
+	FS         fs.FS                 // local checkout of Repository, rooted at the repository root
+	Client     *GitHubClient         // client for remote files; nil for offline analysis
+}
+
+// NewRemoteWorkflowSource returns a source that fetches all files through the GitHub API
+func NewRemoteWorkflowSource(g *GitHubClient, repo repository.Repository) *WorkflowSource {
+	return &WorkflowSource{Repository: repo, Client: g}
+}
+
+// NewLocalWorkflowSource returns a source that reads the files of repo from fsys.
+// g may be nil to analyze the checkout offline without following remote references.
+func NewLocalWorkflowSource(fsys fs.FS, repo repository.Repository, g *GitHubClient) *WorkflowSource {
+	if repo.Host == "" && repo.Owner != "" {
+		repo.Host = defaultHost
+	}
+	return &WorkflowSource{Repository: repo, FS: fsys, Client: g}
+}
+
+// Name returns a display name of the analyzed repository
+func (s *WorkflowSource) Name() string {
+	if s.Repository.Owner == "" && s.FS != nil {
+		return "local directory"
+	}
+	return parser.GetRepositoryFullName(s.Repository)
+}
+
+// withClient returns a copy of the source that fetches remote files through g
+func (s *WorkflowSource) withClient(g *GitHubClient) *WorkflowSource {
+	c := *s
+	c.Client = g
+	return &c
+}
+
+// fallback returns the fallback client to use with this source; there is none in offline mode
+func (s *WorkflowSource) fallback(fallback *GitHubClient) *GitHubClient {
+	if s.Client == nil {
+		return nil
+	}
+	return fallback
+}
+
+// isLocal returns true if files of repo are read from the local FS
+func (s *WorkflowSource) isLocal(repo repository.Repository) bool {
+	return s.FS != nil && strings.EqualFold(repo.Owner, s.Repository.Owner) && strings.EqualFold(repo.Name, s.Repository.Name)
+}
+
+// readFile returns the content of a file in repo. The ref is ignored for local files.
+func (s *WorkflowSource) readFile(ctx context.Context, repo repository.Repository, filePath string, ref *string) ([]byte, error) {
+	if s.isLocal(repo) {
+		return fs.ReadFile(s.FS, path.Clean(filePath))
+	}
+	if s.Client == nil {
+		return nil, fmt.Errorf("%s:%s: %w", parser.GetRepositoryFullName(repo), filePath, ErrRemoteSourceDisabled)
+	}
+	return GetFileContent(ctx, s.Client, repo, filePath, ref)
+}
+
+// listFiles returns the names of the regular files in a directory of repo.
+// A missing directory is not an error.
+func (s *WorkflowSource) listFiles(ctx context.Context, repo repository.Repository, dir string, ref *string) ([]string, error) {
+	var names []string
+	if s.isLocal(repo) {
+		entries, err := fs.ReadDir(s.FS, path.Clean(dir))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				names = append(names, entry.Name())
+			}
+		}
+		return names, nil
+	}
+	if s.Client == nil {
+		return nil, fmt.Errorf("%s:%s: %w", parser.GetRepositoryFullName(repo), dir, ErrRemoteSourceDisabled)
+	}
+	_, dirContent, err := s.Client.GetRepositoryContent(ctx, repo.Owner, repo.Name, dir, ref)
+	if err != nil {
+		if IsHTTPNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, entry := range dirContent {
+		if entry.GetType() == "file" && entry.GetName() != "" {
+			names = append(names, entry.GetName())
+		}
+	}
+	return names, nil
+}
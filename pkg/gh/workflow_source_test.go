@@ -0,0 +1,255 @@
+package gh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh/client"
+)
+
+func TestGetSourceWorkflowDependencies_Offline(t *testing.T) {
+	fsys := fstest.MapFS{
+		".github/workflows/ci.yml": {Data: []byte(`name: CI
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: ./tools/setup
+  release:
+    uses: ./.github/workflows/release.yml
+`)},
+		".github/workflows/release.yml": {Data: []byte(`on: workflow_call
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: octo/publish@v1
+`)},
+		".github/workflows/README.md": {Data: []byte("not a workflow")},
+		"tools/setup/action.yml": {Data: []byte(`runs:
+  using: composite
+  steps:
+    - uses: actions/setup-go@v5
+`)},
+	}
+	src := NewLocalWorkflowSource(fsys, repository.Repository{}, nil)
+
+	deps, err := GetSourceWorkflowDependencies(context.Background(), src, nil, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]int{}
+	for _, dep := range deps {
+		sources[dep.Source] = len(dep.Actions)
+	}
+	want := map[string]int{
+		".github/workflows/ci.yml":      3,
+		".github/workflows/release.yml": 1,
+		"tools/setup/action.yml":        1,
+	}
+	if len(sources) != len(want) {
+		t.Fatalf("sources = %v, want %v", sources, want)
+	}
+	for source, n := range want {
+		if sources[source] != n {
+			t.Errorf("%s: %d actions, want %d", source, sources[source], n)
+		}
+	}
+	if deps[0].Actions[1].Using != "composite" {
+		t.Errorf("local action using = %q, want composite", deps[0].Actions[1].Using)
+	}
+
+	single, err := GetSourceWorkflowFileDependency(context.Background(), src, ".github/workflows/release.yml", nil, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(single) != 1 || single[0].Actions[0].Raw != "octo/publish@v1" {
+		t.Errorf("unexpected single file dependency: %+v", single)
+	}
+}
+
+func TestWorkflowSource_RemoteWithoutClient(t *testing.T) {
+	src := NewLocalWorkflowSource(fstest.MapFS{}, repository.Repository{Owner: "me", Name: "repo"}, nil)
+	if src.Repository.Host != defaultHost {
+		t.Errorf("host = %q, want %q", src.Repository.Host, defaultHost)
+	}
+	_, err := src.readFile(context.Background(), repository.Repository{Owner: "octo", Name: "publish"}, "action.yml", nil)
+	if !errors.Is(err, ErrRemoteSourceDisabled) {
+		t.Errorf("err = %v, want ErrRemoteSourceDisabled", err)
+	}
+	names, err := src.listFiles(context.Background(), src.Repository, workflowsDir, nil)
+	if err != nil || names != nil {
+		t.Errorf("listFiles on missing dir = %v, %v", names, err)
+	}
+}
+
+func TestGetSourceWorkflowDependencies_Resolution(t *testing.T) {
+	fsys := fstest.MapFS{
+		".github/workflows/ci.yml": {Data: []byte(`on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: docker://alpine:3.8
+      - uses: octo/action@${{ inputs.ref }}
+      - uses: ./tools/setup
+`)},
+		"tools/setup/action.yml": {Data: []byte("runs:\n  using: node20\n")},
+	}
+	src := NewLocalWorkflowSource(fsys, repository.Repository{}, nil)
+
+	deps, err := GetSourceWorkflowDependencies(context.Background(), src, nil, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docker", "unresolvable", "local"}
+	actions := deps[0].Actions
+	if len(actions) != len(want) {
+		t.Fatalf("got %d actions, want %d", len(actions), len(want))
+	}
+	for i, resolution := range want {
+		if actions[i].Resolution != resolution {
+			t.Errorf("%s: resolution = %q, want %q", actions[i].Raw, actions[i].Resolution, resolution)
+		}
+	}
+}
+
+// missingActionsFS is a checkout whose workflow references an action twice and a reusable workflow
+// of a repository that cannot be fetched.
+var missingActionsFS = fstest.MapFS{
+	".github/workflows/ci.yml": {Data: []byte(`on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: octo/missing@v1
+      - uses: octo/missing@v1
+  call:
+    uses: octo/missing/.github/workflows/call.yml@v1
+`)},
+}
+
+// statusRoundTripper responds to every request with its HTTP status code.
+type statusRoundTripper int
+
+func (s statusRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:     http.StatusText(int(s)),
+		StatusCode: int(s),
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+// newStatusTestClient creates a *GitHubClient whose requests all fail with status.
+func newStatusTestClient(t *testing.T, status int) *GitHubClient {
+	t.Helper()
+	base := "https://api.github.com/"
+	gc, err := github.NewClient(
+		github.WithHTTPClient(&http.Client{Transport: statusRoundTripper(status)}),
+		github.WithURLs(&base, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := client.NewClient(gc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGetSourceWorkflowDependencies_UnresolvedKeepsHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *GitHubClient
+		want   string
+	}{
+		{"offline", nil, "offline"},
+		{"not found", newStatusTestClient(t, http.StatusNotFound), "not-found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewLocalWorkflowSource(missingActionsFS, repository.Repository{Owner: "me", Name: "repo"}, tt.client)
+
+			deps, err := GetSourceWorkflowDependencies(context.Background(), src, nil, true, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actions := deps[0].Actions
+			if len(actions) != 3 {
+				t.Fatalf("got %d actions, want 3", len(actions))
+			}
+			for _, action := range actions {
+				if action.Resolution != tt.want || action.Host != defaultHost {
+					t.Errorf("%s: resolution = %q, host = %q, want %s on %s", action.Raw, action.Resolution, action.Host, tt.want, defaultHost)
+				}
+			}
+		})
+	}
+}
+
+func TestGetSourceWorkflowDependencies_FetchErrorIsNotNotFound(t *testing.T) {
+	src := NewLocalWorkflowSource(missingActionsFS, repository.Repository{Owner: "me", Name: "repo"}, newStatusTestClient(t, http.StatusInternalServerError))
+
+	deps, err := GetSourceWorkflowDependencies(context.Background(), src, nil, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range deps[0].Actions {
+		if action.Resolution != "" {
+			t.Errorf("%s: resolution = %q, want none for a failed lookup", action.Raw, action.Resolution)
+		}
+	}
+}
+
+func TestUnresolvedAction(t *testing.T) {
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	serverError := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError}}
+	tests := []struct {
+		name    string
+		err     error
+		want    string
+		wantErr bool
+	}{
+		{"offline", fmt.Errorf("octo/missing:action.yml: %w", ErrRemoteSourceDisabled), "offline", false},
+		{"http not found", fmt.Errorf("wrapped: %w", notFound), "not-found", false},
+		{"local not found", fs.ErrNotExist, "not-found", false},
+		{"server error", serverError, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unresolvedAction("ghe.example.com", tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.resolution != tt.want {
+				t.Errorf("resolution = %q, want %q", got.resolution, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionResolution(t *testing.T) {
+	tests := []struct {
+		resolved string
+		want     string
+	}{
+		{"GHE.example.com", "host"},
+		{"github.com", "fallback"},
+	}
+	for _, tt := range tests {
+		if got := actionResolution("ghe.example.com", tt.resolved); got != tt.want {
+			t.Errorf("actionResolution(%q) = %q, want %q", tt.resolved, got, tt.want)
+		}
+	}
+}
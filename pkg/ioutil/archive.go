@@ -4,9 +4,13 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 )
 
 // DownloadZipArchive downloads the workflow run logs archive and returns a zip.Reader for accessing the contents.
@@ -40,3 +44,118 @@ func DownloadZipArchive(ctx context.Context, logURL string) (*zip.Reader, int64,
 
 	return zipReader, int64(len(zipData)), nil
 }
+
+// ErrArchiveTooLarge is returned when a downloaded archive exceeds ZipArchiveOptions.MaxSize
+var ErrArchiveTooLarge = errors.New("archive exceeds the size limit")
+
+// ZipArchiveOptions configures DownloadZipArchiveToFile
+type ZipArchiveOptions struct {
+	TempDir string // directory for the temporary file; defaults to os.TempDir()
+	MaxSize int64  // maximum archive size in bytes; 0 means no limit
+}
+
+// TempZipArchive is a zip archive backed by a temporary file.
+// Close must be called to close and remove the file.
+type TempZipArchive struct {
+	*zip.Reader
+	Size int64
+	file *os.File
+}
+
+// Close closes and removes the temporary file
+func (a *TempZipArchive) Close() error {
+	closeErr := a.file.Close()
+	if err := os.Remove(a.file.Name()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return closeErr
+}
+
+// DownloadZipArchiveToFile downloads a zip archive into a temporary file instead of memory, so that
+// only the entries that are read are decompressed. Downloads larger than opts.MaxSize fail with
+// ErrArchiveTooLarge, checked against Content-Length up front and against the bytes actually received.
+func DownloadZipArchiveToFile(ctx context.Context, archiveURL string, opts *ZipArchiveOptions) (*TempZipArchive, error) {
+	if opts == nil {
+		opts = &ZipArchiveOptions{}
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", archiveURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download archive: %w", err)
+	}
+	defer resp.Body.Close() // nolint
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download archive: status code %d", resp.StatusCode)
+	}
+	if opts.MaxSize > 0 && resp.ContentLength > opts.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes > %d bytes", ErrArchiveTooLarge, resp.ContentLength, opts.MaxSize)
+	}
+
+	file, err := os.CreateTemp(opts.TempDir, "archive-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	archive := &TempZipArchive{file: file}
+	fail := func(err error) (*TempZipArchive, error) {
+		_ = archive.Close()
+		return nil, err
+	}
+
+	var body io.Reader = resp.Body
+	if opts.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, opts.MaxSize+1)
+	}
+	size, err := io.Copy(file, body)
+	if err != nil {
+		return fail(fmt.Errorf("failed to write archive: %w", err))
+	}
+	if opts.MaxSize > 0 && size > opts.MaxSize {
+		return fail(fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, opts.MaxSize))
+	}
+
+	zipReader, err := zip.NewReader(file, size)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create zip reader: %w", err))
+	}
+	archive.Reader = zipReader
+	archive.Size = size
+	return archive, nil
+}
+
+// GetContentLength returns the size of the resource at url without downloading it, by requesting
+// its first byte and reading the total from Content-Range. Signed download URLs are often valid for
+// GET only, so HEAD is not used.
+func GetContentLength(ctx context.Context, url string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to request %s: %w", url, err)
+	}
+	defer resp.Body.Close() // nolint
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		contentRange := resp.Header.Get("Content-Range")
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			if size, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				return size, nil
+			}
+		}
+		return 0, fmt.Errorf("unexpected Content-Range %q", contentRange)
+	case http.StatusOK:
+		if resp.ContentLength >= 0 {
+			return resp.ContentLength, nil
+		}
+		return io.Copy(io.Discard, resp.Body)
+	default:
+		return 0, fmt.Errorf("failed to request %s: status code %d", url, resp.StatusCode)
+	}
+}
@@ -0,0 +1,103 @@
+package ioutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func newTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownloadZipArchiveToFile(t *testing.T) {
+	data := newTestZip(t, map[string]string{"job/1_step.txt": "hello"})
+	chunked := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if chunked {
+			// Flushing before writing drops Content-Length, so only the copy can enforce the cap
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	archive, err := DownloadZipArchiveToFile(context.Background(), srv.URL, &ZipArchiveOptions{TempDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Size != int64(len(data)) || len(archive.File) != 1 || archive.File[0].Name != "job/1_step.txt" {
+		t.Errorf("archive = size %d, files %d", archive.Size, len(archive.File))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("temporary file was not removed: %v", entries)
+	}
+
+	for _, c := range []bool{false, true} {
+		chunked = c
+		_, err = DownloadZipArchiveToFile(context.Background(), srv.URL, &ZipArchiveOptions{TempDir: dir, MaxSize: int64(len(data) - 1)})
+		if !errors.Is(err, ErrArchiveTooLarge) {
+			t.Errorf("chunked=%v: err = %v, want ErrArchiveTooLarge", c, err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("chunked=%v: temporary file was not removed: %v", c, entries)
+		}
+	}
+}
+
+func TestDownloadZipArchiveToFile_StatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	if _, err := DownloadZipArchiveToFile(context.Background(), srv.URL, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestGetContentLength(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/full" {
+			_, _ = w.Write([]byte("0123456789"))
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-0" {
+			t.Errorf("Range = %q", r.Header.Get("Range"))
+		}
+		w.Header().Set("Content-Range", "bytes 0-0/12345")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte("0"))
+	}))
+	defer srv.Close()
+
+	if size, err := GetContentLength(context.Background(), srv.URL+"/range"); err != nil || size != 12345 {
+		t.Errorf("GetContentLength(range) = %d, %v", size, err)
+	}
+	if size, err := GetContentLength(context.Background(), srv.URL+"/full"); err != nil || size != 10 {
+		t.Errorf("GetContentLength(full) = %d, %v", size, err)
+	}
+}
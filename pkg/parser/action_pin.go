@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// usesLinePattern matches a "uses:" line of a workflow or action YAML file.
+// Groups: 1 = prefix up to the value, 2 = opening quote, 3 = value, 4 = closing quote, 5 = trailing comment.
+var usesLinePattern = regexp.MustCompile(`^(\s*(?:-\s+)?uses:\s*)(['"]?)([^'"\s#]+)(['"]?)(\s+#.*)?\s*$`)
+
+// versionCommentPattern matches a trailing comment that records a version, e.g. "v4.2.2" or "tag=v1.0.0".
+var versionCommentPattern = regexp.MustCompile(`^(?:tag=)?v?\d+(?:\.\d+)*(?:[-+][0-9A-Za-z.-]+)?$`)
+
+// commitSHAPattern matches a full-length git commit SHA.
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// ActionUse is a "uses:" value found in a workflow or action YAML file
+type ActionUse struct {
+	Line      int             `json:"line"`              // 1-based line number of the uses: value
+	Reference ActionReference `json:"reference"`         // Parsed action reference
+	Comment   string          `json:"comment,omitempty"` // Trailing comment without the leading "#", e.g. "v4.2.2"
+}
+
+// ActionPin is the commit SHA that an action reference is pinned to
+type ActionPin struct {
+	SHA     string `json:"sha"`     // Full commit SHA the ref resolves to
+	Comment string `json:"comment"` // Version written as a trailing comment, e.g. "v4.2.2"
+}
+
+// isVersionComment returns true if a trailing comment, with or without the leading "#", records a version
+func isVersionComment(comment string) bool {
+	return versionCommentPattern.MatchString(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(comment), "#")))
+}
+
+// IsCommitSHA returns true if ref is a full-length commit SHA
+func IsCommitSHA(ref string) bool {
+	return commitSHAPattern.MatchString(ref)
+}
+
+// IsRemote returns true if the reference points to an action or reusable workflow in another repository.
+// References containing expressions are not remote since they cannot be resolved.
+func (a ActionReference) IsRemote() bool {
+	return !a.IsLocal && a.Owner != "" && a.Repo != "" && !strings.HasPrefix(a.Raw, "docker://") && !a.HasExpression()
+}
+
+// IsPinned returns true if the reference is pinned to a full-length commit SHA
+func (a ActionReference) IsPinned() bool {
+	return IsCommitSHA(a.Ref)
+}
+
+// FindActionUses scans workflow or action YAML content line by line and returns every "uses:" value
+// with its line number. Block scalars and flow mappings are not inspected.
+func FindActionUses(content []byte) []ActionUse {
+	var uses []ActionUse
+	for i, line := range strings.Split(string(content), "\n") {
+		m := usesLinePattern.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+		if m == nil || m[2] != m[4] {
+			continue
+		}
+		ref := ParseActionReference(m[3])
+		if ref.Raw == "" {
+			continue
+		}
+		uses = append(uses, ActionUse{
+			Line:      i + 1,
+			Reference: ref,
+			Comment:   strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(m[5]), "#")),
+		})
+	}
+	return uses
+}
+
+// ActionUseReplacement is the new ref of a "uses:" value and the trailing comment to write
+type ActionUseReplacement struct {
+	Ref     string // New ref, e.g. a commit SHA or "v5"
+	Comment string // Trailing comment without "#"; when empty an existing comment is kept unless it records the old version
+}
+
+// ReplaceActionUses rewrites "uses:" values found in replacements (keyed by the original uses string)
+// to "<name>@<ref>". A non-empty replacement comment replaces any trailing comment; otherwise a trailing
+// comment that records a version is dropped when the ref changes, since it would name the old version.
+// Indentation, quoting, line endings and all other lines are kept as is.
+// It returns the rewritten content and the number of rewritten lines.
+func ReplaceActionUses(content []byte, replacements map[string]ActionUseReplacement) ([]byte, int) {
+	lines := strings.Split(string(content), "\n")
+	count := 0
+	for i, line := range lines {
+		body, cr := strings.CutSuffix(line, "\r")
+		m := usesLinePattern.FindStringSubmatch(body)
+		if m == nil || m[2] != m[4] {
+			continue
+		}
+		replacement, ok := replacements[m[3]]
+		if !ok || replacement.Ref == "" {
+			continue
+		}
+		ref := ParseActionReference(m[3])
+		replaced := m[1] + m[2] + ref.Name() + "@" + replacement.Ref + m[4]
+		switch {
+		case replacement.Comment != "":
+			replaced += " # " + replacement.Comment
+		case replacement.Ref != ref.Ref && isVersionComment(m[5]):
+			// Drop the version of the old ref
+		default:
+			replaced += m[5]
+		}
+		if cr {
+			replaced += "\r"
+		}
+		if replaced != line {
+			lines[i] = replaced
+			count++
+		}
+	}
+	return []byte(strings.Join(lines, "\n")), count
+}
+
+// PinActionUses rewrites "uses:" values found in pins (keyed by the original uses string) to
+// "<name>@<sha>" and replaces any trailing comment with "# <comment>".
+// It returns the rewritten content and the number of rewritten lines.
+func PinActionUses(content []byte, pins map[string]ActionPin) ([]byte, int) {
+	replacements := make(map[string]ActionUseReplacement, len(pins))
+	for uses, pin := range pins {
+		replacements[uses] = ActionUseReplacement{Ref: pin.SHA, Comment: pin.Comment}
+	}
+	return ReplaceActionUses(content, replacements)
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pinTestSHA = "11bd71901bbe5b1630ceea73d27597364c9af683"
+
+func TestFindActionUses(t *testing.T) {
+	content := []byte(`name: CI
+jobs:
+  build:
+    steps:
+      - uses: actions/checkout@v4 # checkout
+      - name: setup
+        uses: "actions/setup-go@v5"
+      - uses: ./local-action
+      - run: echo "uses: not/an-action@v1"
+  call:
+    uses: owner/repo/.github/workflows/ci.yml@main
+`)
+	uses := FindActionUses(content)
+	assert.Len(t, uses, 4)
+	assert.Equal(t, 5, uses[0].Line)
+	assert.Equal(t, "actions/checkout@v4", uses[0].Reference.Raw)
+	assert.Equal(t, "checkout", uses[0].Comment)
+	assert.Equal(t, 7, uses[1].Line)
+	assert.Equal(t, "actions/setup-go@v5", uses[1].Reference.Raw)
+	assert.True(t, uses[2].Reference.IsLocal)
+	assert.Equal(t, 11, uses[3].Line)
+	assert.True(t, uses[3].Reference.IsReusableWorkflow())
+}
+
+func TestPinActionUses(t *testing.T) {
+	content := []byte("jobs:\r\n  build:\r\n    steps:\r\n      - uses: actions/checkout@v4 # old\r\n      - uses: 'actions/setup-go@v5'\r\n      - uses: ./local\r\n")
+	pins := map[string]ActionPin{
+		"actions/checkout@v4": {SHA: pinTestSHA, Comment: "v4.2.2"},
+		"actions/setup-go@v5": {SHA: pinTestSHA, Comment: "v5.0.0"},
+	}
+	pinned, count := PinActionUses(content, pins)
+	assert.Equal(t, 2, count)
+	expected := "jobs:\r\n  build:\r\n    steps:\r\n" +
+		"      - uses: actions/checkout@" + pinTestSHA + " # v4.2.2\r\n" +
+		"      - uses: 'actions/setup-go@" + pinTestSHA + "' # v5.0.0\r\n" +
+		"      - uses: ./local\r\n"
+	assert.Equal(t, expected, string(pinned))
+
+	again, count := PinActionUses(pinned, pins)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, string(pinned), string(again))
+}
+
+func TestReplaceActionUses_VersionComment(t *testing.T) {
+	content := []byte("steps:\n" +
+		"  - uses: actions/checkout@v4 # v4.2.2\n" +
+		"  - uses: actions/setup-go@v4 # keep this note\n" +
+		"  - uses: actions/cache@v4 # v4.0.0\n" +
+		"  - uses: actions/upload-artifact@v4 # v4.1.0\n")
+	replacements := map[string]ActionUseReplacement{
+		"actions/checkout@v4":        {Ref: "v5"},
+		"actions/setup-go@v4":        {Ref: "v5"},
+		"actions/cache@v4":           {Ref: "v5", Comment: "v5.0.1"},
+		"actions/upload-artifact@v4": {Ref: "v4"},
+	}
+	replaced, count := ReplaceActionUses(content, replacements)
+	assert.Equal(t, 3, count)
+	expected := "steps:\n" +
+		"  - uses: actions/checkout@v5\n" +
+		"  - uses: actions/setup-go@v5 # keep this note\n" +
+		"  - uses: actions/cache@v5 # v5.0.1\n" +
+		"  - uses: actions/upload-artifact@v4 # v4.1.0\n"
+	assert.Equal(t, expected, string(replaced))
+}
+
+func TestIsVersionComment(t *testing.T) {
+	assert.True(t, isVersionComment("# v4.2.2"))
+	assert.True(t, isVersionComment("v1"))
+	assert.True(t, isVersionComment("1.2.3-rc.1"))
+	assert.True(t, isVersionComment(" # tag=v2.0.0"))
+	assert.False(t, isVersionComment("# checkout"))
+	assert.False(t, isVersionComment(""))
+}
+
+func TestActionReference_IsPinned(t *testing.T) {
+	assert.True(t, ParseActionReference("actions/checkout@"+pinTestSHA).IsPinned())
+	assert.False(t, ParseActionReference("actions/checkout@v4").IsPinned())
+	assert.True(t, ParseActionReference("actions/checkout@v4").IsRemote())
+	assert.False(t, ParseActionReference("docker://alpine:3").IsRemote())
+	assert.False(t, ParseActionReference("./local").IsRemote())
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// actionVersionPattern matches version-like refs such as "v4", "v4.2", "4.2.1" or "v1.0.0-beta.1"
+var actionVersionPattern = regexp.MustCompile(`^(v?)(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?$`)
+
+// ActionVersion is a semver-like version parsed from an action tag.
+// Precision is the number of numeric components present in the tag (1 for "v4", 3 for "v4.2.1"),
+// so that floating major/minor tags can be told apart from full versions.
+type ActionVersion struct {
+	Raw        string `json:"raw"`
+	Prefix     string `json:"prefix,omitempty"`
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+	Patch      int    `json:"patch"`
+	Prerelease string `json:"prerelease,omitempty"`
+	Precision  int    `json:"precision"`
+}
+
+// ParseActionVersion parses a version-like ref. It returns false if ref is not a version.
+func ParseActionVersion(ref string) (ActionVersion, bool) {
+	m := actionVersionPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return ActionVersion{}, false
+	}
+	v := ActionVersion{Raw: ref, Prefix: m[1], Prerelease: m[5], Precision: 1}
+	v.Major, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Minor, _ = strconv.Atoi(m[3])
+		v.Precision = 2
+	}
+	if m[4] != "" {
+		v.Patch, _ = strconv.Atoi(m[4])
+		v.Precision = 3
+	}
+	return v, true
+}
+
+// IsPrerelease returns true if the version has a prerelease suffix
+func (v ActionVersion) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Format returns the version with the given precision and the original prefix, e.g. "v4" or "v4.2"
+func (v ActionVersion) Format(precision int) string {
+	switch precision {
+	case 1:
+		return fmt.Sprintf("%s%d", v.Prefix, v.Major)
+	case 2:
+		return fmt.Sprintf("%s%d.%d", v.Prefix, v.Major, v.Minor)
+	default:
+		s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+		if v.Prerelease != "" {
+			s += "-" + v.Prerelease
+		}
+		return s
+	}
+}
+
+// CompareActionVersions compares two versions by major, minor and patch, then by prerelease.
+// A release sorts after its prereleases; at equal numbers a more precise tag sorts after a less precise one.
+func CompareActionVersions(a, b ActionVersion) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	if a.Prerelease != b.Prerelease {
+		if a.Prerelease == "" {
+			return 1
+		}
+		if b.Prerelease == "" {
+			return -1
+		}
+		return strings.Compare(a.Prerelease, b.Prerelease)
+	}
+	return sign(a.Precision - b.Precision)
+}
+
+// UpgradeKind returns the kind of upgrade from current to latest: "major", "minor", "patch",
+// or "" if current already covers latest. Components missing from a floating tag are treated
+// as tracking the newest value (e.g. "v4" is up to date with "v4.2.1").
+func UpgradeKind(current, latest ActionVersion) string {
+	switch {
+	case latest.Major > current.Major:
+		return "major"
+	case latest.Major < current.Major || current.Precision < 2:
+		return ""
+	case latest.Minor > current.Minor:
+		return "minor"
+	case latest.Minor < current.Minor || current.Precision < 3:
+		return ""
+	case latest.Patch > current.Patch:
+		return "patch"
+	case latest.Patch == current.Patch && current.IsPrerelease() && !latest.IsPrerelease():
+		return "patch"
+	}
+	return ""
+}
+
+func sign(d int) int {
+	switch {
+	case d > 0:
+		return 1
+	case d < 0:
+		return -1
+	}
+	return 0
+}
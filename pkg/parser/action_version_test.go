@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseActionVersion(t *testing.T) {
+	v, ok := ParseActionVersion("v4.2.1")
+	assert.True(t, ok)
+	assert.Equal(t, ActionVersion{Raw: "v4.2.1", Prefix: "v", Major: 4, Minor: 2, Patch: 1, Precision: 3}, v)
+
+	v, ok = ParseActionVersion("1.0.0-beta.1")
+	assert.True(t, ok)
+	assert.Equal(t, "beta.1", v.Prerelease)
+	assert.Equal(t, "", v.Prefix)
+
+	v, ok = ParseActionVersion("v4")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v.Precision)
+	assert.Equal(t, "v4", v.Format(1))
+	assert.Equal(t, "v4.0", v.Format(2))
+
+	_, ok = ParseActionVersion("main")
+	assert.False(t, ok)
+}
+
+func TestCompareActionVersions(t *testing.T) {
+	parse := func(s string) ActionVersion {
+		v, _ := ParseActionVersion(s)
+		return v
+	}
+	assert.Equal(t, 1, CompareActionVersions(parse("v4.10.0"), parse("v4.9.9")))
+	assert.Equal(t, 1, CompareActionVersions(parse("v1.0.0"), parse("v1.0.0-rc.1")))
+	assert.Equal(t, 1, CompareActionVersions(parse("v4.0.0"), parse("v4")))
+	assert.Equal(t, 0, CompareActionVersions(parse("v4.0.0"), parse("v4.0.0")))
+}
+
+func TestUpgradeKind(t *testing.T) {
+	parse := func(s string) ActionVersion {
+		v, _ := ParseActionVersion(s)
+		return v
+	}
+	tests := []struct {
+		current string
+		latest  string
+		want    string
+	}{
+		{"v4", "v4.2.1", ""},
+		{"v4", "v5.0.0", "major"},
+		{"v4.1", "v4.2.0", "minor"},
+		{"v4.2", "v4.2.9", ""},
+		{"v4.2.1", "v4.2.2", "patch"},
+		{"v4.2.2", "v4.2.2", ""},
+		{"v4.2.2-rc.1", "v4.2.2", "patch"},
+		{"v5", "v4.9.0", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, UpgradeKind(parse(tt.current), parse(tt.latest)), "%s -> %s", tt.current, tt.latest)
+	}
+}
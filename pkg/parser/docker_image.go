@@ -0,0 +1,65 @@
+package parser
+
+import "strings"
+
+// DefaultDockerRegistry is the registry of docker image references without a registry host
+const DefaultDockerRegistry = "docker.io"
+
+// DockerImageReference is a parsed "docker://" uses value, e.g. "docker://ghcr.io/owner/image:1.0@sha256:..."
+type DockerImageReference struct {
+	Registry string `json:"registry" yaml:"registry"` // e.g. "docker.io", "ghcr.io", "localhost:5000"
+	Image    string `json:"image" yaml:"image"`       // repository path within the registry, e.g. "library/alpine"
+	Tag      string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Digest   string `json:"digest,omitempty" yaml:"digest,omitempty"` // e.g. "sha256:..."
+}
+
+// String returns the image reference without the docker:// scheme
+func (d DockerImageReference) String() string {
+	s := d.Registry + "/" + d.Image
+	if d.Tag != "" {
+		s += ":" + d.Tag
+	}
+	if d.Digest != "" {
+		s += "@" + d.Digest
+	}
+	return s
+}
+
+// IsPinned returns true if the image is referenced by digest
+func (d DockerImageReference) IsPinned() bool {
+	return d.Digest != ""
+}
+
+// ParseDockerImageReference parses an image reference with or without the docker:// scheme.
+// The first path component is the registry when it looks like a host (contains "." or ":" or is "localhost");
+// otherwise the image is on Docker Hub, where official images live under "library/".
+func ParseDockerImageReference(s string) (DockerImageReference, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "docker://")
+	if s == "" {
+		return DockerImageReference{}, false
+	}
+	var ref DockerImageReference
+	if name, digest, ok := strings.Cut(s, "@"); ok {
+		s = name
+		ref.Digest = digest
+	}
+	// A tag separator is a ":" after the last "/", so that a registry port is not mistaken for a tag
+	if i := strings.LastIndex(s, ":"); i > strings.LastIndex(s, "/") {
+		ref.Tag = s[i+1:]
+		s = s[:i]
+	}
+	if first, rest, ok := strings.Cut(s, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		ref.Image = rest
+	} else {
+		ref.Registry = DefaultDockerRegistry
+		ref.Image = s
+		if !strings.Contains(s, "/") {
+			ref.Image = "library/" + s
+		}
+	}
+	if ref.Image == "" {
+		return DockerImageReference{}, false
+	}
+	return ref, true
+}
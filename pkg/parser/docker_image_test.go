@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDockerImageReference(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected DockerImageReference
+		str      string
+	}{
+		{"docker://alpine", DockerImageReference{Registry: "docker.io", Image: "library/alpine"}, "docker.io/library/alpine"},
+		{"docker://owner/image:1.0", DockerImageReference{Registry: "docker.io", Image: "owner/image", Tag: "1.0"}, "docker.io/owner/image:1.0"},
+		{"docker://ghcr.io/owner/image:v2@sha256:abc", DockerImageReference{Registry: "ghcr.io", Image: "owner/image", Tag: "v2", Digest: "sha256:abc"}, "ghcr.io/owner/image:v2@sha256:abc"},
+		{"localhost:5000/tool", DockerImageReference{Registry: "localhost:5000", Image: "tool"}, "localhost:5000/tool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseDockerImageReference(tt.input)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.str, got.String())
+		})
+	}
+
+	pinned, _ := ParseDockerImageReference("docker://alpine@sha256:abc")
+	assert.True(t, pinned.IsPinned())
+
+	_, ok := ParseDockerImageReference("docker://")
+	assert.False(t, ok)
+}
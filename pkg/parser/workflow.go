@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
-	"gopkg.in/yaml.v3"
 )
 
 // ActionReference represents a parsed reference to a GitHub Action or reusable workflow
@@ -19,6 +18,29 @@ type ActionReference struct {
 	Using   string `json:"using,omitempty" yaml:"using,omitempty"` // runs.using value of the referenced action, e.g. "node20", "composite"
 	Host    string `json:"host,omitempty" yaml:"host,omitempty"`   // GitHub host, e.g. "github.com" or GHES hostname
 	JobID   string `json:"jobId,omitempty" yaml:"jobId,omitempty"` // Job ID in the workflow file, e.g. "build", "test"
+
+	Docker       *DockerImageReference `json:"docker,omitempty" yaml:"docker,omitempty"`             // Parsed image for "docker://" references
+	Unresolvable string                `json:"unresolvable,omitempty" yaml:"unresolvable,omitempty"` // Reason the reference cannot be resolved statically
+	Resolution   string                `json:"resolution,omitempty" yaml:"resolution,omitempty"`     // Where the reference resolved during traversal, see ActionResolution*
+}
+
+// Values of ActionReference.Resolution
+const (
+	ActionResolutionLocal        = "local"        // a file of the repository being analyzed
+	ActionResolutionHost         = "host"         // fetched from the host of the referencing repository
+	ActionResolutionFallback     = "fallback"     // fetched from github.com through the fallback client (e.g. not mirrored on GHES)
+	ActionResolutionDocker       = "docker"       // a container image, not a repository
+	ActionResolutionNotFound     = "not-found"    // the repository or action file does not exist
+	ActionResolutionOffline      = "offline"      // a remote reference that was not followed without a GitHub client
+	ActionResolutionUnresolvable = "unresolvable" // see ActionReference.Unresolvable
+)
+
+// expressionUnresolvableReason is the Unresolvable reason of uses values containing an expression
+const expressionUnresolvableReason = "uses contains an expression, which GitHub Actions does not evaluate"
+
+// HasExpression returns true if the uses value contains a ${{ }} expression
+func (a ActionReference) HasExpression() bool {
+	return strings.Contains(a.Raw, "${{")
 }
 
 // Name returns a human-readable name for the action reference
@@ -132,22 +154,6 @@ type WorkflowDependency struct {
 	Repository repository.Repository `json:"repository,omitempty"` // Repository context for the source file
 }
 
-// workflowYAML represents the structure of a GitHub Actions workflow YAML file
-type workflowYAML struct {
-	Name string                 `yaml:"name"`
-	Jobs map[string]workflowJob `yaml:"jobs"`
-}
-
-type workflowJob struct {
-	Uses  string         `yaml:"uses"`
-	Steps []workflowStep `yaml:"steps"`
-}
-
-type workflowStep struct {
-	Uses string         `yaml:"uses"`
-	With map[string]any `yaml:"with"`
-}
-
 // CheckoutPath represents a mapping of a checkout destination path to a repository.
 // This is used to resolve local action references (e.g. "./my-tool") that depend on
 // a repository checked out to a specific path via actions/checkout.
@@ -163,40 +169,6 @@ func isCheckoutAction(uses string) bool {
 	return ref.Owner == "actions" && ref.Repo == "checkout"
 }
 
-// getWithString extracts a string value from a step's "with" map
-func getWithString(with map[string]any, key string) string {
-	if with == nil {
-		return ""
-	}
-	v, ok := with[key]
-	if !ok {
-		return ""
-	}
-	if v == nil {
-		return ""
-	}
-
-	switch vv := v.(type) {
-	case string:
-		return vv
-	case *yaml.Node:
-		// Only accept scalar nodes as string values
-		if vv.Kind == yaml.ScalarNode {
-			return vv.Value
-		}
-		return ""
-	case yaml.Node:
-		// Handle non-pointer yaml.Node as well
-		if vv.Kind == yaml.ScalarNode {
-			return vv.Value
-		}
-		return ""
-	default:
-		// Non-string types are not treated as valid string values
-		return ""
-	}
-}
-
 // resolveLocalActionByCheckout resolves a local action reference using checkout path mappings.
 // If the local action path matches a checkout path prefix, it sets the Owner, Repo, Path, and Ref
 // fields on the ActionReference based on the checkout mapping.
@@ -236,16 +208,6 @@ func resolveLocalActionByCheckout(ref *ActionReference, checkouts []CheckoutPath
 	}
 }
 
-// actionYAML represents the structure of an action.yml/action.yaml file
-type actionYAML struct {
-	Runs actionRuns `yaml:"runs"`
-}
-
-type actionRuns struct {
-	Using string         `yaml:"using"`
-	Steps []workflowStep `yaml:"steps"`
-}
-
 // ParseActionReference parses a "uses" string into an ActionReference
 func ParseActionReference(uses string) ActionReference {
 	uses = strings.TrimSpace(uses)
@@ -255,21 +217,35 @@ func ParseActionReference(uses string) ActionReference {
 
 	// Local action reference (e.g. "./path/to/action")
 	if strings.HasPrefix(uses, "./") {
-		return ActionReference{
+		ref := ActionReference{
 			Raw:     uses,
 			IsLocal: true,
 		}
+		if ref.HasExpression() {
+			ref.Unresolvable = expressionUnresolvableReason
+		}
+		return ref
 	}
 
 	// Docker reference (e.g. "docker://image:tag")
 	if strings.HasPrefix(uses, "docker://") {
-		return ActionReference{
+		ref := ActionReference{
 			Raw:     uses,
 			IsLocal: false,
 		}
+		if image, ok := ParseDockerImageReference(uses); ok {
+			ref.Docker = &image
+		} else {
+			ref.Unresolvable = "invalid docker image reference"
+		}
+		return ref
 	}
 
 	ref := ActionReference{Raw: uses}
+	// GitHub Actions does not evaluate expressions in uses, so the reference can never resolve
+	if ref.HasExpression() {
+		ref.Unresolvable = expressionUnresolvableReason
+	}
 
 	// Split by @ to separate ref/version
 	atParts := strings.SplitN(uses, "@", 2)
@@ -293,8 +269,8 @@ func ParseActionReference(uses string) ActionReference {
 
 // ParseWorkflowName extracts the workflow name from a workflow YAML content
 func ParseWorkflowName(content []byte) string {
-	var wf workflowYAML
-	if err := yaml.Unmarshal(content, &wf); err != nil {
+	wf, err := ParseWorkflow(content)
+	if err != nil {
 		return ""
 	}
 	return wf.Name
@@ -303,59 +279,23 @@ func ParseWorkflowName(content []byte) string {
 // ParseWorkflowYAML parses a GitHub Actions workflow YAML content and extracts action references.
 // Jobs and steps are processed in YAML document order to produce deterministic output.
 func ParseWorkflowYAML(content []byte) (string, []ActionReference, error) {
-	var doc yaml.Node
-	if err := yaml.Unmarshal(content, &doc); err != nil {
-		return "", nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
-	}
-
-	// doc is a Document node; the actual mapping is its first child
-	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
-		return "", nil, fmt.Errorf("unexpected YAML structure")
-	}
-	root := doc.Content[0]
-	if root.Kind != yaml.MappingNode {
-		return "", nil, fmt.Errorf("expected YAML mapping at root")
-	}
-
-	var name string
-	var jobsNode *yaml.Node
-
-	// Iterate root mapping keys in document order
-	for i := 0; i+1 < len(root.Content); i += 2 {
-		key := root.Content[i].Value
-		val := root.Content[i+1]
-		switch key {
-		case "name":
-			name = val.Value
-		case "jobs":
-			jobsNode = val
-		}
-	}
-
-	if jobsNode == nil || jobsNode.Kind != yaml.MappingNode {
-		return name, nil, nil
+	wf, err := ParseWorkflow(content)
+	if err != nil {
+		return "", nil, err
 	}
+	return wf.Name, WorkflowActionReferences(wf), nil
+}
 
+// WorkflowActionReferences extracts the action references of a parsed workflow in document order.
+// Local action references are resolved through the actions/checkout steps that precede them in the same job.
+func WorkflowActionReferences(wf *Workflow) []ActionReference {
 	var refs []ActionReference
-	// Iterate jobs in document order (mapping key/value pairs)
-	for i := 0; i+1 < len(jobsNode.Content); i += 2 {
-		jobID := jobsNode.Content[i].Value
-		jobNode := jobsNode.Content[i+1]
-		if jobNode.Kind != yaml.MappingNode {
-			continue
-		}
-
-		// Decode the job into a struct for convenient access
-		var job workflowJob
-		if err := jobNode.Decode(&job); err != nil {
-			continue
-		}
-
+	for _, job := range wf.Jobs {
 		// Reusable workflow reference (jobs.<job_id>.uses)
 		if job.Uses != "" {
 			ref := ParseActionReference(job.Uses)
 			if ref.Raw != "" {
-				ref.JobID = jobID
+				ref.JobID = job.ID
 				refs = append(refs, ref)
 			}
 		}
@@ -369,13 +309,11 @@ func ParseWorkflowYAML(content []byte) (string, []ActionReference, error) {
 			}
 			// Track checkout steps with path specified
 			if isCheckoutAction(step.Uses) {
-				repo := getWithString(step.With, "repository")
-				path := getWithString(step.With, "path")
-				if path != "" {
+				if path := step.With["path"]; path != "" {
 					checkouts = append(checkouts, CheckoutPath{
-						Repository: repo, // empty means self repository
+						Repository: step.With["repository"], // empty means self repository
 						Path:       path,
-						Ref:        getWithString(step.With, "ref"),
+						Ref:        step.With["ref"],
 					})
 				}
 			}
@@ -385,21 +323,24 @@ func ParseWorkflowYAML(content []byte) (string, []ActionReference, error) {
 				if ref.IsLocal && !ref.IsReusableWorkflow() {
 					resolveLocalActionByCheckout(&ref, checkouts)
 				}
-				ref.JobID = jobID
+				ref.JobID = job.ID
 				refs = append(refs, ref)
 			}
 		}
 	}
-	return name, refs, nil
+	return refs
 }
 
 // ParseActionYAML parses an action.yml/action.yaml content and extracts action references.
 // It returns the list of action references (non-nil only for composite actions),
 // the runs.using value (e.g. "node20", "composite", "docker"), and any parse error.
 func ParseActionYAML(content []byte) ([]ActionReference, string, error) {
-	var action actionYAML
-	if err := yaml.Unmarshal(content, &action); err != nil {
-		return nil, "", fmt.Errorf("failed to parse action YAML: %w", err)
+	action, err := ParseActionMetadata(content)
+	if err != nil {
+		return nil, "", err
+	}
+	if action.Runs == nil {
+		return nil, "", nil
 	}
 
 	// Only composite actions have steps that can reference other actions
@@ -0,0 +1,902 @@
+package parser
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a 1-based line and column in a YAML source file
+type Position struct {
+	Line   int `json:"line" yaml:"line"`
+	Column int `json:"column" yaml:"column"`
+}
+
+// Workflow is a typed model of a GitHub Actions workflow file
+type Workflow struct {
+	Name        string            `json:"name,omitempty"`
+	RunName     string            `json:"run_name,omitempty"`
+	On          []*WorkflowEvent  `json:"on,omitempty"` // Trigger events in document order
+	Permissions *Permissions      `json:"permissions,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	Defaults    *Defaults         `json:"defaults,omitempty"`
+	Concurrency *Concurrency      `json:"concurrency,omitempty"`
+	Jobs        []*WorkflowJob    `json:"jobs,omitempty"` // Jobs in document order
+	Pos         Position          `json:"pos"`
+}
+
+// Event returns the trigger event with the given name, or nil if the workflow is not triggered by it
+func (w *Workflow) Event(name string) *WorkflowEvent {
+	for _, event := range w.On {
+		if event.Name == name {
+			return event
+		}
+	}
+	return nil
+}
+
+// Job returns the job with the given ID, or nil if it does not exist
+func (w *Workflow) Job(id string) *WorkflowJob {
+	for _, job := range w.Jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// WorkflowEvent is a single trigger under "on:" with its filters and inputs
+type WorkflowEvent struct {
+	Name           string                `json:"name"`
+	Types          []string              `json:"types,omitempty"`
+	Branches       []string              `json:"branches,omitempty"`
+	BranchesIgnore []string              `json:"branches_ignore,omitempty"`
+	Tags           []string              `json:"tags,omitempty"`
+	TagsIgnore     []string              `json:"tags_ignore,omitempty"`
+	Paths          []string              `json:"paths,omitempty"`
+	PathsIgnore    []string              `json:"paths_ignore,omitempty"`
+	Workflows      []string              `json:"workflows,omitempty"` // workflow_run
+	Cron           []string              `json:"cron,omitempty"`      // schedule
+	Inputs         []*WorkflowInput      `json:"inputs,omitempty"`    // workflow_dispatch, workflow_call
+	Outputs        []*WorkflowCallOutput `json:"outputs,omitempty"`   // workflow_call
+	Secrets        []*WorkflowCallSecret `json:"secrets,omitempty"`   // workflow_call
+	Pos            Position              `json:"pos"`
+}
+
+// WorkflowInput is an input of workflow_dispatch or workflow_call
+type WorkflowInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	HasDefault  bool     `json:"has_default,omitempty"`
+	Options     []string `json:"options,omitempty"` // choice inputs
+	Pos         Position `json:"pos"`
+}
+
+// WorkflowCallOutput is an output of a reusable workflow
+type WorkflowCallOutput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Pos         Position `json:"pos"`
+}
+
+// WorkflowCallSecret is a secret accepted by a reusable workflow
+type WorkflowCallSecret struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Pos         Position `json:"pos"`
+}
+
+// Permissions is a "permissions:" block. All is set for the "read-all"/"write-all" shorthand
+// (or an expression); otherwise Scopes holds the per-scope access, and is empty for "permissions: {}".
+type Permissions struct {
+	All    string            `json:"all,omitempty"`
+	Scopes map[string]string `json:"scopes,omitempty"`
+	Pos    Position          `json:"pos"`
+}
+
+// Defaults is a "defaults:" block
+type Defaults struct {
+	Shell            string   `json:"shell,omitempty"`
+	WorkingDirectory string   `json:"working_directory,omitempty"`
+	Pos              Position `json:"pos"`
+}
+
+// Concurrency is a "concurrency:" block, either a group string or a mapping
+type Concurrency struct {
+	Group            string   `json:"group,omitempty"`
+	CancelInProgress string   `json:"cancel_in_progress,omitempty"`
+	Pos              Position `json:"pos"`
+}
+
+// RunsOn is a "runs-on:" value, either labels or a runner group
+type RunsOn struct {
+	Labels []string `json:"labels,omitempty"`
+	Group  string   `json:"group,omitempty"`
+	Pos    Position `json:"pos"`
+}
+
+// Environment is a job's "environment:" value
+type Environment struct {
+	Name string   `json:"name,omitempty"`
+	URL  string   `json:"url,omitempty"`
+	Pos  Position `json:"pos"`
+}
+
+// Container is a job container or service container
+type Container struct {
+	Image       string            `json:"image,omitempty"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	Ports       []string          `json:"ports,omitempty"`
+	Volumes     []string          `json:"volumes,omitempty"`
+	Options     string            `json:"options,omitempty"`
+	Pos         Position          `json:"pos"`
+}
+
+// Strategy is a job's "strategy:" block
+type Strategy struct {
+	Matrix      *Matrix  `json:"matrix,omitempty"`
+	FailFast    string   `json:"fail_fast,omitempty"`
+	MaxParallel string   `json:"max_parallel,omitempty"`
+	Pos         Position `json:"pos"`
+}
+
+// Matrix is a strategy matrix. Expression is set when the whole matrix is an expression
+// such as "${{ fromJSON(needs.setup.outputs.matrix) }}".
+type Matrix struct {
+	Values     map[string][]any `json:"values,omitempty"`
+	Include    []map[string]any `json:"include,omitempty"`
+	Exclude    []map[string]any `json:"exclude,omitempty"`
+	Expression string           `json:"expression,omitempty"`
+	Pos        Position         `json:"pos"`
+}
+
+// JobSecrets is the "secrets:" value of a job calling a reusable workflow
+type JobSecrets struct {
+	Inherit bool              `json:"inherit,omitempty"`
+	Values  map[string]string `json:"values,omitempty"`
+	Pos     Position          `json:"pos"`
+}
+
+// WorkflowJob is a single job of a workflow
+type WorkflowJob struct {
+	ID              string                `json:"id"`
+	Name            string                `json:"name,omitempty"`
+	Needs           []string              `json:"needs,omitempty"`
+	If              string                `json:"if,omitempty"`
+	RunsOn          *RunsOn               `json:"runs_on,omitempty"`
+	Permissions     *Permissions          `json:"permissions,omitempty"`
+	Environment     *Environment          `json:"environment,omitempty"`
+	Concurrency     *Concurrency          `json:"concurrency,omitempty"`
+	Outputs         map[string]string     `json:"outputs,omitempty"`
+	Env             map[string]string     `json:"env,omitempty"`
+	Defaults        *Defaults             `json:"defaults,omitempty"`
+	TimeoutMinutes  string                `json:"timeout_minutes,omitempty"`
+	ContinueOnError string                `json:"continue_on_error,omitempty"`
+	Strategy        *Strategy             `json:"strategy,omitempty"`
+	Container       *Container            `json:"container,omitempty"`
+	Services        map[string]*Container `json:"services,omitempty"`
+	Uses            string                `json:"uses,omitempty"` // Reusable workflow reference
+	UsesPos         Position              `json:"uses_pos"`
+	With            map[string]string     `json:"with,omitempty"`
+	Secrets         *JobSecrets           `json:"secrets,omitempty"`
+	Steps           []*WorkflowStep       `json:"steps,omitempty"`
+	Pos             Position              `json:"pos"`
+}
+
+// WorkflowStep is a single step of a job or composite action
+type WorkflowStep struct {
+	ID               string            `json:"id,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	If               string            `json:"if,omitempty"`
+	Uses             string            `json:"uses,omitempty"`
+	UsesPos          Position          `json:"uses_pos"`
+	With             map[string]string `json:"with,omitempty"`
+	Run              string            `json:"run,omitempty"`
+	RunPos           Position          `json:"run_pos"`
+	Shell            string            `json:"shell,omitempty"`
+	WorkingDirectory string            `json:"working_directory,omitempty"`
+	Env              map[string]string `json:"env,omitempty"`
+	ContinueOnError  string            `json:"continue_on_error,omitempty"`
+	TimeoutMinutes   string            `json:"timeout_minutes,omitempty"`
+	Pos              Position          `json:"pos"`
+}
+
+// ActionMetadata is a typed model of an action.yml/action.yaml file
+type ActionMetadata struct {
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Author      string          `json:"author,omitempty"`
+	Inputs      []*ActionInput  `json:"inputs,omitempty"`
+	Outputs     []*ActionOutput `json:"outputs,omitempty"`
+	Runs        *ActionRuns     `json:"runs,omitempty"`
+	Branding    *ActionBranding `json:"branding,omitempty"`
+	Pos         Position        `json:"pos"`
+}
+
+// ActionInput is an input of an action
+type ActionInput struct {
+	Name               string   `json:"name"`
+	Description        string   `json:"description,omitempty"`
+	Required           bool     `json:"required,omitempty"`
+	Default            string   `json:"default,omitempty"`
+	HasDefault         bool     `json:"has_default,omitempty"`
+	DeprecationMessage string   `json:"deprecation_message,omitempty"`
+	Pos                Position `json:"pos"`
+}
+
+// ActionOutput is an output of an action
+type ActionOutput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Value       string   `json:"value,omitempty"` // composite actions only
+	Pos         Position `json:"pos"`
+}
+
+// ActionRuns is the "runs:" block of an action
+type ActionRuns struct {
+	Using          string            `json:"using,omitempty"`
+	Main           string            `json:"main,omitempty"`
+	Pre            string            `json:"pre,omitempty"`
+	PreIf          string            `json:"pre_if,omitempty"`
+	Post           string            `json:"post,omitempty"`
+	PostIf         string            `json:"post_if,omitempty"`
+	Image          string            `json:"image,omitempty"`
+	Entrypoint     string            `json:"entrypoint,omitempty"`
+	PreEntrypoint  string            `json:"pre_entrypoint,omitempty"`
+	PostEntrypoint string            `json:"post_entrypoint,omitempty"`
+	Args           []string          `json:"args,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	Steps          []*WorkflowStep   `json:"steps,omitempty"`
+	Pos            Position          `json:"pos"`
+}
+
+// ActionBranding is the "branding:" block of an action
+type ActionBranding struct {
+	Icon  string `json:"icon,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// ParseWorkflow parses workflow YAML content into a typed Workflow model.
+// Unknown keys are ignored and values of an unexpected shape are left empty,
+// so that partially invalid workflows can still be analyzed.
+func ParseWorkflow(content []byte) (*Workflow, error) {
+	root, err := parseYAMLRootMapping(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("failed to parse workflow YAML: unexpected YAML structure")
+	}
+	wf := &Workflow{Pos: nodePosition(root)}
+	forEachMappingPair(root, func(key, val *yaml.Node) {
+		switch key.Value {
+		case "name":
+			wf.Name = nodeString(val)
+		case "run-name":
+			wf.RunName = nodeString(val)
+		case "on":
+			wf.On = decodeWorkflowEvents(val)
+		case "permissions":
+			wf.Permissions = decodePermissions(val)
+		case "env":
+			wf.Env = nodeStringMap(val)
+		case "defaults":
+			wf.Defaults = decodeDefaults(val)
+		case "concurrency":
+			wf.Concurrency = decodeConcurrency(val)
+		case "jobs":
+			forEachMappingPair(val, func(jobKey, jobVal *yaml.Node) {
+				if jobVal.Kind != yaml.MappingNode {
+					return
+				}
+				wf.Jobs = append(wf.Jobs, decodeWorkflowJob(jobKey, jobVal))
+			})
+		}
+	})
+	return wf, nil
+}
+
+// ParseActionMetadata parses action.yml/action.yaml content into a typed ActionMetadata model.
+// An empty or null document yields empty metadata.
+func ParseActionMetadata(content []byte) (*ActionMetadata, error) {
+	root, err := parseYAMLRootMapping(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse action YAML: %w", err)
+	}
+	if root == nil {
+		return &ActionMetadata{}, nil
+	}
+	action := &ActionMetadata{Pos: nodePosition(root)}
+	forEachMappingPair(root, func(key, val *yaml.Node) {
+		switch key.Value {
+		case "name":
+			action.Name = nodeString(val)
+		case "description":
+			action.Description = nodeString(val)
+		case "author":
+			action.Author = nodeString(val)
+		case "inputs":
+			forEachMappingPair(val, func(k, v *yaml.Node) {
+				input := &ActionInput{Name: k.Value, Pos: nodePosition(k)}
+				forEachMappingPair(v, func(fk, fv *yaml.Node) {
+					switch fk.Value {
+					case "description":
+						input.Description = nodeString(fv)
+					case "required":
+						input.Required = nodeBool(fv)
+					case "default":
+						input.Default = nodeString(fv)
+						input.HasDefault = true
+					case "deprecationMessage":
+						input.DeprecationMessage = nodeString(fv)
+					}
+				})
+				action.Inputs = append(action.Inputs, input)
+			})
+		case "outputs":
+			forEachMappingPair(val, func(k, v *yaml.Node) {
+				output := &ActionOutput{Name: k.Value, Pos: nodePosition(k)}
+				forEachMappingPair(v, func(fk, fv *yaml.Node) {
+					switch fk.Value {
+					case "description":
+						output.Description = nodeString(fv)
+					case "value":
+						output.Value = nodeString(fv)
+					}
+				})
+				action.Outputs = append(action.Outputs, output)
+			})
+		case "runs":
+			action.Runs = decodeActionRuns(val)
+		case "branding":
+			action.Branding = &ActionBranding{}
+			forEachMappingPair(val, func(fk, fv *yaml.Node) {
+				switch fk.Value {
+				case "icon":
+					action.Branding.Icon = nodeString(fv)
+				case "color":
+					action.Branding.Color = nodeString(fv)
+				}
+			})
+		}
+	})
+	return action, nil
+}
+
+// parseYAMLRootMapping unmarshals content and returns the root mapping node of the document.
+// It returns a nil node without error for an empty or null document.
+func parseYAMLRootMapping(content []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	// doc is a Document node; the actual mapping is its first child
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("unexpected YAML structure")
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected YAML mapping at root")
+	}
+	return root, nil
+}
+
+func decodeWorkflowEvents(node *yaml.Node) []*WorkflowEvent {
+	var events []*WorkflowEvent
+	switch node.Kind {
+	case yaml.ScalarNode:
+		events = append(events, &WorkflowEvent{Name: node.Value, Pos: nodePosition(node)})
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if item.Kind == yaml.ScalarNode {
+				events = append(events, &WorkflowEvent{Name: item.Value, Pos: nodePosition(item)})
+			}
+		}
+	case yaml.MappingNode:
+		forEachMappingPair(node, func(key, val *yaml.Node) {
+			events = append(events, decodeWorkflowEvent(key, val))
+		})
+	}
+	return events
+}
+
+func decodeWorkflowEvent(key, val *yaml.Node) *WorkflowEvent {
+	event := &WorkflowEvent{Name: key.Value, Pos: nodePosition(key)}
+	if key.Value == "schedule" && val.Kind == yaml.SequenceNode {
+		for _, item := range val.Content {
+			forEachMappingPair(item, func(k, v *yaml.Node) {
+				if k.Value == "cron" {
+					event.Cron = append(event.Cron, nodeString(v))
+				}
+			})
+		}
+		return event
+	}
+	forEachMappingPair(val, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "types":
+			event.Types = nodeStrings(v)
+		case "branches":
+			event.Branches = nodeStrings(v)
+		case "branches-ignore":
+			event.BranchesIgnore = nodeStrings(v)
+		case "tags":
+			event.Tags = nodeStrings(v)
+		case "tags-ignore":
+			event.TagsIgnore = nodeStrings(v)
+		case "paths":
+			event.Paths = nodeStrings(v)
+		case "paths-ignore":
+			event.PathsIgnore = nodeStrings(v)
+		case "workflows":
+			event.Workflows = nodeStrings(v)
+		case "inputs":
+			forEachMappingPair(v, func(ik, iv *yaml.Node) {
+				event.Inputs = append(event.Inputs, decodeWorkflowInput(ik, iv))
+			})
+		case "outputs":
+			forEachMappingPair(v, func(ok, ov *yaml.Node) {
+				output := &WorkflowCallOutput{Name: ok.Value, Pos: nodePosition(ok)}
+				forEachMappingPair(ov, func(fk, fv *yaml.Node) {
+					switch fk.Value {
+					case "description":
+						output.Description = nodeString(fv)
+					case "value":
+						output.Value = nodeString(fv)
+					}
+				})
+				event.Outputs = append(event.Outputs, output)
+			})
+		case "secrets":
+			forEachMappingPair(v, func(sk, sv *yaml.Node) {
+				secret := &WorkflowCallSecret{Name: sk.Value, Pos: nodePosition(sk)}
+				forEachMappingPair(sv, func(fk, fv *yaml.Node) {
+					switch fk.Value {
+					case "description":
+						secret.Description = nodeString(fv)
+					case "required":
+						secret.Required = nodeBool(fv)
+					}
+				})
+				event.Secrets = append(event.Secrets, secret)
+			})
+		}
+	})
+	return event
+}
+
+func decodeWorkflowInput(key, val *yaml.Node) *WorkflowInput {
+	input := &WorkflowInput{Name: key.Value, Pos: nodePosition(key)}
+	forEachMappingPair(val, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "description":
+			input.Description = nodeString(v)
+		case "type":
+			input.Type = nodeString(v)
+		case "required":
+			input.Required = nodeBool(v)
+		case "default":
+			input.Default = nodeString(v)
+			input.HasDefault = true
+		case "options":
+			input.Options = nodeStrings(v)
+		}
+	})
+	return input
+}
+
+func decodePermissions(node *yaml.Node) *Permissions {
+	perms := &Permissions{Pos: nodePosition(node)}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		perms.All = node.Value
+	case yaml.MappingNode:
+		perms.Scopes = make(map[string]string)
+		forEachMappingPair(node, func(k, v *yaml.Node) {
+			perms.Scopes[k.Value] = nodeString(v)
+		})
+	}
+	return perms
+}
+
+func decodeDefaults(node *yaml.Node) *Defaults {
+	defaults := &Defaults{Pos: nodePosition(node)}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		if k.Value != "run" {
+			return
+		}
+		forEachMappingPair(v, func(rk, rv *yaml.Node) {
+			switch rk.Value {
+			case "shell":
+				defaults.Shell = nodeString(rv)
+			case "working-directory":
+				defaults.WorkingDirectory = nodeString(rv)
+			}
+		})
+	})
+	return defaults
+}
+
+func decodeConcurrency(node *yaml.Node) *Concurrency {
+	concurrency := &Concurrency{Pos: nodePosition(node)}
+	if node.Kind == yaml.ScalarNode {
+		concurrency.Group = node.Value
+		return concurrency
+	}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "group":
+			concurrency.Group = nodeString(v)
+		case "cancel-in-progress":
+			concurrency.CancelInProgress = nodeString(v)
+		}
+	})
+	return concurrency
+}
+
+func decodeRunsOn(node *yaml.Node) *RunsOn {
+	runsOn := &RunsOn{Pos: nodePosition(node)}
+	if node.Kind != yaml.MappingNode {
+		runsOn.Labels = nodeStrings(node)
+		return runsOn
+	}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "group":
+			runsOn.Group = nodeString(v)
+		case "labels":
+			runsOn.Labels = nodeStrings(v)
+		}
+	})
+	return runsOn
+}
+
+func decodeEnvironment(node *yaml.Node) *Environment {
+	env := &Environment{Pos: nodePosition(node)}
+	if node.Kind == yaml.ScalarNode {
+		env.Name = node.Value
+		return env
+	}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "name":
+			env.Name = nodeString(v)
+		case "url":
+			env.URL = nodeString(v)
+		}
+	})
+	return env
+}
+
+func decodeContainer(node *yaml.Node) *Container {
+	container := &Container{Pos: nodePosition(node)}
+	if node.Kind == yaml.ScalarNode {
+		container.Image = node.Value
+		return container
+	}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "image":
+			container.Image = nodeString(v)
+		case "credentials":
+			container.Credentials = nodeStringMap(v)
+		case "env":
+			container.Env = nodeStringMap(v)
+		case "ports":
+			container.Ports = nodeStrings(v)
+		case "volumes":
+			container.Volumes = nodeStrings(v)
+		case "options":
+			container.Options = nodeString(v)
+		}
+	})
+	return container
+}
+
+func decodeStrategy(node *yaml.Node) *Strategy {
+	strategy := &Strategy{Pos: nodePosition(node)}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "matrix":
+			strategy.Matrix = decodeMatrix(v)
+		case "fail-fast":
+			strategy.FailFast = nodeString(v)
+		case "max-parallel":
+			strategy.MaxParallel = nodeString(v)
+		}
+	})
+	return strategy
+}
+
+func decodeMatrix(node *yaml.Node) *Matrix {
+	matrix := &Matrix{Pos: nodePosition(node)}
+	if node.Kind == yaml.ScalarNode {
+		matrix.Expression = node.Value
+		return matrix
+	}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "include":
+			_ = v.Decode(&matrix.Include)
+		case "exclude":
+			_ = v.Decode(&matrix.Exclude)
+		default:
+			var values []any
+			if v.Kind == yaml.SequenceNode {
+				_ = v.Decode(&values)
+			} else {
+				values = []any{v.Value}
+			}
+			if matrix.Values == nil {
+				matrix.Values = make(map[string][]any)
+			}
+			matrix.Values[k.Value] = values
+		}
+	})
+	return matrix
+}
+
+func decodeJobSecrets(node *yaml.Node) *JobSecrets {
+	secrets := &JobSecrets{Pos: nodePosition(node)}
+	if node.Kind == yaml.ScalarNode {
+		secrets.Inherit = node.Value == "inherit"
+		return secrets
+	}
+	secrets.Values = nodeStringMap(node)
+	return secrets
+}
+
+func decodeWorkflowJob(key, val *yaml.Node) *WorkflowJob {
+	job := &WorkflowJob{ID: key.Value, Pos: nodePosition(key)}
+	forEachMappingPair(val, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "name":
+			job.Name = nodeString(v)
+		case "needs":
+			job.Needs = nodeStrings(v)
+		case "if":
+			job.If = nodeString(v)
+		case "runs-on":
+			job.RunsOn = decodeRunsOn(v)
+		case "permissions":
+			job.Permissions = decodePermissions(v)
+		case "environment":
+			job.Environment = decodeEnvironment(v)
+		case "concurrency":
+			job.Concurrency = decodeConcurrency(v)
+		case "outputs":
+			job.Outputs = nodeStringMap(v)
+		case "env":
+			job.Env = nodeStringMap(v)
+		case "defaults":
+			job.Defaults = decodeDefaults(v)
+		case "timeout-minutes":
+			job.TimeoutMinutes = nodeString(v)
+		case "continue-on-error":
+			job.ContinueOnError = nodeString(v)
+		case "strategy":
+			job.Strategy = decodeStrategy(v)
+		case "container":
+			job.Container = decodeContainer(v)
+		case "services":
+			forEachMappingPair(v, func(sk, sv *yaml.Node) {
+				if job.Services == nil {
+					job.Services = make(map[string]*Container)
+				}
+				job.Services[sk.Value] = decodeContainer(sv)
+			})
+		case "uses":
+			job.Uses = nodeString(v)
+			job.UsesPos = nodePosition(v)
+		case "with":
+			job.With = nodeStringMap(v)
+		case "secrets":
+			job.Secrets = decodeJobSecrets(v)
+		case "steps":
+			job.Steps = decodeWorkflowSteps(v)
+		}
+	})
+	return job
+}
+
+func decodeWorkflowSteps(node *yaml.Node) []*WorkflowStep {
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var steps []*WorkflowStep
+	for _, item := range node.Content {
+		item = resolveAlias(item)
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		step := &WorkflowStep{Pos: nodePosition(item)}
+		forEachMappingPair(item, func(k, v *yaml.Node) {
+			switch k.Value {
+			case "id":
+				step.ID = nodeString(v)
+			case "name":
+				step.Name = nodeString(v)
+			case "if":
+				step.If = nodeString(v)
+			case "uses":
+				step.Uses = nodeString(v)
+				step.UsesPos = nodePosition(v)
+			case "with":
+				step.With = nodeStringMap(v)
+			case "run":
+				step.Run = nodeString(v)
+				step.RunPos = nodePosition(v)
+			case "shell":
+				step.Shell = nodeString(v)
+			case "working-directory":
+				step.WorkingDirectory = nodeString(v)
+			case "env":
+				step.Env = nodeStringMap(v)
+			case "continue-on-error":
+				step.ContinueOnError = nodeString(v)
+			case "timeout-minutes":
+				step.TimeoutMinutes = nodeString(v)
+			}
+		})
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func decodeActionRuns(node *yaml.Node) *ActionRuns {
+	runs := &ActionRuns{Pos: nodePosition(node)}
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		switch k.Value {
+		case "using":
+			runs.Using = nodeString(v)
+		case "main":
+			runs.Main = nodeString(v)
+		case "pre":
+			runs.Pre = nodeString(v)
+		case "pre-if":
+			runs.PreIf = nodeString(v)
+		case "post":
+			runs.Post = nodeString(v)
+		case "post-if":
+			runs.PostIf = nodeString(v)
+		case "image":
+			runs.Image = nodeString(v)
+		case "entrypoint":
+			runs.Entrypoint = nodeString(v)
+		case "pre-entrypoint":
+			runs.PreEntrypoint = nodeString(v)
+		case "post-entrypoint":
+			runs.PostEntrypoint = nodeString(v)
+		case "args":
+			runs.Args = nodeStrings(v)
+		case "env":
+			runs.Env = nodeStringMap(v)
+		case "steps":
+			runs.Steps = decodeWorkflowSteps(v)
+		}
+	})
+	return runs
+}
+
+// forEachMappingPair calls fn for each key/value pair of a mapping node in document order.
+// Aliases are followed and "<<" merge keys are expanded in place, with keys set
+// explicitly on the mapping taking precedence over merged ones.
+// Non-mapping nodes are ignored.
+func forEachMappingPair(node *yaml.Node, fn func(key, val *yaml.Node)) {
+	seen := make(map[string]bool)
+	for _, pair := range mappingPairs(node) {
+		if seen[pair[0].Value] {
+			continue
+		}
+		seen[pair[0].Value] = true
+		fn(pair[0], resolveAlias(pair[1]))
+	}
+}
+
+// mappingPairs returns the key/value pairs of a mapping node with merge keys expanded.
+// Explicit keys come first so that callers keeping the first occurrence of a key
+// follow YAML merge precedence.
+func mappingPairs(node *yaml.Node) [][2]*yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var explicit, merged [][2]*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		if !isMergeKey(key) {
+			explicit = append(explicit, [2]*yaml.Node{key, val})
+			continue
+		}
+		val = resolveAlias(val)
+		if val.Kind == yaml.SequenceNode {
+			// earlier mappings in a merge sequence take precedence over later ones
+			for _, item := range val.Content {
+				merged = append(merged, mappingPairs(item)...)
+			}
+		} else {
+			merged = append(merged, mappingPairs(val)...)
+		}
+	}
+	return append(explicit, merged...)
+}
+
+// isMergeKey reports whether key is the YAML "<<" merge key
+func isMergeKey(key *yaml.Node) bool {
+	return key.Kind == yaml.ScalarNode && key.Value == "<<" && (key.Tag == "" || key.Tag == "!!merge")
+}
+
+// resolveAlias follows alias nodes to the node they refer to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// nodePosition returns the source position of a node
+func nodePosition(node *yaml.Node) Position {
+	return Position{Line: node.Line, Column: node.Column}
+}
+
+// nodeString returns the value of a scalar node, or "" for other node kinds
+func nodeString(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// nodeBool returns true if node is the scalar "true"
+func nodeBool(node *yaml.Node) bool {
+	return nodeString(node) == "true"
+}
+
+// nodeStrings returns the scalar values of a sequence node, or a single value for a scalar node
+func nodeStrings(node *yaml.Node) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil
+		}
+		return []string{node.Value}
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if item.Kind == yaml.ScalarNode {
+				values = append(values, item.Value)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// nodeStringMap returns the scalar values of a mapping node keyed by their keys.
+// Non-scalar values are skipped.
+func nodeStringMap(node *yaml.Node) map[string]string {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	values := make(map[string]string, len(node.Content)/2)
+	forEachMappingPair(node, func(k, v *yaml.Node) {
+		if v.Kind == yaml.ScalarNode {
+			values[k.Value] = nodeString(v)
+		}
+	})
+	return values
+}
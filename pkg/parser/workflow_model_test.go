@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkflow(t *testing.T) {
+	content := `name: CI
+on:
+  push:
+    branches: [main]
+    paths-ignore:
+      - docs/**
+  pull_request_target:
+    types: [opened, synchronize]
+  schedule:
+    - cron: '0 0 * * *'
+  workflow_dispatch:
+    inputs:
+      level:
+        type: choice
+        required: true
+        default: info
+        options: [info, debug]
+permissions: read-all
+env:
+  GO111MODULE: on
+concurrency:
+  group: ci-${{ github.ref }}
+  cancel-in-progress: true
+jobs:
+  build:
+    runs-on: [self-hosted, linux]
+    permissions:
+      contents: write
+    environment: production
+    strategy:
+      matrix:
+        go: ['1.22', '1.23']
+        include:
+          - go: '1.24'
+            experimental: true
+    services:
+      redis:
+        image: redis:7
+        ports: ['6379:6379']
+    outputs:
+      version: ${{ steps.v.outputs.version }}
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+      - id: v
+        run: echo "version=1" >> "$GITHUB_OUTPUT"
+  call:
+    needs: build
+    uses: octo/repo/.github/workflows/release.yml@v1
+    secrets: inherit
+`
+	wf, err := ParseWorkflow([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "CI", wf.Name)
+
+	assert.Len(t, wf.On, 4)
+	push := wf.Event("push")
+	assert.NotNil(t, push)
+	assert.Equal(t, []string{"main"}, push.Branches)
+	assert.Equal(t, []string{"docs/**"}, push.PathsIgnore)
+	assert.Equal(t, 3, push.Pos.Line)
+	assert.Equal(t, []string{"opened", "synchronize"}, wf.Event("pull_request_target").Types)
+	assert.Equal(t, []string{"0 0 * * *"}, wf.Event("schedule").Cron)
+	dispatch := wf.Event("workflow_dispatch")
+	assert.Len(t, dispatch.Inputs, 1)
+	assert.Equal(t, WorkflowInput{Name: "level", Type: "choice", Required: true, Default: "info", HasDefault: true, Options: []string{"info", "debug"}, Pos: Position{Line: 13, Column: 7}}, *dispatch.Inputs[0])
+
+	assert.Equal(t, "read-all", wf.Permissions.All)
+	assert.Equal(t, "on", wf.Env["GO111MODULE"])
+	assert.Equal(t, "ci-${{ github.ref }}", wf.Concurrency.Group)
+	assert.Equal(t, "true", wf.Concurrency.CancelInProgress)
+
+	assert.Len(t, wf.Jobs, 2)
+	build := wf.Job("build")
+	assert.Equal(t, []string{"self-hosted", "linux"}, build.RunsOn.Labels)
+	assert.Equal(t, "write", build.Permissions.Scopes["contents"])
+	assert.Equal(t, "production", build.Environment.Name)
+	assert.Equal(t, []any{"1.22", "1.23"}, build.Strategy.Matrix.Values["go"])
+	assert.Len(t, build.Strategy.Matrix.Include, 1)
+	assert.Equal(t, "redis:7", build.Services["redis"].Image)
+	assert.Equal(t, "${{ steps.v.outputs.version }}", build.Outputs["version"])
+	assert.Len(t, build.Steps, 2)
+	assert.Equal(t, "actions/checkout@v4", build.Steps[0].Uses)
+	assert.Equal(t, Position{Line: 43, Column: 15}, build.Steps[0].UsesPos)
+	assert.Equal(t, "0", build.Steps[0].With["fetch-depth"])
+	assert.Equal(t, 47, build.Steps[1].RunPos.Line)
+
+	call := wf.Job("call")
+	assert.Equal(t, []string{"build"}, call.Needs)
+	assert.Equal(t, "octo/repo/.github/workflows/release.yml@v1", call.Uses)
+	assert.True(t, call.Secrets.Inherit)
+}
+
+func TestParseWorkflow_ShorthandForms(t *testing.T) {
+	wf, err := ParseWorkflow([]byte(`on: [push, pull_request]
+permissions: {}
+jobs:
+  test:
+    runs-on:
+      group: large
+    container: node:20
+    concurrency: test
+    strategy:
+      matrix: ${{ fromJSON(inputs.matrix) }}
+`))
+	assert.NoError(t, err)
+	assert.Len(t, wf.On, 2)
+	assert.Equal(t, "pull_request", wf.On[1].Name)
+	assert.Empty(t, wf.Permissions.All)
+	assert.NotNil(t, wf.Permissions.Scopes)
+	assert.Empty(t, wf.Permissions.Scopes)
+	job := wf.Jobs[0]
+	assert.Equal(t, "large", job.RunsOn.Group)
+	assert.Equal(t, "node:20", job.Container.Image)
+	assert.Equal(t, "test", job.Concurrency.Group)
+	assert.Equal(t, "${{ fromJSON(inputs.matrix) }}", job.Strategy.Matrix.Expression)
+
+	_, err = ParseWorkflow([]byte("- not a mapping"))
+	assert.Error(t, err)
+}
+
+func TestParseActionMetadata(t *testing.T) {
+	action, err := ParseActionMetadata([]byte(`name: Setup
+description: Set up the tool
+inputs:
+  version:
+    description: Tool version
+    required: false
+    default: latest
+outputs:
+  path:
+    description: Install path
+    value: ${{ steps.install.outputs.path }}
+runs:
+  using: composite
+  steps:
+    - id: install
+      run: ./install.sh
+      shell: bash
+    - uses: actions/cache@v4
+branding:
+  icon: box
+  color: blue
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Setup", action.Name)
+	assert.Len(t, action.Inputs, 1)
+	assert.Equal(t, "latest", action.Inputs[0].Default)
+	assert.True(t, action.Inputs[0].HasDefault)
+	assert.Equal(t, 4, action.Inputs[0].Pos.Line)
+	assert.Equal(t, "${{ steps.install.outputs.path }}", action.Outputs[0].Value)
+	assert.Equal(t, "composite", action.Runs.Using)
+	assert.Len(t, action.Runs.Steps, 2)
+	assert.Equal(t, "bash", action.Runs.Steps[0].Shell)
+	assert.Equal(t, "actions/cache@v4", action.Runs.Steps[1].Uses)
+	assert.Equal(t, "box", action.Branding.Icon)
+}
@@ -55,7 +55,19 @@ func TestParseActionReference(t *testing.T) {
 			name: "docker action",
 			uses: "docker://alpine:3.8",
 			expected: ActionReference{
-				Raw: "docker://alpine:3.8",
+				Raw:    "docker://alpine:3.8",
+				Docker: &DockerImageReference{Registry: "docker.io", Image: "library/alpine", Tag: "3.8"},
+			},
+		},
+		{
+			name: "expression ref",
+			uses: "octo/action@${{ inputs.ref }}",
+			expected: ActionReference{
+				Raw:          "octo/action@${{ inputs.ref }}",
+				Owner:        "octo",
+				Repo:         "action",
+				Ref:          "${{ inputs.ref }}",
+				Unresolvable: expressionUnresolvableReason,
 			},
 		},
 		{
@@ -170,6 +182,82 @@ runs:
 		assert.Equal(t, "node20", using)
 		assert.Nil(t, refs)
 	})
+
+	t.Run("empty action", func(t *testing.T) {
+		refs, using, err := ParseActionYAML([]byte(""))
+		assert.NoError(t, err)
+		assert.Equal(t, "", using)
+		assert.Nil(t, refs)
+	})
+
+	t.Run("composite action with aliased steps", func(t *testing.T) {
+		yaml := `
+x-steps: &s
+  - uses: actions/checkout@v4
+  - run: make
+    shell: bash
+runs:
+  using: composite
+  steps: *s
+`
+		refs, using, err := ParseActionYAML([]byte(yaml))
+		assert.NoError(t, err)
+		assert.Equal(t, "composite", using)
+		assert.Len(t, refs, 1)
+		assert.Equal(t, "actions/checkout@v4", refs[0].Raw)
+	})
+}
+
+func TestParseWorkflowYAML_MergeKeys(t *testing.T) {
+	yaml := `
+name: CI
+on: push
+x-defaults: &d
+  uses: actions/setup-go@v5
+  with:
+    go-version: '1.22'
+    cache: ~
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - <<: *d
+        name: Setup Go
+`
+	name, refs, err := ParseWorkflowYAML([]byte(yaml))
+	assert.NoError(t, err)
+	assert.Equal(t, "CI", name)
+	assert.Len(t, refs, 2)
+	assert.Equal(t, "actions/checkout@v4", refs[0].Raw)
+	assert.Equal(t, "actions/setup-go@v5", refs[1].Raw)
+
+	wf, err := ParseWorkflow([]byte(yaml))
+	assert.NoError(t, err)
+	step := wf.Job("build").Steps[1]
+	assert.Equal(t, "Setup Go", step.Name)
+	assert.Equal(t, map[string]string{"go-version": "1.22", "cache": ""}, step.With)
+}
+
+func TestParseWorkflowYAML_MergeKeyExplicitKeyWins(t *testing.T) {
+	yaml := `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - <<: {uses: actions/checkout@v3}
+        uses: actions/checkout@v4
+`
+	_, refs, err := ParseWorkflowYAML([]byte(yaml))
+	assert.NoError(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, "actions/checkout@v4", refs[0].Raw)
+}
+
+func TestParseWorkflowYAML_Empty(t *testing.T) {
+	_, _, err := ParseWorkflowYAML([]byte(""))
+	assert.Error(t, err)
 }
 
 func TestActionReferenceName(t *testing.T) {
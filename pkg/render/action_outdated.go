@@ -0,0 +1,98 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+// actionUpgradeRow is a single usage of an action reference with its upgrade status
+type actionUpgradeRow struct {
+	Upgrade *gh.ActionUpgrade
+	Usage   gh.ActionUsage
+}
+
+type actionUpgradeFieldGetter func(row *actionUpgradeRow) string
+type actionUpgradeFieldGetters struct {
+	Func map[string]actionUpgradeFieldGetter
+}
+
+func NewActionUpgradeFieldGetters() *actionUpgradeFieldGetters {
+	return &actionUpgradeFieldGetters{
+		Func: map[string]actionUpgradeFieldGetter{
+			"ACTION": func(row *actionUpgradeRow) string {
+				return row.Upgrade.Action.Name()
+			},
+			"REF": func(row *actionUpgradeRow) string {
+				return row.Upgrade.Action.Ref
+			},
+			"CURRENT": func(row *actionUpgradeRow) string {
+				return row.Upgrade.CurrentVersion
+			},
+			"LATEST": func(row *actionUpgradeRow) string {
+				return row.Upgrade.LatestVersion
+			},
+			"SUGGESTED": func(row *actionUpgradeRow) string {
+				return row.Upgrade.SuggestedRef
+			},
+			"STATUS": func(row *actionUpgradeRow) string {
+				return row.Upgrade.Status
+			},
+			"REPOSITORY": func(row *actionUpgradeRow) string {
+				return row.Usage.Repository
+			},
+			"SOURCE": func(row *actionUpgradeRow) string {
+				return row.Usage.Source
+			},
+			"JOB": func(row *actionUpgradeRow) string {
+				return row.Usage.JobID
+			},
+			"ERROR": func(row *actionUpgradeRow) string {
+				return row.Upgrade.Error
+			},
+		},
+	}
+}
+
+func (u *actionUpgradeFieldGetters) GetField(row *actionUpgradeRow, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(row)
+	}
+	return ""
+}
+
+// RenderActionUpgrades renders the upgrade status of action references with one row per usage.
+func (r *Renderer) RenderActionUpgrades(upgrades []*gh.ActionUpgrade, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(upgrades)
+	}
+
+	if len(upgrades) == 0 {
+		r.writeLine("No actions.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"ACTION", "CURRENT", "LATEST", "STATUS", "SUGGESTED", "SOURCE", "JOB"}
+	}
+
+	getter := NewActionUpgradeFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, upgrade := range upgrades {
+		usages := upgrade.Usages
+		if len(usages) == 0 {
+			usages = []gh.ActionUsage{{}}
+		}
+		for _, usage := range usages {
+			row := make([]string, len(headers))
+			for i, header := range headers {
+				row[i] = getter.GetField(&actionUpgradeRow{Upgrade: upgrade, Usage: usage}, header)
+			}
+			table.Append(row)
+		}
+	}
+
+	return table.Render()
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type actionPinResultFieldGetter func(result *gh.ActionPinResult) string
+type actionPinResultFieldGetters struct {
+	Func map[string]actionPinResultFieldGetter
+}
+
+func NewActionPinResultFieldGetters() *actionPinResultFieldGetters {
+	return &actionPinResultFieldGetters{
+		Func: map[string]actionPinResultFieldGetter{
+			"REPOSITORY": func(result *gh.ActionPinResult) string {
+				return result.Repository
+			},
+			"SOURCE": func(result *gh.ActionPinResult) string {
+				return result.Source
+			},
+			"LINE": func(result *gh.ActionPinResult) string {
+				return ToString(result.Line)
+			},
+			"USES": func(result *gh.ActionPinResult) string {
+				return result.Uses
+			},
+			"PINNED": func(result *gh.ActionPinResult) string {
+				return ToString(result.Pinned)
+			},
+			"SHA": func(result *gh.ActionPinResult) string {
+				return result.SHA
+			},
+			"COMMENT": func(result *gh.ActionPinResult) string {
+				return result.Comment
+			},
+			"ERROR": func(result *gh.ActionPinResult) string {
+				return result.Error
+			},
+		},
+	}
+}
+
+func (u *actionPinResultFieldGetters) GetField(result *gh.ActionPinResult, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(result)
+	}
+	return ""
+}
+
+// RenderActionPinResults renders the pinning status of action references in a table format.
+func (r *Renderer) RenderActionPinResults(results []*gh.ActionPinResult, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(results)
+	}
+
+	if len(results) == 0 {
+		r.writeLine("All actions are pinned.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"SOURCE", "LINE", "USES", "PINNED", "SHA", "COMMENT", "ERROR"}
+	}
+
+	getter := NewActionPinResultFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, result := range results {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(result, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
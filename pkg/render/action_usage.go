@@ -0,0 +1,77 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type actionUsageEntryFieldGetter func(entry *gh.ActionUsageEntry) string
+type actionUsageEntryFieldGetters struct {
+	Func map[string]actionUsageEntryFieldGetter
+}
+
+func NewActionUsageEntryFieldGetters() *actionUsageEntryFieldGetters {
+	return &actionUsageEntryFieldGetters{
+		Func: map[string]actionUsageEntryFieldGetter{
+			"REPOSITORY": func(entry *gh.ActionUsageEntry) string {
+				return entry.Repository
+			},
+			"SOURCE": func(entry *gh.ActionUsageEntry) string {
+				return entry.Source
+			},
+			"JOB": func(entry *gh.ActionUsageEntry) string {
+				return entry.JobID
+			},
+			"ACTION": func(entry *gh.ActionUsageEntry) string {
+				return entry.Action
+			},
+			"REF": func(entry *gh.ActionUsageEntry) string {
+				return entry.Ref
+			},
+			"TRANSITIVE": func(entry *gh.ActionUsageEntry) string {
+				return ToString(entry.IsTransitive())
+			},
+			"VIA": func(entry *gh.ActionUsageEntry) string {
+				return strings.Join(entry.Via, " > ")
+			},
+		},
+	}
+}
+
+func (u *actionUsageEntryFieldGetters) GetField(entry *gh.ActionUsageEntry, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(entry)
+	}
+	return ""
+}
+
+// RenderActionUsageEntries renders the repositories using an action in a table format.
+func (r *Renderer) RenderActionUsageEntries(entries []*gh.ActionUsageEntry, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(entries)
+	}
+
+	if len(entries) == 0 {
+		r.writeLine("No usages found.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"REPOSITORY", "SOURCE", "JOB", "ACTION", "REF", "VIA"}
+	}
+
+	getter := NewActionUsageEntryFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, entry := range entries {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(entry, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
@@ -0,0 +1,201 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+)
+
+// FormatAnnotations is the output format that emits findings as GitHub Actions annotations
+const FormatAnnotations = "annotations"
+
+// Annotation is a finding located in a file, rendered as an Actions workflow command
+type Annotation struct {
+	Level      string // actions.AnnotationError, actions.AnnotationWarning or actions.AnnotationNotice
+	Message    string
+	Properties actions.AnnotationProperties
+}
+
+// RenderAnnotations renders findings as ::error/::warning/::notice workflow commands when running in
+// GitHub Actions, so that they are shown inline on the changed files. Outside Actions each finding is
+// written as a "file:line:column: level: message" line instead.
+func (r *Renderer) RenderAnnotations(annotations []Annotation) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(annotations)
+	}
+	inActions := actions.IsRunsOn()
+	for _, a := range annotations {
+		if inActions {
+			r.writeLine(actions.FormatCommand(a.Level, a.Properties.Map(), a.Message))
+			continue
+		}
+		r.writeLine(formatAnnotationLine(a))
+	}
+	return nil
+}
+
+func formatAnnotationLine(a Annotation) string {
+	location := a.Properties.File
+	if a.Properties.Line > 0 {
+		location += fmt.Sprintf(":%d", a.Properties.Line)
+		if a.Properties.Column > 0 {
+			location += fmt.Sprintf(":%d", a.Properties.Column)
+		}
+	}
+	message := a.Message
+	if a.Properties.Title != "" {
+		message = a.Properties.Title + ": " + message
+	}
+	if location == "" {
+		return fmt.Sprintf("%s: %s", a.Level, message)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, a.Level, message)
+}
+
+// CodeScanningAlertAnnotation converts the most recent instance of a code scanning alert to an annotation.
+// Alerts with error severity or a critical/high security severity become errors and notes become notices.
+// It returns false when the alert has no location.
+func CodeScanningAlertAnnotation(alert *github.Alert) (Annotation, bool) {
+	instance := alert.GetMostRecentInstance()
+	location := instance.GetLocation()
+	if location.GetPath() == "" {
+		return Annotation{}, false
+	}
+	rule := alert.GetRule()
+	level := actions.AnnotationWarning
+	switch {
+	case rule.GetSeverity() == "error", rule.GetSecuritySeverityLevel() == "critical", rule.GetSecuritySeverityLevel() == "high":
+		level = actions.AnnotationError
+	case rule.GetSeverity() == "note":
+		level = actions.AnnotationNotice
+	}
+	message := instance.GetMessage().GetText()
+	if message == "" {
+		message = rule.GetDescription()
+	}
+	title := rule.GetID()
+	if title == "" {
+		title = rule.GetName()
+	}
+	return Annotation{
+		Level:   level,
+		Message: message,
+		Properties: actions.AnnotationProperties{
+			Title:     strings.TrimSpace(fmt.Sprintf("%s %s", alert.GetTool().GetName(), title)),
+			File:      location.GetPath(),
+			Line:      location.GetStartLine(),
+			EndLine:   location.GetEndLine(),
+			Column:    location.GetStartColumn(),
+			EndColumn: location.GetEndColumn(),
+		},
+	}, true
+}
+
+// SecretScanningAlertLocationAnnotation converts a secret scanning alert location in a file to an error annotation.
+// It returns false for locations that are not in a file, e.g. issue or pull request comments.
+func SecretScanningAlertLocationAnnotation(location *github.SecretScanningAlertLocation) (Annotation, bool) {
+	details := location.GetDetails()
+	if details.GetPath() == "" {
+		return Annotation{}, false
+	}
+	message := "A secret was found in this file"
+	if sha := details.GetCommitSHA(); sha != "" {
+		message += " at commit " + sha
+	}
+	return Annotation{
+		Level:   actions.AnnotationError,
+		Message: message,
+		Properties: actions.AnnotationProperties{
+			Title:     "Secret scanning",
+			File:      details.GetPath(),
+			Line:      details.GetStartline(),
+			EndLine:   details.GetEndLine(),
+			Column:    details.GetStartColumn(),
+			EndColumn: details.GetEndColumn(),
+		},
+	}, true
+}
+
+// PullRequestCommentAnnotation converts a pull request review comment to a notice annotation on the
+// commented lines. Outdated comments fall back to their original line.
+// It returns false for comments without a path.
+func PullRequestCommentAnnotation(comment *github.PullRequestComment) (Annotation, bool) {
+	if comment.GetPath() == "" {
+		return Annotation{}, false
+	}
+	line := comment.GetLine()
+	startLine := comment.GetStartLine()
+	if line == 0 {
+		line = comment.GetOriginalLine()
+		startLine = comment.GetOriginalStartLine()
+	}
+	props := actions.AnnotationProperties{
+		Title: "Review comment by " + comment.GetUser().GetLogin(),
+		File:  comment.GetPath(),
+		Line:  line,
+	}
+	if startLine > 0 && startLine < line {
+		props.Line = startLine
+		props.EndLine = line
+	}
+	return Annotation{
+		Level:      actions.AnnotationNotice,
+		Message:    comment.GetBody(),
+		Properties: props,
+	}, true
+}
+
+// RenderCodeScanningAlertsWithFormat renders code scanning alerts as a table or, with FormatAnnotations, as annotations
+func (r *Renderer) RenderCodeScanningAlertsWithFormat(format string, alerts []*github.Alert, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(alerts)
+	}
+	if !strings.EqualFold(format, FormatAnnotations) {
+		return r.RenderCodeScanningAlerts(alerts, headers)
+	}
+	annotations := make([]Annotation, 0, len(alerts))
+	for _, alert := range alerts {
+		if a, ok := CodeScanningAlertAnnotation(alert); ok {
+			annotations = append(annotations, a)
+		}
+	}
+	return r.RenderAnnotations(annotations)
+}
+
+// RenderSecretScanningAlertLocationsWithFormat renders secret scanning alert locations as a table or,
+// with FormatAnnotations, as annotations
+func (r *Renderer) RenderSecretScanningAlertLocationsWithFormat(format string, locations []*github.SecretScanningAlertLocation) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(locations)
+	}
+	if !strings.EqualFold(format, FormatAnnotations) {
+		return r.RenderSecretScanningAlertLocations(locations)
+	}
+	annotations := make([]Annotation, 0, len(locations))
+	for _, location := range locations {
+		if a, ok := SecretScanningAlertLocationAnnotation(location); ok {
+			annotations = append(annotations, a)
+		}
+	}
+	return r.RenderAnnotations(annotations)
+}
+
+// RenderPullRequestCommentsWithFormat renders pull request review comments as a table or,
+// with FormatAnnotations, as annotations
+func (r *Renderer) RenderPullRequestCommentsWithFormat(format string, comments []*github.PullRequestComment, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(comments)
+	}
+	if !strings.EqualFold(format, FormatAnnotations) {
+		return r.RenderPullRequestComments(comments, headers)
+	}
+	annotations := make([]Annotation, 0, len(comments))
+	for _, comment := range comments {
+		if a, ok := PullRequestCommentAnnotation(comment); ok {
+			annotations = append(annotations, a)
+		}
+	}
+	return r.RenderAnnotations(annotations)
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCodeScanningAlertsWithFormat_Annotations(t *testing.T) {
+	alerts := []*github.Alert{
+		{
+			Rule: &github.Rule{ID: github.Ptr("go/sql-injection"), Severity: github.Ptr("warning"), SecuritySeverityLevel: github.Ptr("high")},
+			Tool: &github.Tool{Name: github.Ptr("CodeQL")},
+			MostRecentInstance: &github.MostRecentInstance{
+				Message:  &github.Message{Text: github.Ptr("query built from user input")},
+				Location: &github.Location{Path: github.Ptr("db.go"), StartLine: github.Ptr(12), StartColumn: github.Ptr(3)},
+			},
+		},
+		{Rule: &github.Rule{ID: github.Ptr("no-location")}},
+	}
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	r := NewStringRenderer(nil)
+	assert.NoError(t, r.Renderer.RenderCodeScanningAlertsWithFormat(FormatAnnotations, alerts, nil))
+	assert.Equal(t, "::error col=3,file=db.go,line=12,title=CodeQL go/sql-injection::query built from user input\n", r.Stdout.String())
+
+	t.Setenv("GITHUB_ACTIONS", "")
+	r = NewStringRenderer(nil)
+	assert.NoError(t, r.Renderer.RenderCodeScanningAlertsWithFormat(FormatAnnotations, alerts, nil))
+	assert.Equal(t, "db.go:12:3: error: CodeQL go/sql-injection: query built from user input\n", r.Stdout.String())
+}
+
+func TestSecretScanningAlertLocationAnnotation(t *testing.T) {
+	a, ok := SecretScanningAlertLocationAnnotation(&github.SecretScanningAlertLocation{
+		Type:    github.Ptr("commit"),
+		Details: &github.SecretScanningAlertLocationDetails{Path: github.Ptr(".env"), Startline: github.Ptr(2), EndLine: github.Ptr(2), CommitSHA: github.Ptr("abc")},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "error", a.Level)
+	assert.Equal(t, ".env", a.Properties.File)
+	assert.Equal(t, 2, a.Properties.Line)
+
+	_, ok = SecretScanningAlertLocationAnnotation(&github.SecretScanningAlertLocation{Type: github.Ptr("issue_body")})
+	assert.False(t, ok)
+}
+
+func TestPullRequestCommentAnnotation(t *testing.T) {
+	a, ok := PullRequestCommentAnnotation(&github.PullRequestComment{
+		Path:      github.Ptr("main.go"),
+		StartLine: github.Ptr(4),
+		Line:      github.Ptr(8),
+		Body:      github.Ptr("nit"),
+		User:      &github.User{Login: github.Ptr("octocat")},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "notice", a.Level)
+	assert.Equal(t, 4, a.Properties.Line)
+	assert.Equal(t, 8, a.Properties.EndLine)
+	assert.Equal(t, "Review comment by octocat", a.Properties.Title)
+
+	outdated, ok := PullRequestCommentAnnotation(&github.PullRequestComment{Path: github.Ptr("main.go"), OriginalLine: github.Ptr(5)})
+	assert.True(t, ok)
+	assert.Equal(t, 5, outdated.Properties.Line)
+}
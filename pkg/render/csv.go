@@ -0,0 +1,15 @@
+package render
+
+import "encoding/csv"
+
+// renderCSV writes the header and rows as CSV to the output stream
+func (r *Renderer) renderCSV(headers []string, rows [][]string) error {
+	w := csv.NewWriter(r.IO.Out)
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
@@ -0,0 +1,153 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type deprecationRuleSummaryFieldGetter func(summary *gh.DeprecationRuleSummary) string
+type deprecationRuleSummaryFieldGetters struct {
+	Func map[string]deprecationRuleSummaryFieldGetter
+}
+
+func NewDeprecationRuleSummaryFieldGetters() *deprecationRuleSummaryFieldGetters {
+	return &deprecationRuleSummaryFieldGetters{
+		Func: map[string]deprecationRuleSummaryFieldGetter{
+			"RULE": func(summary *gh.DeprecationRuleSummary) string {
+				return summary.RuleID
+			},
+			"KIND": func(summary *gh.DeprecationRuleSummary) string {
+				return summary.Kind
+			},
+			"DESCRIPTION": func(summary *gh.DeprecationRuleSummary) string {
+				return summary.Description
+			},
+			"REPLACEMENT": func(summary *gh.DeprecationRuleSummary) string {
+				return summary.Replacement
+			},
+			"DATE": func(summary *gh.DeprecationRuleSummary) string {
+				return summary.Date
+			},
+			"COUNT": func(summary *gh.DeprecationRuleSummary) string {
+				return ToString(summary.Count)
+			},
+			"REPOSITORIES": func(summary *gh.DeprecationRuleSummary) string {
+				return ToString(len(summary.Repositories))
+			},
+			"FILES": func(summary *gh.DeprecationRuleSummary) string {
+				return strings.Join(summary.Files, "\n")
+			},
+		},
+	}
+}
+
+func (u *deprecationRuleSummaryFieldGetters) GetField(summary *gh.DeprecationRuleSummary, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(summary)
+	}
+	return ""
+}
+
+type deprecationFindingFieldGetter func(finding *gh.DeprecationFinding) string
+type deprecationFindingFieldGetters struct {
+	Func map[string]deprecationFindingFieldGetter
+}
+
+func NewDeprecationFindingFieldGetters() *deprecationFindingFieldGetters {
+	return &deprecationFindingFieldGetters{
+		Func: map[string]deprecationFindingFieldGetter{
+			"RULE": func(finding *gh.DeprecationFinding) string {
+				return finding.RuleID
+			},
+			"KIND": func(finding *gh.DeprecationFinding) string {
+				return finding.Kind
+			},
+			"REPOSITORY": func(finding *gh.DeprecationFinding) string {
+				return finding.Repository
+			},
+			"PATH": func(finding *gh.DeprecationFinding) string {
+				return finding.Path
+			},
+			"LINE": func(finding *gh.DeprecationFinding) string {
+				return ToString(finding.Line)
+			},
+			"JOB": func(finding *gh.DeprecationFinding) string {
+				return finding.JobID
+			},
+			"MATCH": func(finding *gh.DeprecationFinding) string {
+				return finding.Match
+			},
+			"REPLACEMENT": func(finding *gh.DeprecationFinding) string {
+				return finding.Replacement
+			},
+		},
+	}
+}
+
+func (u *deprecationFindingFieldGetters) GetField(finding *gh.DeprecationFinding, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(finding)
+	}
+	return ""
+}
+
+// RenderDeprecationReport renders the per-rule summary of a deprecation migration report in a table format.
+func (r *Renderer) RenderDeprecationReport(report *gh.DeprecationReport, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(report)
+	}
+
+	if len(report.Summaries) == 0 {
+		r.writeLine("No deprecated usages found.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"RULE", "COUNT", "REPOSITORIES", "REPLACEMENT", "FILES"}
+	}
+
+	getter := NewDeprecationRuleSummaryFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, summary := range report.Summaries {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(summary, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
+
+// RenderDeprecationFindings renders every deprecated usage in a table format.
+func (r *Renderer) RenderDeprecationFindings(findings []*gh.DeprecationFinding, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(findings)
+	}
+
+	if len(findings) == 0 {
+		r.writeLine("No deprecated usages found.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"REPOSITORY", "PATH", "LINE", "JOB", "RULE", "MATCH", "REPLACEMENT"}
+	}
+
+	getter := NewDeprecationFindingFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, finding := range findings {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(finding, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
@@ -279,3 +279,16 @@ func TestMermaidNodeID_NoCollision(t *testing.T) {
 			"mermaidNodeID(%q) == mermaidNodeID(%q) == %q", pair[0], pair[1], id1)
 	}
 }
+
+func TestRenderMermaidGraphEdge_WorkflowJobs(t *testing.T) {
+	sr := NewStringRenderer(nil)
+	edges := []gh.GraphEdge{
+		{From: gh.WorkflowNode{Path: ".github/workflows/ci.yml"}, To: gh.WorkflowJobNode{Path: ".github/workflows/ci.yml", JobID: "build"}},
+		{From: gh.WorkflowJobNode{Path: ".github/workflows/ci.yml", JobID: "build"}, To: gh.WorkflowJobNode{Path: ".github/workflows/ci.yml", JobID: "test"}},
+	}
+	assert.NoError(t, sr.Renderer.RenderMermaidGraphEdge(edges))
+	got := sr.Stdout.String()
+	assert.Contains(t, got, `["ci.yml:build"] --> `)
+	assert.Contains(t, got, `["ci.yml:test"]`)
+	assert.Contains(t, got, `[".github/workflows/ci.yml"]`)
+}
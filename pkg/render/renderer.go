@@ -3,6 +3,7 @@ package render
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"time"
@@ -19,6 +20,7 @@ type Renderer struct {
 	IO       *iostreams.IOStreams
 	Color    bool
 	exporter cmdutil.Exporter
+	markdown bool
 }
 
 var defaultTimeFormat = "2006-01-02 15:04:05"
@@ -77,6 +79,32 @@ func NewFileRenderer(file *os.File, ex cmdutil.Exporter) *Renderer {
 	}
 }
 
+// NewMarkdownRenderer creates a Renderer that outputs to w with tables in markdown format,
+// e.g. for writing into an actions.Summary
+func NewMarkdownRenderer(w io.Writer, ex cmdutil.Exporter) *Renderer {
+	io := iostreams.System()
+	io.SetStdoutTTY(false)
+	io.SetStderrTTY(false)
+	io.Out = &writerWithFd{Writer: w, fd: ^uintptr(0)}
+	io.SetColorEnabled(false)
+	return &Renderer{
+		IO:       io,
+		exporter: ex,
+		markdown: true,
+	}
+}
+
+// writerWithFd adapts an io.Writer to the Fd-carrying writer IOStreams expects.
+// The invalid descriptor makes terminal detection treat it as a non-TTY.
+type writerWithFd struct {
+	io.Writer
+	fd uintptr
+}
+
+func (w *writerWithFd) Fd() uintptr {
+	return w.fd
+}
+
 // SetColor sets the Color field based on the provided color flag
 func (r *Renderer) SetColor(colorFlag string) {
 	switch colorFlag {
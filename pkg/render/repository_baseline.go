@@ -0,0 +1,77 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type repositoryBaselineResultFieldGetter func(result *gh.RepositoryBaselineResult) string
+type repositoryBaselineResultFieldGetters struct {
+	Func map[string]repositoryBaselineResultFieldGetter
+}
+
+func NewRepositoryBaselineResultFieldGetters() *repositoryBaselineResultFieldGetters {
+	return &repositoryBaselineResultFieldGetters{
+		Func: map[string]repositoryBaselineResultFieldGetter{
+			"REPOSITORY": func(result *gh.RepositoryBaselineResult) string {
+				return result.Repository
+			},
+			"SETTING": func(result *gh.RepositoryBaselineResult) string {
+				return result.Setting
+			},
+			"EXPECTED": func(result *gh.RepositoryBaselineResult) string {
+				return result.Expected
+			},
+			"ACTUAL": func(result *gh.RepositoryBaselineResult) string {
+				return result.Actual
+			},
+			"COMPLIANT": func(result *gh.RepositoryBaselineResult) string {
+				return ToString(result.Compliant)
+			},
+			"FIXED": func(result *gh.RepositoryBaselineResult) string {
+				return ToString(result.Fixed)
+			},
+			"ERROR": func(result *gh.RepositoryBaselineResult) string {
+				return result.Error
+			},
+		},
+	}
+}
+
+func (u *repositoryBaselineResultFieldGetters) GetField(result *gh.RepositoryBaselineResult, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(result)
+	}
+	return ""
+}
+
+// RenderRepositoryBaselineResults renders repository baseline compliance results in a table format.
+func (r *Renderer) RenderRepositoryBaselineResults(results []*gh.RepositoryBaselineResult, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(results)
+	}
+
+	if len(results) == 0 {
+		r.writeLine("No baseline results.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"REPOSITORY", "SETTING", "EXPECTED", "ACTUAL", "COMPLIANT", "FIXED", "ERROR"}
+	}
+
+	getter := NewRepositoryBaselineResultFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, result := range results {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(result, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
@@ -0,0 +1,95 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type rulesetMigrationReferenceFieldGetter func(ref *gh.RulesetMigrationReference) string
+type rulesetMigrationReferenceFieldGetters struct {
+	Func map[string]rulesetMigrationReferenceFieldGetter
+}
+
+func NewRulesetMigrationReferenceFieldGetters() *rulesetMigrationReferenceFieldGetters {
+	return &rulesetMigrationReferenceFieldGetters{
+		Func: map[string]rulesetMigrationReferenceFieldGetter{
+			"KIND": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Kind
+			},
+			"TYPE": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Type
+			},
+			"SOURCE": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Source
+			},
+			"SOURCE_ID": func(ref *gh.RulesetMigrationReference) string {
+				return ToString(ref.SourceID)
+			},
+			"STATUS": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Status
+			},
+			"DESTINATION": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Destination
+			},
+			"DESTINATION_ID": func(ref *gh.RulesetMigrationReference) string {
+				return ToString(ref.DestinationID)
+			},
+			"REASON": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Reason
+			},
+			"SUGGESTION": func(ref *gh.RulesetMigrationReference) string {
+				return ref.Suggestion
+			},
+		},
+	}
+}
+
+func (u *rulesetMigrationReferenceFieldGetters) GetField(ref *gh.RulesetMigrationReference, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(ref)
+	}
+	return ""
+}
+
+// RenderRulesetMigrationReport renders the pre-flight validation report of a ruleset migration.
+// When unresolvedOnly is true, only references that could not be resolved are listed.
+func (r *Renderer) RenderRulesetMigrationReport(report *gh.RulesetMigrationReport, headers []string, unresolvedOnly bool) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(report)
+	}
+
+	refs := report.References
+	if unresolvedOnly {
+		refs = report.Unresolved()
+	}
+
+	r.writeLine(fmt.Sprintf("Ruleset: %s -> %s", report.Ruleset, report.Destination))
+	if len(refs) == 0 {
+		if unresolvedOnly {
+			r.writeLine("All references are resolvable.")
+		} else {
+			r.writeLine("No references.")
+		}
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"KIND", "TYPE", "SOURCE", "STATUS", "DESTINATION", "REASON", "SUGGESTION"}
+	}
+
+	getter := NewRulesetMigrationReferenceFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, ref := range refs {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(ref, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
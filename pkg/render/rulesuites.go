@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 	"github.com/srz-zumix/go-gh-extension/pkg/gh/client"
 )
 
@@ -123,3 +124,122 @@ func (r *Renderer) RenderRuleSuiteDetail(ruleSuite *client.RuleSuite) error {
 	}
 	return nil
 }
+
+type ruleSuiteStatsFieldGetter func(stats *gh.RuleSuiteStats) string
+type ruleSuiteStatsFieldGetters struct {
+	Func map[string]ruleSuiteStatsFieldGetter
+}
+
+func NewRuleSuiteStatsFieldGetters() *ruleSuiteStatsFieldGetters {
+	return &ruleSuiteStatsFieldGetters{
+		Func: map[string]ruleSuiteStatsFieldGetter{
+			"RULESET": func(stats *gh.RuleSuiteStats) string {
+				return stats.Ruleset
+			},
+			"RULE_TYPE": func(stats *gh.RuleSuiteStats) string {
+				return stats.RuleType
+			},
+			"ACTOR": func(stats *gh.RuleSuiteStats) string {
+				return stats.Actor
+			},
+			"REF": func(stats *gh.RuleSuiteStats) string {
+				return strings.TrimPrefix(stats.Ref, "refs/heads/")
+			},
+			"REPOSITORY": func(stats *gh.RuleSuiteStats) string {
+				return stats.Repository
+			},
+			"SUITES": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.Suites)
+			},
+			"TOTAL": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.Total)
+			},
+			"PASS": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.Pass)
+			},
+			"FAIL": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.Fail)
+			},
+			"BYPASS": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.Bypass)
+			},
+			"EVALUATE": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.Evaluate)
+			},
+			"EVALUATE_FAIL": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.EvaluateFail)
+			},
+			"READY_TO_ACTIVATE": func(stats *gh.RuleSuiteStats) string {
+				if stats.Evaluate == 0 {
+					return "-"
+				}
+				return ToString(stats.ReadyToActivate)
+			},
+			"FIRST_AT": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.FirstAt)
+			},
+			"LAST_AT": func(stats *gh.RuleSuiteStats) string {
+				return ToString(stats.LastAt)
+			},
+		},
+	}
+}
+
+func (u *ruleSuiteStatsFieldGetters) GetField(stats *gh.RuleSuiteStats, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(stats)
+	}
+	return ""
+}
+
+// RenderRuleSuiteStats renders aggregated rule suite statistics in a table format.
+// When headers is empty, the grouped dimensions present in the stats are shown followed by the counters.
+func (r *Renderer) RenderRuleSuiteStats(stats []*gh.RuleSuiteStats, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(stats)
+	}
+
+	if len(stats) == 0 {
+		r.writeLine("No rule suites.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = ruleSuiteStatsDefaultHeaders(stats)
+	}
+
+	getter := NewRuleSuiteStatsFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, s := range stats {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(s, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
+
+func ruleSuiteStatsDefaultHeaders(stats []*gh.RuleSuiteStats) []string {
+	first := stats[0]
+	headers := []string{}
+	if first.Repository != "" {
+		headers = append(headers, "REPOSITORY")
+	}
+	if first.Ruleset != "" {
+		headers = append(headers, "RULESET")
+	}
+	if first.RuleType != "" {
+		headers = append(headers, "RULE_TYPE")
+	}
+	if first.Ref != "" {
+		headers = append(headers, "REF")
+	}
+	if first.Actor != "" {
+		headers = append(headers, "ACTOR")
+	}
+	return append(headers, "SUITES", "PASS", "FAIL", "BYPASS", "EVALUATE_FAIL", "READY_TO_ACTIVATE")
+}
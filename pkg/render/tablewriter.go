@@ -2,6 +2,7 @@ package render
 
 import (
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
 	"github.com/olekukonko/tablewriter/tw"
 )
 
@@ -33,7 +34,11 @@ func (s *TableWriter) Render() error {
 
 // newTableWriter creates a TableWriter with the given column headers.
 func (r *Renderer) newTableWriter(header []string) *TableWriter {
-	table := tablewriter.NewTable(r.IO.Out)
+	var opts []tablewriter.Option
+	if r.markdown {
+		opts = append(opts, tablewriter.WithRenderer(renderer.NewMarkdown()))
+	}
+	table := tablewriter.NewTable(r.IO.Out, opts...)
 	table.Configure(func(config *tablewriter.Config) {
 		config.Row.Alignment.Global = tw.AlignLeft
 	})
@@ -0,0 +1,18 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMarkdownRenderer(t *testing.T) {
+	var buf strings.Builder
+	r := NewMarkdownRenderer(&buf, nil)
+	err := r.RenderWorkflowRunStats([]*gh.WorkflowRunStats{{Workflow: "CI", Runs: 2}}, []string{"WORKFLOW", "RUNS"})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "| WORKFLOW | RUNS |")
+	assert.Contains(t, buf.String(), "CI")
+}
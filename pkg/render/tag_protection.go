@@ -2,8 +2,10 @@ package render
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
 )
 
 // RenderTagProtections renders a list of tag protections in a table format.
@@ -43,3 +45,54 @@ func (r *Renderer) RenderTagProtection(tagProtection *github.TagProtection) erro
 	table.Append([]string{"Pattern", ToString(tagProtection.Pattern)})
 	return table.Render()
 }
+
+// RenderTagProtectionConversion renders the converted tag ruleset patterns and, per existing tag,
+// whether it is matched by the tag protections and by the converted ruleset.
+func (r *Renderer) RenderTagProtectionConversion(conversion *gh.TagProtectionConversion) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(conversion)
+	}
+
+	r.writeLine(fmt.Sprintf("Ruleset: %s", conversion.Ruleset.Name))
+	r.writeLine(fmt.Sprintf("Equivalent: %s", ToString(conversion.Equivalent())))
+	r.writeLine("")
+
+	patterns := r.newTableWriter([]string{"TAG_PROTECTION", "RULESET_PATTERN"})
+	for i, tagProtection := range conversion.TagProtections {
+		if tagProtection == nil {
+			continue
+		}
+		pattern := ""
+		if i < len(conversion.RulesetPatterns) {
+			pattern = conversion.RulesetPatterns[i]
+		}
+		patterns.Append([]string{ToString(tagProtection.Pattern), pattern})
+	}
+	if err := patterns.Render(); err != nil {
+		return err
+	}
+
+	tags := slices.Clone(conversion.ProtectedTags)
+	for _, tag := range conversion.RulesetTags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		r.writeLine("")
+		r.writeLine("No existing tags are matched.")
+		return nil
+	}
+	slices.Sort(tags)
+
+	r.writeLine("")
+	table := r.newTableWriter([]string{"TAG", "TAG_PROTECTION", "RULESET"})
+	for _, tag := range tags {
+		table.Append([]string{
+			tag,
+			ToString(slices.Contains(conversion.ProtectedTags, tag)),
+			ToString(slices.Contains(conversion.RulesetTags, tag)),
+		})
+	}
+	return table.Render()
+}
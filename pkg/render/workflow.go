@@ -19,7 +19,7 @@ const (
 )
 
 // WorkflowDependencyFields lists all available field names for ActionReference table rendering.
-var WorkflowDependencyFields = []string{"Name", "Version", "Owner", "Repo", "Path", "Raw", "Using", "Node_Version", "Job"}
+var WorkflowDependencyFields = []string{"Name", "Version", "Owner", "Repo", "Path", "Raw", "Using", "Node_Version", "Job", "Host", "Resolution", "Reason"}
 
 // WorkflowDependencyFieldGetter defines a function to get a field value from parser.ActionReference
 type WorkflowDependencyFieldGetter func(ref *parser.ActionReference) string
@@ -60,6 +60,15 @@ func NewWorkflowDependencyFieldGetters() *WorkflowDependencyFieldGetters {
 			"JOB": func(ref *parser.ActionReference) string {
 				return ref.JobID
 			},
+			"HOST": func(ref *parser.ActionReference) string {
+				return ref.Host
+			},
+			"RESOLUTION": func(ref *parser.ActionReference) string {
+				return ref.Resolution
+			},
+			"REASON": func(ref *parser.ActionReference) string {
+				return ref.Unresolvable
+			},
 		},
 	}
 }
@@ -173,12 +182,19 @@ func (r *Renderer) RenderWorkflowDependenciesWithFormat(format string, deps []pa
 }
 
 // actionTreeLabel returns a human-readable label for an action reference node.
-// If the action has a Using field, it is appended in brackets (e.g. "actions/checkout@v4 [node20]").
+// If the action has a Using field, it is appended in brackets (e.g. "actions/checkout@v4 [node20]"),
+// followed by the resolution when the action was fetched through the fallback or could not be resolved.
 func actionTreeLabel(action parser.ActionReference) string {
 	label := action.VersionedName()
 	if action.Using != "" {
 		label += " [" + action.Using + "]"
 	}
+	switch action.Resolution {
+	case parser.ActionResolutionFallback:
+		label += " (via " + action.Host + ")"
+	case parser.ActionResolutionNotFound, parser.ActionResolutionOffline, parser.ActionResolutionUnresolvable:
+		label += " (" + action.Resolution + ")"
+	}
 	return label
 }
 
@@ -0,0 +1,25 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+// RenderWorkflowDispatchResult writes the dispatched run with its conclusion and the jobs that did not succeed
+func (r *Renderer) RenderWorkflowDispatchResult(result *gh.WorkflowDispatchResult) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(result)
+	}
+	run := result.Run
+	state := run.GetStatus()
+	if conclusion := result.Conclusion(); conclusion != "" {
+		state = conclusion
+	}
+	r.writeLine(fmt.Sprintf("%s #%d: %s", run.GetName(), run.GetRunNumber(), state))
+	r.writeLine(run.GetHTMLURL())
+	for _, job := range result.FailedJobs {
+		r.writeLine(fmt.Sprintf("  %s: %s %s", job.GetName(), job.GetConclusion(), job.GetHTMLURL()))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderWorkflowDispatchResult(t *testing.T) {
+	r := NewStringRenderer(nil)
+	result := &gh.WorkflowDispatchResult{
+		Run: &github.WorkflowRun{
+			Name:       github.Ptr("Deploy"),
+			RunNumber:  github.Ptr(7),
+			Status:     github.Ptr("completed"),
+			Conclusion: github.Ptr("failure"),
+			HTMLURL:    github.Ptr("https://github.com/o/r/actions/runs/1"),
+		},
+		FailedJobs: []*github.WorkflowJob{
+			{Name: github.Ptr("deploy"), Conclusion: github.Ptr("failure"), HTMLURL: github.Ptr("https://github.com/o/r/actions/runs/1/job/2")},
+		},
+	}
+	assert.NoError(t, r.Renderer.RenderWorkflowDispatchResult(result))
+	assert.Equal(t, "Deploy #7: failure\nhttps://github.com/o/r/actions/runs/1\n  deploy: failure https://github.com/o/r/actions/runs/1/job/2\n", r.Stdout.String())
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type workflowLintFindingFieldGetter func(finding *gh.WorkflowLintFinding) string
+type workflowLintFindingFieldGetters struct {
+	Func map[string]workflowLintFindingFieldGetter
+}
+
+func NewWorkflowLintFindingFieldGetters() *workflowLintFindingFieldGetters {
+	return &workflowLintFindingFieldGetters{
+		Func: map[string]workflowLintFindingFieldGetter{
+			"REPOSITORY": func(finding *gh.WorkflowLintFinding) string {
+				return finding.Repository
+			},
+			"PATH": func(finding *gh.WorkflowLintFinding) string {
+				return finding.Path
+			},
+			"LINE": func(finding *gh.WorkflowLintFinding) string {
+				return ToString(finding.Line)
+			},
+			"COLUMN": func(finding *gh.WorkflowLintFinding) string {
+				return ToString(finding.Column)
+			},
+			"JOB": func(finding *gh.WorkflowLintFinding) string {
+				return finding.JobID
+			},
+			"RULE": func(finding *gh.WorkflowLintFinding) string {
+				return finding.RuleID
+			},
+			"SEVERITY": func(finding *gh.WorkflowLintFinding) string {
+				return finding.Severity
+			},
+			"MESSAGE": func(finding *gh.WorkflowLintFinding) string {
+				return finding.Message
+			},
+		},
+	}
+}
+
+func (u *workflowLintFindingFieldGetters) GetField(finding *gh.WorkflowLintFinding, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(finding)
+	}
+	return ""
+}
+
+// RenderWorkflowLintFindings renders workflow lint findings in a table format.
+func (r *Renderer) RenderWorkflowLintFindings(findings []*gh.WorkflowLintFinding, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(findings)
+	}
+
+	if len(findings) == 0 {
+		r.writeLine("No workflow issues found.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"PATH", "LINE", "SEVERITY", "RULE", "JOB", "MESSAGE"}
+	}
+
+	getter := NewWorkflowLintFindingFieldGetters()
+	table := r.newTableWriter(headers)
+
+	for _, finding := range findings {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(finding, header)
+		}
+		table.Append(row)
+	}
+
+	return table.Render()
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+// RenderWorkflowRunLogDiff writes the unified diffs of the steps whose normalized logs differ between
+// the base and head runs. Unchanged jobs and steps are omitted.
+func (r *Renderer) RenderWorkflowRunLogDiff(diff *gh.WorkflowRunLogDiff) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(diff)
+	}
+	r.writeLine(fmt.Sprintf("base: %s #%d (%d) %s", diff.Base.GetName(), diff.Base.GetRunNumber(), diff.Base.GetID(), diff.Base.GetHeadSHA()))
+	r.writeLine(fmt.Sprintf("head: %s #%d (%d) %s", diff.Head.GetName(), diff.Head.GetRunNumber(), diff.Head.GetID(), diff.Head.GetHeadSHA()))
+	changed := diff.Changed()
+	if len(changed) == 0 {
+		r.writeLine("No differences in the job logs")
+		return nil
+	}
+	for _, job := range changed {
+		r.writeLine("")
+		r.writeLine(fmt.Sprintf("=== %s (%s)", job.JobName, job.Status))
+		for _, step := range job.Steps {
+			if step.Status == actions.LogDiffUnchanged {
+				continue
+			}
+			if step.Diff == "" {
+				r.writeLine(fmt.Sprintf("--- step %q (%s)", step.StepName, step.Status))
+				continue
+			}
+			r.writeLine(r.colorizeUnifiedDiff(strings.TrimSuffix(step.Diff, "\n")))
+		}
+	}
+	return nil
+}
+
+func (r *Renderer) colorizeUnifiedDiff(diff string) string {
+	if !r.Color {
+		return diff
+	}
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = color.New(color.Bold).Sprint(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = color.CyanString(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = color.GreenString(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = color.RedString(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderWorkflowRunLogDiff(t *testing.T) {
+	r := NewStringRenderer(nil)
+	diff := &gh.WorkflowRunLogDiff{
+		Base: &github.WorkflowRun{ID: github.Ptr(int64(1)), Name: github.Ptr("CI"), RunNumber: github.Ptr(10), HeadSHA: github.Ptr("aaa")},
+		Head: &github.WorkflowRun{ID: github.Ptr(int64(2)), Name: github.Ptr("CI"), RunNumber: github.Ptr(11), HeadSHA: github.Ptr("bbb")},
+		LogDiff: &actions.LogDiff{Jobs: []*actions.JobLogDiff{
+			{JobName: "lint", Status: actions.LogDiffUnchanged},
+			{JobName: "test", Status: actions.LogDiffChanged, Steps: []*actions.StepLogDiff{
+				{StepName: "Set up job", Status: actions.LogDiffUnchanged},
+				{StepName: "Test", Status: actions.LogDiffChanged, Diff: "--- base/Test\n+++ head/Test\n@@ -1 +1 @@\n-ok\n+FAIL\n"},
+				{StepName: "Upload", Status: actions.LogDiffAdded},
+			}},
+		}},
+	}
+	assert.NoError(t, r.Renderer.RenderWorkflowRunLogDiff(diff))
+	assert.Equal(t, "base: CI #10 (1) aaa\n"+
+		"head: CI #11 (2) bbb\n"+
+		"\n"+
+		"=== test (changed)\n"+
+		"--- base/Test\n+++ head/Test\n@@ -1 +1 @@\n-ok\n+FAIL\n"+
+		"--- step \"Upload\" (added)\n", r.Stdout.String())
+}
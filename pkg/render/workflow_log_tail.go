@@ -0,0 +1,37 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+// workflowLogTailExport is the exported form of a gh.WorkflowLogTailEvent
+type workflowLogTailExport struct {
+	JobID      int64  `json:"job_id"`
+	JobName    string `json:"job_name"`
+	StepNumber int    `json:"step_number"`
+	StepName   string `json:"step_name"`
+	Content    string `json:"content"`
+}
+
+// RenderWorkflowLogTailEvent writes the log of a completed step under a "job / step" header.
+// It is meant to be passed as the callback of gh.TailWorkflowRunLogs.
+func (r *Renderer) RenderWorkflowLogTailEvent(event *gh.WorkflowLogTailEvent) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(workflowLogTailExport{
+			JobID:      event.Job.GetID(),
+			JobName:    event.Job.GetName(),
+			StepNumber: event.Step.StepNumber,
+			StepName:   event.Step.StepName,
+			Content:    string(event.Content),
+		})
+	}
+	r.writeLine(fmt.Sprintf("==> %s / %d %s", event.Job.GetName(), event.Step.StepNumber, event.Step.StepName))
+	content := strings.TrimRight(strings.ReplaceAll(string(event.Content), "\r\n", "\n"), "\n")
+	if content != "" {
+		r.writeLine(strings.TrimPrefix(content, "\xef\xbb\xbf"))
+	}
+	return nil
+}
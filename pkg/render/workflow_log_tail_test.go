@@ -0,0 +1,21 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v90/github"
+	"github.com/srz-zumix/go-gh-extension/pkg/actions"
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderWorkflowLogTailEvent(t *testing.T) {
+	r := NewStringRenderer(nil)
+	event := &gh.WorkflowLogTailEvent{
+		Job:     &github.WorkflowJob{Name: github.Ptr("build")},
+		Step:    &actions.StepLog{StepNumber: 2, StepName: "Run make"},
+		Content: []byte("\xef\xbb\xbfline 1\r\nline 2\r\n"),
+	}
+	assert.NoError(t, r.Renderer.RenderWorkflowLogTailEvent(event))
+	assert.Equal(t, "==> build / 2 Run make\nline 1\nline 2\n", r.Stdout.String())
+}
@@ -0,0 +1,270 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type workflowRunStatsFieldGetter func(stats *gh.WorkflowRunStats) string
+type workflowRunStatsFieldGetters struct {
+	Func map[string]workflowRunStatsFieldGetter
+}
+
+// NewWorkflowRunStatsFieldGetters returns getters that format durations and rates for display
+func NewWorkflowRunStatsFieldGetters() *workflowRunStatsFieldGetters {
+	return newWorkflowRunStatsFieldGetters(formatAnalyticsDuration, formatAnalyticsRate)
+}
+
+// newWorkflowRunStatsCSVFieldGetters returns getters that keep durations in seconds and rates as fractions
+func newWorkflowRunStatsCSVFieldGetters() *workflowRunStatsFieldGetters {
+	return newWorkflowRunStatsFieldGetters(formatAnalyticsNumber, formatAnalyticsNumber)
+}
+
+func newWorkflowRunStatsFieldGetters(duration func(float64) string, rate func(float64) string) *workflowRunStatsFieldGetters {
+	return &workflowRunStatsFieldGetters{
+		Func: map[string]workflowRunStatsFieldGetter{
+			"WORKFLOW": func(stats *gh.WorkflowRunStats) string {
+				return stats.Workflow
+			},
+			"JOB": func(stats *gh.WorkflowRunStats) string {
+				return stats.Job
+			},
+			"RUNS": func(stats *gh.WorkflowRunStats) string {
+				return ToString(stats.Runs)
+			},
+			"SUCCESS": func(stats *gh.WorkflowRunStats) string {
+				return ToString(stats.Success)
+			},
+			"FAILURE": func(stats *gh.WorkflowRunStats) string {
+				return ToString(stats.Failure)
+			},
+			"CANCELLED": func(stats *gh.WorkflowRunStats) string {
+				return ToString(stats.Cancelled)
+			},
+			"RERUNS": func(stats *gh.WorkflowRunStats) string {
+				return ToString(stats.Reruns)
+			},
+			"FLAKY": func(stats *gh.WorkflowRunStats) string {
+				return ToString(stats.Flaky)
+			},
+			"SUCCESS_RATE": func(stats *gh.WorkflowRunStats) string {
+				return rate(stats.SuccessRate)
+			},
+			"FAILURE_RATE": func(stats *gh.WorkflowRunStats) string {
+				return rate(stats.FailureRate)
+			},
+			"CANCEL_RATE": func(stats *gh.WorkflowRunStats) string {
+				return rate(stats.CancelRate)
+			},
+			"RERUN_RATE": func(stats *gh.WorkflowRunStats) string {
+				return rate(stats.RerunRate)
+			},
+			"DURATION_P50": func(stats *gh.WorkflowRunStats) string {
+				return duration(stats.DurationP50)
+			},
+			"DURATION_P95": func(stats *gh.WorkflowRunStats) string {
+				return duration(stats.DurationP95)
+			},
+			"QUEUE_P50": func(stats *gh.WorkflowRunStats) string {
+				return duration(stats.QueueP50)
+			},
+			"QUEUE_P95": func(stats *gh.WorkflowRunStats) string {
+				return duration(stats.QueueP95)
+			},
+		},
+	}
+}
+
+func (u *workflowRunStatsFieldGetters) GetField(stats *gh.WorkflowRunStats, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(stats)
+	}
+	return ""
+}
+
+func formatAnalyticsDuration(seconds float64) string {
+	return (time.Duration(seconds * float64(time.Second))).Round(time.Second).String()
+}
+
+func formatAnalyticsRate(rate float64) string {
+	return fmt.Sprintf("%.1f%%", rate*100)
+}
+
+func formatAnalyticsNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var workflowRunStatsDefaultHeaders = []string{"WORKFLOW", "RUNS", "SUCCESS_RATE", "FAILURE_RATE", "CANCEL_RATE", "RERUN_RATE", "DURATION_P50", "DURATION_P95", "QUEUE_P50", "QUEUE_P95"}
+var workflowJobStatsDefaultHeaders = []string{"WORKFLOW", "JOB", "RUNS", "FAILURE_RATE", "RERUN_RATE", "FLAKY", "DURATION_P50", "DURATION_P95", "QUEUE_P50", "QUEUE_P95"}
+var workflowFlakyJobHeaders = []string{"WORKFLOW", "JOB", "HEAD_SHA", "FAILURES", "RUNS"}
+var workflowBillableHeaders = []string{"WORKFLOW", "OS", "RUNS", "JOBS", "MINUTES"}
+
+// RenderWorkflowRunStats renders per-workflow or per-job statistics in a table format
+func (r *Renderer) RenderWorkflowRunStats(stats []*gh.WorkflowRunStats, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(stats)
+	}
+
+	if len(stats) == 0 {
+		r.writeLine("No workflow runs.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = workflowRunStatsHeaders(stats)
+	}
+
+	getter := NewWorkflowRunStatsFieldGetters()
+	table := r.newTableWriter(headers)
+	for _, s := range stats {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(s, header)
+		}
+		table.Append(row)
+	}
+	return table.Render()
+}
+
+// RenderWorkflowRunStatsCSV renders per-workflow or per-job statistics as CSV.
+// Durations are written in seconds and rates as fractions so that the output can be processed further.
+func (r *Renderer) RenderWorkflowRunStatsCSV(stats []*gh.WorkflowRunStats, headers []string) error {
+	if len(headers) == 0 {
+		headers = workflowRunStatsDefaultHeaders
+		if len(stats) > 0 {
+			headers = workflowRunStatsHeaders(stats)
+		}
+	}
+	getter := newWorkflowRunStatsCSVFieldGetters()
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(s, header)
+		}
+		rows = append(rows, row)
+	}
+	return r.renderCSV(headers, rows)
+}
+
+func workflowRunStatsHeaders(stats []*gh.WorkflowRunStats) []string {
+	if stats[0].Job != "" {
+		return workflowJobStatsDefaultHeaders
+	}
+	return workflowRunStatsDefaultHeaders
+}
+
+// RenderWorkflowFlakyJobs renders flaky jobs in a table format
+func (r *Renderer) RenderWorkflowFlakyJobs(jobs []*gh.WorkflowFlakyJob) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(jobs)
+	}
+
+	if len(jobs) == 0 {
+		r.writeLine("No flaky jobs.")
+		return nil
+	}
+
+	table := r.newTableWriter(workflowFlakyJobHeaders)
+	for _, job := range jobs {
+		runIDs := make([]string, len(job.RunIDs))
+		for i, id := range job.RunIDs {
+			runIDs[i] = ToString(id)
+		}
+		sha := job.HeadSHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		table.Append([]string{job.Workflow, job.Job, sha, ToString(job.Failures), strings.Join(runIDs, ", ")})
+	}
+	return table.Render()
+}
+
+// RenderWorkflowFlakyJobsCSV renders flaky jobs as CSV with the full head SHA
+func (r *Renderer) RenderWorkflowFlakyJobsCSV(jobs []*gh.WorkflowFlakyJob) error {
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		runIDs := make([]string, len(job.RunIDs))
+		for i, id := range job.RunIDs {
+			runIDs[i] = ToString(id)
+		}
+		rows = append(rows, []string{job.Workflow, job.Job, job.HeadSHA, ToString(job.Failures), strings.Join(runIDs, " ")})
+	}
+	return r.renderCSV(workflowFlakyJobHeaders, rows)
+}
+
+// RenderWorkflowBillable renders billable minutes per workflow and runner OS in a table format,
+// followed by the total per OS
+func (r *Renderer) RenderWorkflowBillable(billable []*gh.WorkflowBillableStats) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(billable)
+	}
+
+	if len(billable) == 0 {
+		r.writeLine("No billable usage.")
+		return nil
+	}
+
+	table := r.newTableWriter(workflowBillableHeaders)
+	totals := map[string]float64{}
+	for _, b := range billable {
+		table.Append([]string{b.Workflow, b.OS, ToString(b.Runs), ToString(b.Jobs), fmt.Sprintf("%.1f", b.Minutes)})
+		totals[b.OS] += b.Minutes
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	oses := make([]string, 0, len(totals))
+	for os := range totals {
+		oses = append(oses, os)
+	}
+	sort.Strings(oses)
+	for _, os := range oses {
+		r.writeLine(fmt.Sprintf("Total %s: %.1f minutes", os, totals[os]))
+	}
+	return nil
+}
+
+// RenderWorkflowBillableCSV renders billable minutes per workflow and runner OS as CSV.
+// Minutes are written unrounded and the per-OS totals are left out so that every line is a record.
+func (r *Renderer) RenderWorkflowBillableCSV(billable []*gh.WorkflowBillableStats) error {
+	rows := make([][]string, 0, len(billable))
+	for _, b := range billable {
+		rows = append(rows, []string{b.Workflow, b.OS, ToString(b.Runs), ToString(b.Jobs), formatAnalyticsNumber(b.Minutes)})
+	}
+	return r.renderCSV(workflowBillableHeaders, rows)
+}
+
+// RenderWorkflowRunAnalytics renders the workflow statistics followed by the job statistics,
+// flaky jobs and billable minutes when they were collected
+func (r *Renderer) RenderWorkflowRunAnalytics(analytics *gh.WorkflowRunAnalytics, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(analytics)
+	}
+
+	if err := r.RenderWorkflowRunStats(analytics.Workflows, headers); err != nil {
+		return err
+	}
+	if len(analytics.Jobs) > 0 {
+		r.writeLine("")
+		if err := r.RenderWorkflowRunStats(analytics.Jobs, nil); err != nil {
+			return err
+		}
+		r.writeLine("")
+		if err := r.RenderWorkflowFlakyJobs(analytics.FlakyJobs); err != nil {
+			return err
+		}
+	}
+	if len(analytics.Billable) > 0 {
+		r.writeLine("")
+		return r.RenderWorkflowBillable(analytics.Billable)
+	}
+	return nil
+}
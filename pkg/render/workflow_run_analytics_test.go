@@ -0,0 +1,45 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderWorkflowRunStatsCSV(t *testing.T) {
+	r := NewStringRenderer(nil)
+	stats := []*gh.WorkflowRunStats{
+		{Workflow: "CI, main", Runs: 4, FailureRate: 0.25, DurationP50: 90.5},
+	}
+	err := r.Renderer.RenderWorkflowRunStatsCSV(stats, []string{"WORKFLOW", "RUNS", "FAILURE_RATE", "DURATION_P50"})
+	assert.NoError(t, err)
+	assert.Equal(t, "WORKFLOW,RUNS,FAILURE_RATE,DURATION_P50\n\"CI, main\",4,0.25,90.5\n", r.Stdout.String())
+}
+
+func TestRenderWorkflowFlakyJobsCSV(t *testing.T) {
+	r := NewStringRenderer(nil)
+	jobs := []*gh.WorkflowFlakyJob{
+		{Workflow: "CI", Job: "test", HeadSHA: "0123456789abcdef", Failures: 2, RunIDs: []int64{10, 11}},
+	}
+	err := r.Renderer.RenderWorkflowFlakyJobsCSV(jobs)
+	assert.NoError(t, err)
+	assert.Equal(t, "WORKFLOW,JOB,HEAD_SHA,FAILURES,RUNS\nCI,test,0123456789abcdef,2,10 11\n", r.Stdout.String())
+}
+
+func TestRenderWorkflowBillableCSV(t *testing.T) {
+	r := NewStringRenderer(nil)
+	billable := []*gh.WorkflowBillableStats{
+		{Workflow: "CI", OS: "UBUNTU", Runs: 3, Jobs: 6, Minutes: 12.25},
+	}
+	err := r.Renderer.RenderWorkflowBillableCSV(billable)
+	assert.NoError(t, err)
+	assert.Equal(t, "WORKFLOW,OS,RUNS,JOBS,MINUTES\nCI,UBUNTU,3,6,12.25\n", r.Stdout.String())
+}
+
+func TestWorkflowRunStatsFieldGetters(t *testing.T) {
+	getter := NewWorkflowRunStatsFieldGetters()
+	stats := &gh.WorkflowRunStats{FailureRate: 0.125, QueueP95: 75.4}
+	assert.Equal(t, "12.5%", getter.GetField(stats, "failure_rate"))
+	assert.Equal(t, "1m15s", getter.GetField(stats, "QUEUE_P95"))
+}
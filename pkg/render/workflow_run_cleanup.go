@@ -0,0 +1,123 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+)
+
+type workflowRunCleanupFieldGetter func(item *gh.WorkflowRunCleanupItem) string
+type workflowRunCleanupFieldGetters struct {
+	Func map[string]workflowRunCleanupFieldGetter
+}
+
+// NewWorkflowRunCleanupFieldGetters returns getters for workflow run cleanup plan items
+func NewWorkflowRunCleanupFieldGetters() *workflowRunCleanupFieldGetters {
+	return &workflowRunCleanupFieldGetters{
+		Func: map[string]workflowRunCleanupFieldGetter{
+			"REPOSITORY": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.Repository
+			},
+			"ACTION": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.Action
+			},
+			"WORKFLOW": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.Workflow
+			},
+			"BRANCH": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.Branch
+			},
+			"RUN_ID": func(item *gh.WorkflowRunCleanupItem) string {
+				return ToString(item.RunID)
+			},
+			"RUN": func(item *gh.WorkflowRunCleanupItem) string {
+				return fmt.Sprintf("#%d", item.RunNumber)
+			},
+			"CONCLUSION": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.Conclusion
+			},
+			"CREATED_AT": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.CreatedAt.Format(time.DateOnly)
+			},
+			"ARTIFACT": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.ArtifactName
+			},
+			"REASON": func(item *gh.WorkflowRunCleanupItem) string {
+				return item.Reason
+			},
+			"SIZE": func(item *gh.WorkflowRunCleanupItem) string {
+				return formatByteSize(item.Bytes)
+			},
+			"RESULT": func(item *gh.WorkflowRunCleanupItem) string {
+				switch {
+				case item.Error != "":
+					return item.Error
+				case item.Done:
+					return "done"
+				}
+				return "planned"
+			},
+		},
+	}
+}
+
+func (u *workflowRunCleanupFieldGetters) GetField(item *gh.WorkflowRunCleanupItem, field string) string {
+	field = strings.ToUpper(field)
+	if getter, ok := u.Func[field]; ok {
+		return getter(item)
+	}
+	return ""
+}
+
+// formatByteSize formats a byte count with binary units, e.g. "1.5 MiB"
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+var workflowRunCleanupDefaultHeaders = []string{"REPOSITORY", "ACTION", "WORKFLOW", "BRANCH", "RUN", "CONCLUSION", "CREATED_AT", "ARTIFACT", "REASON", "SIZE"}
+
+// RenderWorkflowRunCleanupPlan renders the items of a cleanup plan in a table format, followed by
+// the number of deletions per action and the estimated storage reclaimed
+func (r *Renderer) RenderWorkflowRunCleanupPlan(plan *gh.WorkflowRunCleanupPlan, headers []string) error {
+	if r.exporter != nil {
+		return r.RenderExportedData(plan)
+	}
+
+	if len(plan.Items) == 0 {
+		r.writeLine("Nothing to clean up.")
+		return nil
+	}
+
+	if len(headers) == 0 {
+		headers = workflowRunCleanupDefaultHeaders
+	}
+	getter := NewWorkflowRunCleanupFieldGetters()
+	table := r.newTableWriter(headers)
+	for _, item := range plan.Items {
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = getter.GetField(item, header)
+		}
+		table.Append(row)
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+	r.writeLine(fmt.Sprintf("%d runs, %d logs and %d artifacts to delete, reclaiming about %s",
+		plan.Count(gh.WorkflowRunCleanupDeleteRun),
+		plan.Count(gh.WorkflowRunCleanupDeleteLogs),
+		plan.Count(gh.WorkflowRunCleanupDeleteArtifact),
+		formatByteSize(plan.ReclaimedBytes())))
+	return nil
+}
@@ -0,0 +1,32 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/srz-zumix/go-gh-extension/pkg/gh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatByteSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatByteSize(512))
+	assert.Equal(t, "1.5 KiB", formatByteSize(1536))
+	assert.Equal(t, "2.0 GiB", formatByteSize(2<<30))
+}
+
+func TestRenderWorkflowRunCleanupPlan(t *testing.T) {
+	r := NewStringRenderer(nil)
+	plan := &gh.WorkflowRunCleanupPlan{Items: []*gh.WorkflowRunCleanupItem{
+		{Repository: "o/r", Action: gh.WorkflowRunCleanupDeleteRun, RunNumber: 1, Bytes: 1024},
+		{Repository: "o/r", Action: gh.WorkflowRunCleanupDeleteArtifact, RunNumber: 2, ArtifactName: "dist", Bytes: 2048},
+	}}
+	assert.NoError(t, r.Renderer.RenderWorkflowRunCleanupPlan(plan, []string{"ACTION", "RUN", "ARTIFACT", "SIZE", "RESULT"}))
+	out := r.Stdout.String()
+	assert.Contains(t, out, "delete-artifact")
+	assert.Contains(t, out, "dist")
+	assert.Contains(t, out, "planned")
+	assert.Contains(t, out, "1 runs, 0 logs and 1 artifacts to delete, reclaiming about 3.0 KiB\n")
+
+	empty := NewStringRenderer(nil)
+	assert.NoError(t, empty.Renderer.RenderWorkflowRunCleanupPlan(&gh.WorkflowRunCleanupPlan{}, nil))
+	assert.Equal(t, "Nothing to clean up.\n", empty.Stdout.String())
+}